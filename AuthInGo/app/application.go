@@ -5,8 +5,10 @@ import (
 	config "AuthInGo/config/env"
 	"AuthInGo/controllers"
 	repo "AuthInGo/db/repositories"
+	"AuthInGo/gateway"
 	"AuthInGo/router"
 	"AuthInGo/services"
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -14,7 +16,12 @@ import (
 
 // Config holds the configuration for the server.
 type Config struct {
-	Addr string // PORT
+	Addr             string // PORT
+	HotelServiceUrl  string
+	ReviewServiceUrl string
+	UpstreamTimeout  time.Duration
+	GatewayConfig    string
+	GatewayPoll      time.Duration
 }
 
 type Application struct {
@@ -27,7 +34,12 @@ func NewConfig() Config {
 	port := config.GetString("PORT", ":8080")
 
 	return Config{
-		Addr: port,
+		Addr:             port,
+		HotelServiceUrl:  config.GetString("HOTEL_SERVICE_URL", "http://localhost:3000"),
+		ReviewServiceUrl: config.GetString("REVIEW_SERVICE_URL", "http://localhost:8081"),
+		UpstreamTimeout:  time.Duration(config.GetInt("UPSTREAM_TIMEOUT_MS", 2000)) * time.Millisecond,
+		GatewayConfig:    config.GetString("GATEWAY_CONFIG_PATH", "gateway.json"),
+		GatewayPoll:      time.Duration(config.GetInt("GATEWAY_CONFIG_POLL_SECONDS", 5)) * time.Second,
 	}
 }
 
@@ -51,16 +63,34 @@ func (app *Application) Run() error {
 	rr := repo.NewRoleRepository(db)
 	rpr := repo.NewRolePermissionRepository(db)
 	urr := repo.NewUserRoleRepository(db)
-	us := services.NewUserService(ur)
+	hsr := repo.NewHotelStaffRepository(db)
+	us := services.NewUserService(ur, urr, hsr)
 	rs := services.NewRoleService(rr, rpr, urr)
 	uc := controllers.NewUserController(us)
 	rc := controllers.NewRoleController(rs)
+	hps := services.NewHotelPageService(services.HotelPageConfig{
+		HotelServiceUrl:  app.Config.HotelServiceUrl,
+		ReviewServiceUrl: app.Config.ReviewServiceUrl,
+		Timeout:          app.Config.UpstreamTimeout,
+	})
+	hpc := controllers.NewHotelPageController(hps)
+
+	table, err := gateway.NewTable(app.Config.GatewayConfig)
+	if err != nil {
+		fmt.Println("Error loading gateway config:", err)
+		return err
+	}
+	go table.Watch(context.Background(), app.Config.GatewayPoll)
+	gc := controllers.NewGatewayController(table)
+
 	uRouter := router.NewUserRouter(uc)
 	rRouter := router.NewRoleRouter(rc)
+	hpRouter := router.NewHotelPageRouter(hpc)
+	gRouter := router.NewGatewayRouter(gc, table)
 
 	server := &http.Server{
 		Addr:         app.Config.Addr,
-		Handler:      router.SetupRouter(uRouter, rRouter),
+		Handler:      router.SetupRouter(uRouter, rRouter, hpRouter, gRouter),
 		ReadTimeout:  10 * time.Second, // Set read timeout to 10 seconds
 		WriteTimeout: 10 * time.Second, // Set write timeout to 10 seconds
 	}
@@ -18,7 +18,7 @@ func SetupDB() (*sql.DB, error) {
 	cfg.Addr = env.GetString("DB_ADDR", "127.0.0.1:3306")
 	cfg.DBName = env.GetString("DBName", "auth_dev")
 
-	fmt.Println("Connecting to database:", cfg.DBName, cfg.FormatDSN())
+	fmt.Println("Connecting to database:", cfg.DBName, cfg.Addr)
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"AuthInGo/utils"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GatewayConfig is the reloadable route table and middleware policy set of the gateway.
+type GatewayConfig struct {
+	Routes  []RouteConfig  `json:"routes"`
+	Logging *LoggingConfig `json:"logging,omitempty"`
+}
+
+// LoggingConfig tunes log redaction. RedactFields are masked in addition to the built-in sensitive fields.
+type LoggingConfig struct {
+	RedactFields []string `json:"redact_fields,omitempty"`
+	MaskEmails   *bool    `json:"mask_emails,omitempty"`
+	MaskTokens   *bool    `json:"mask_tokens,omitempty"`
+	Mask         string   `json:"mask,omitempty"`
+}
+
+type RouteConfig struct {
+	Name        string           `json:"name"`
+	PathPrefix  string           `json:"path_prefix"`
+	Target      string           `json:"target,omitempty"`
+	Sticky      bool             `json:"sticky,omitempty"`
+	Variants    []VariantConfig  `json:"variants,omitempty"`
+	RequireAuth bool             `json:"require_auth,omitempty"`
+	RateLimit   *RateLimitConfig `json:"rate_limit,omitempty"`
+	Shadow      *ShadowConfig    `json:"shadow,omitempty"`
+}
+
+type VariantConfig struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+// ShadowConfig mirrors a sample of a route's traffic to a candidate upstream for comparison.
+type ShadowConfig struct {
+	Target        string   `json:"target"`
+	SamplePercent float64  `json:"sample_percent"`
+	Methods       []string `json:"methods,omitempty"`
+	TimeoutMs     int      `json:"timeout_ms,omitempty"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	Burst             int `json:"burst"`
+}
+
+// Load reads and validates the gateway config file at path.
+func Load(path string) (*GatewayConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg := &GatewayConfig{}
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing gateway config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *GatewayConfig) Validate() error {
+	names := map[string]bool{}
+	prefixes := map[string]bool{}
+
+	for i, route := range c.Routes {
+		if route.Name == "" {
+			return fmt.Errorf("route %d: name is required", i)
+		}
+		if names[route.Name] {
+			return fmt.Errorf("route %s: duplicate name", route.Name)
+		}
+		names[route.Name] = true
+
+		if !strings.HasPrefix(route.PathPrefix, "/") || strings.HasSuffix(route.PathPrefix, "/") {
+			return fmt.Errorf("route %s: path_prefix must start with / and must not end with /", route.Name)
+		}
+		if prefixes[route.PathPrefix] {
+			return fmt.Errorf("route %s: duplicate path_prefix %s", route.Name, route.PathPrefix)
+		}
+		prefixes[route.PathPrefix] = true
+
+		if (route.Target == "") == (len(route.Variants) == 0) {
+			return fmt.Errorf("route %s: exactly one of target or variants is required", route.Name)
+		}
+
+		if route.Target != "" {
+			if err := validateTarget(route.Target); err != nil {
+				return fmt.Errorf("route %s: %w", route.Name, err)
+			}
+		}
+
+		total := 0
+		for _, variant := range route.Variants {
+			if variant.Name == "" {
+				return fmt.Errorf("route %s: variant name is required", route.Name)
+			}
+			if err := validateTarget(variant.Target); err != nil {
+				return fmt.Errorf("route %s variant %s: %w", route.Name, variant.Name, err)
+			}
+			if variant.Weight < 0 {
+				return fmt.Errorf("route %s variant %s: weight must not be negative", route.Name, variant.Name)
+			}
+			total += variant.Weight
+		}
+		if len(route.Variants) > 0 && total != 100 {
+			return fmt.Errorf("route %s: variant weights must add up to 100, got %d", route.Name, total)
+		}
+
+		if route.RateLimit != nil && (route.RateLimit.RequestsPerMinute <= 0 || route.RateLimit.Burst <= 0) {
+			return fmt.Errorf("route %s: rate_limit requires positive requests_per_minute and burst", route.Name)
+		}
+
+		if route.Shadow != nil {
+			if err := validateTarget(route.Shadow.Target); err != nil {
+				return fmt.Errorf("route %s shadow: %w", route.Name, err)
+			}
+			if route.Shadow.SamplePercent <= 0 || route.Shadow.SamplePercent > 100 {
+				return fmt.Errorf("route %s shadow: sample_percent must be in (0, 100]", route.Name)
+			}
+			if route.Shadow.TimeoutMs < 0 {
+				return fmt.Errorf("route %s shadow: timeout_ms must not be negative", route.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RedactionConfig merges the logging section with the default redaction rules.
+func (c *GatewayConfig) RedactionConfig() utils.RedactionConfig {
+	cfg := utils.DefaultRedactionConfig
+	cfg.Fields = append([]string{}, utils.DefaultRedactionConfig.Fields...)
+	if c.Logging == nil {
+		return cfg
+	}
+
+	cfg.Fields = append(cfg.Fields, c.Logging.RedactFields...)
+	if c.Logging.MaskEmails != nil {
+		cfg.MaskEmails = *c.Logging.MaskEmails
+	}
+	if c.Logging.MaskTokens != nil {
+		cfg.MaskTokens = *c.Logging.MaskTokens
+	}
+	if c.Logging.Mask != "" {
+		cfg.Mask = c.Logging.Mask
+	}
+	return cfg
+}
+
+func validateTarget(target string) error {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid target %s: %w", target, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("invalid target %s: must be an absolute http(s) URL", target)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"AuthInGo/dto"
+	"AuthInGo/gateway"
+	"AuthInGo/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GatewayController struct {
+	Table *gateway.Table
+}
+
+func NewGatewayController(_table *gateway.Table) *GatewayController {
+	return &GatewayController{
+		Table: _table,
+	}
+}
+
+func (gc *GatewayController) GetConfigStatus(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Gateway config fetched successfully", gc.Table.Status())
+}
+
+func (gc *GatewayController) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if err := gc.Table.Reload(); err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusUnprocessableEntity, "Gateway config rejected, last good config kept", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Gateway config reloaded successfully", gc.Table.Status())
+}
+
+func (gc *GatewayController) GetCanaryStats(w http.ResponseWriter, r *http.Request) {
+	stats := []utils.CanaryRouteStats{}
+	for _, route := range gc.Table.Canaries().All() {
+		stats = append(stats, route.Stats())
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Canary stats fetched successfully", stats)
+}
+
+func (gc *GatewayController) GetShadowSummary(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Shadow traffic summary fetched successfully", gc.Table.ShadowSummaries())
+}
+
+func (gc *GatewayController) UpdateCanaryWeights(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	// Weights set here survive config reloads until the route's variants or configured weights change
+	route := gc.Table.Canaries().Get(name)
+	if route == nil {
+		utils.WriteJsonErrorResponse(w, http.StatusNotFound, "Canary route not found", fmt.Errorf("canary route %s not found", name))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.UpdateCanaryWeightsRequestDTO)
+
+	if err := route.SetWeights(payload.Weights); err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Failed to update canary weights", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Canary weights updated successfully", route.Stats())
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"AuthInGo/services"
+	"AuthInGo/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HotelPageController struct {
+	HotelPageService services.HotelPageService
+}
+
+func NewHotelPageController(_hotelPageService services.HotelPageService) *HotelPageController {
+	return &HotelPageController{
+		HotelPageService: _hotelPageService,
+	}
+}
+
+func (hc *HotelPageController) GetHotelPage(w http.ResponseWriter, r *http.Request) {
+	hotelId := chi.URLParam(r, "id")
+	if hotelId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Hotel ID is required", fmt.Errorf("missing hotel ID"))
+		return
+	}
+
+	// Only identity headers are forwarded to the upstream services
+	headers := http.Header{}
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		headers.Set("Authorization", auth)
+	}
+	if userId, ok := r.Context().Value("userID").(string); ok {
+		headers.Set("X-User-ID", userId)
+	}
+
+	page := hc.HotelPageService.GetHotelPage(r.Context(), hotelId, headers)
+
+	if page.Hotel.Status == http.StatusNotFound {
+		utils.WriteJsonErrorResponse(w, http.StatusNotFound, "Hotel not found", fmt.Errorf("hotel with ID %s not found", hotelId))
+		return
+	}
+
+	if page.Hotel.Error != "" && page.Reviews.Error != "" && page.Availability.Error != "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadGateway, "All upstream services failed", fmt.Errorf("hotel: %s", page.Hotel.Error))
+		return
+	}
+
+	message := "Hotel page fetched successfully"
+	if page.Partial {
+		message = "Hotel page fetched with partial data"
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, message, page)
+}
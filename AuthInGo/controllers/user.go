@@ -4,8 +4,13 @@ import (
 	"AuthInGo/dto"
 	"AuthInGo/services"
 	"AuthInGo/utils"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type UserController struct {
@@ -38,17 +43,37 @@ func (uc *UserController) GetUserById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if user == nil {
-		utils.WriteJsonErrorResponse(w, http.StatusNotFound, "User not found", fmt.Errorf("user with ID %d not found", userId))
+		utils.WriteJsonErrorResponse(w, http.StatusNotFound, "User not found", fmt.Errorf("user with ID %s not found", userId))
 		return
 	}
 	utils.WriteJsonSuccessResponse(w, http.StatusOK, "User fetched successfully", user)
-	fmt.Println("User fetched successfully:", user)
+	utils.LogRedacted("User fetched successfully:", user)
+}
+
+// GetUserContact serves the internal contact lookup of other services.
+func (uc *UserController) GetUserContact(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := parseUserHotelParams(w, r, false)
+	if !ok {
+		return
+	}
+
+	user, err := uc.UserService.GetUserById(strconv.FormatInt(userId, 10))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch user", err)
+		return
+	}
+	if user == nil {
+		utils.WriteJsonErrorResponse(w, http.StatusNotFound, "User not found", fmt.Errorf("user with ID %d not found", userId))
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "User fetched successfully", dto.UserContactDTO{Id: user.Id, Email: user.Email})
 }
 
 func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 	payload := r.Context().Value("payload").(dto.CreateUserRequestDTO)
 
-	fmt.Println("Payload received:", payload)
+	utils.LogRedacted("Payload received:", payload)
 
 	user, err := uc.UserService.CreateUser(&payload)
 
@@ -58,7 +83,7 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.WriteJsonSuccessResponse(w, http.StatusCreated, "User created successfully", user)
-	fmt.Println("User created successfully:", user)
+	utils.LogRedacted("User created successfully:", user)
 }
 
 func (uc *UserController) LoginUser(w http.ResponseWriter, r *http.Request) {
@@ -67,7 +92,7 @@ func (uc *UserController) LoginUser(w http.ResponseWriter, r *http.Request) {
 
 	payload := r.Context().Value("payload").(dto.LoginUserRequestDTO)
 
-	fmt.Println("Payload received:", payload)
+	utils.LogRedacted("Payload received:", payload)
 
 	jwtToken, err := uc.UserService.LoginUser(&payload)
 
@@ -79,3 +104,65 @@ func (uc *UserController) LoginUser(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJsonSuccessResponse(w, http.StatusOK, "User logged in successfully", jwtToken)
 
 }
+
+// parseUserHotelParams reads the user and hotel IDs of the hotel staff routes, writing a 400 when one is invalid.
+func parseUserHotelParams(w http.ResponseWriter, r *http.Request, withHotel bool) (int64, int64, bool) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "userId"), 10, 64)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid user ID", err)
+		return 0, 0, false
+	}
+	if !withHotel {
+		return userId, 0, true
+	}
+
+	hotelId, err := strconv.ParseInt(chi.URLParam(r, "hotelId"), 10, 64)
+	if err != nil || hotelId <= 0 {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid hotel ID", fmt.Errorf("invalid hotel ID %q", chi.URLParam(r, "hotelId")))
+		return 0, 0, false
+	}
+	return userId, hotelId, true
+}
+
+func (uc *UserController) GetUserHotels(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := parseUserHotelParams(w, r, false)
+	if !ok {
+		return
+	}
+
+	hotelIds, err := uc.UserService.GetUserHotels(userId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch user hotels", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "User hotels fetched successfully", hotelIds)
+}
+
+func (uc *UserController) AssignHotelToUser(w http.ResponseWriter, r *http.Request) {
+	userId, hotelId, ok := parseUserHotelParams(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := uc.UserService.AssignHotelToUser(userId, hotelId); err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to assign hotel to user", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Hotel assigned to user successfully", nil)
+}
+
+func (uc *UserController) RemoveHotelFromUser(w http.ResponseWriter, r *http.Request) {
+	userId, hotelId, ok := parseUserHotelParams(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := uc.UserService.RemoveHotelFromUser(userId, hotelId); err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to remove hotel from user", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Hotel removed from user successfully", nil)
+}
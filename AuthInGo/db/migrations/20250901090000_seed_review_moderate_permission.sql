@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('review:moderate', 'Permission to moderate, edit and delete any review', 'review', 'moderate');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'review:moderate';
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('hotel:respond', 'Permission to respond to reviews of any hotel', 'hotel', 'respond');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'hotel:respond';
+-- +goose StatementEnd
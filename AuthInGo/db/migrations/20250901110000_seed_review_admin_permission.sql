@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('review:admin', 'Permission to restore and permanently delete reviews', 'review', 'admin');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'review:admin';
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS hotel_staff (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    hotel_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_hotel_staff_user_hotel (user_id, hotel_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE permissions SET description = 'Permission to respond to reviews of any hotel, for admins; hosts get hotel:<id>:respond for their assigned hotels'
+WHERE name = 'hotel:respond';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The global permission is reserved for admins
+DELETE rp FROM role_permissions rp
+JOIN roles r ON r.id = rp.role_id
+JOIN permissions p ON p.id = rp.permission_id
+WHERE p.name = 'hotel:respond' AND r.name <> 'admin';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'hotel:respond'
+AND NOT EXISTS (SELECT 1 FROM role_permissions rp WHERE rp.role_id = r.id AND rp.permission_id = p.id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE permissions SET description = 'Permission to respond to reviews of any hotel' WHERE name = 'hotel:respond';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS hotel_staff;
+-- +goose StatementEnd
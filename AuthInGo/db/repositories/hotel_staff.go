@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// HotelStaffRepository records which hotels a user works for. Staff get hotel-scoped permissions for those hotels.
+type HotelStaffRepository interface {
+	GetUserHotels(userId int64) ([]int64, error)
+	AssignHotelToUser(userId int64, hotelId int64) error
+	RemoveHotelFromUser(userId int64, hotelId int64) error
+}
+
+type HotelStaffRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewHotelStaffRepository(_db *sql.DB) HotelStaffRepository {
+	return &HotelStaffRepositoryImpl{
+		db: _db,
+	}
+}
+
+func (h *HotelStaffRepositoryImpl) GetUserHotels(userId int64) ([]int64, error) {
+	query := "SELECT hotel_id FROM hotel_staff WHERE user_id = ? ORDER BY hotel_id"
+	rows, err := h.db.Query(query, userId)
+	if err != nil {
+		fmt.Println("Error fetching user hotels:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	hotelIds := []int64{}
+	for rows.Next() {
+		var hotelId int64
+		if err := rows.Scan(&hotelId); err != nil {
+			return nil, err
+		}
+		hotelIds = append(hotelIds, hotelId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hotelIds, nil
+}
+
+// AssignHotelToUser is a no-op when the user is already assigned to the hotel.
+func (h *HotelStaffRepositoryImpl) AssignHotelToUser(userId int64, hotelId int64) error {
+	query := "INSERT IGNORE INTO hotel_staff (user_id, hotel_id) VALUES (?, ?)"
+	_, err := h.db.Exec(query, userId, hotelId)
+	if err != nil {
+		fmt.Println("Error assigning hotel to user:", err)
+		return err
+	}
+	return nil
+}
+
+func (h *HotelStaffRepositoryImpl) RemoveHotelFromUser(userId int64, hotelId int64) error {
+	query := "DELETE FROM hotel_staff WHERE user_id = ? AND hotel_id = ?"
+	_, err := h.db.Exec(query, userId, hotelId)
+	if err != nil {
+		fmt.Println("Error removing hotel from user:", err)
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"AuthInGo/models"
+	"AuthInGo/utils"
 	"database/sql"
 	"fmt"
 )
@@ -116,7 +117,7 @@ func (u *UserRepositoryImpl) Create(username string, email string, hashedPasswor
 		Email:    email,
 	}
 
-	fmt.Println("User created successfully:", user)
+	utils.LogRedacted("User created successfully:", user)
 
 	return user, nil
 }
@@ -146,7 +147,7 @@ func (u *UserRepositoryImpl) GetByID(id string) (*models.User, error) {
 	}
 
 	// Step 4: Print the user details
-	fmt.Println("User fetched successfully:", user)
+	utils.LogRedacted("User fetched successfully:", user)
 
 	return user, nil
 }
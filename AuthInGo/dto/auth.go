@@ -10,3 +10,9 @@ type CreateUserRequestDTO struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
 }
+
+// UserContactDTO is how other services reach a user, e.g. ReviewService emailing a guest.
+type UserContactDTO struct {
+	Id    int64  `json:"id"`
+	Email string `json:"email"`
+}
@@ -0,0 +1,5 @@
+package dto
+
+type UpdateCanaryWeightsRequestDTO struct {
+	Weights map[string]int `json:"weights" validate:"required,min=1,dive,min=0,max=100"`
+}
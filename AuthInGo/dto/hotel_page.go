@@ -0,0 +1,18 @@
+package dto
+
+import "encoding/json"
+
+type UpstreamSectionDTO struct {
+	Status    int             `json:"status"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	LatencyMs int64           `json:"latency_ms"`
+}
+
+type HotelPageResponseDTO struct {
+	HotelId      string              `json:"hotel_id"`
+	Hotel        *UpstreamSectionDTO `json:"hotel"`
+	Reviews      *UpstreamSectionDTO `json:"reviews"`
+	Availability *UpstreamSectionDTO `json:"availability"`
+	Partial      bool                `json:"partial"`
+}
@@ -0,0 +1,222 @@
+package gateway
+
+import (
+	gatewayConfig "AuthInGo/config/gateway"
+	"AuthInGo/middlewares"
+	"AuthInGo/utils"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/time/rate"
+)
+
+// Table is the live gateway route table. Reloads build a complete new snapshot and swap it in atomically,
+// so requests already being served keep using the snapshot they started with.
+type Table struct {
+	path      string
+	reloadMu  sync.Mutex
+	current   atomic.Pointer[snapshot]
+	lastError atomic.Value // string
+}
+
+type snapshot struct {
+	config   *gatewayConfig.GatewayConfig
+	handler  http.Handler
+	canaries *utils.CanaryRegistry
+	shadows  map[string]*utils.ShadowMirror
+	limiters map[string]*rate.Limiter
+	version  int
+	loadedAt time.Time
+}
+
+type TableStatus struct {
+	ConfigPath string                      `json:"config_path"`
+	Version    int                         `json:"version"`
+	LoadedAt   time.Time                   `json:"loaded_at"`
+	Routes     []gatewayConfig.RouteConfig `json:"routes"`
+	LastError  string                      `json:"last_error,omitempty"`
+}
+
+// NewTable loads the config file at path. The initial load must succeed.
+func NewTable(path string) (*Table, error) {
+	t := &Table{path: path}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload validates the config file and swaps in a new route table. On failure the last good table stays active.
+func (t *Table) Reload() error {
+	t.reloadMu.Lock()
+	defer t.reloadMu.Unlock()
+
+	cfg, err := gatewayConfig.Load(t.path)
+	if err != nil {
+		fmt.Println("Gateway config reload rejected, keeping last good config:", err)
+		t.lastError.Store(err.Error())
+		return err
+	}
+
+	previous := t.current.Load()
+	next, err := buildSnapshot(cfg, previous)
+	if err != nil {
+		fmt.Println("Gateway config reload rejected, keeping last good config:", err)
+		t.lastError.Store(err.Error())
+		return err
+	}
+
+	t.current.Store(next)
+	t.lastError.Store("")
+	utils.SetRedactionConfig(cfg.RedactionConfig())
+	fmt.Println("Gateway config loaded, version:", next.version, "routes:", len(cfg.Routes))
+	return nil
+}
+
+func (t *Table) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.current.Load().handler.ServeHTTP(w, r)
+}
+
+func (t *Table) Canaries() *utils.CanaryRegistry {
+	return t.current.Load().canaries
+}
+
+// ShadowSummaries returns the comparison summary of every route that currently mirrors traffic.
+func (t *Table) ShadowSummaries() []utils.ShadowSummary {
+	s := t.current.Load()
+	summaries := []utils.ShadowSummary{}
+	for _, route := range s.config.Routes {
+		if mirror, ok := s.shadows[route.Name]; ok {
+			summaries = append(summaries, mirror.Stats.Summary(route.Name))
+		}
+	}
+	return summaries
+}
+
+func (t *Table) Status() TableStatus {
+	s := t.current.Load()
+	lastError, _ := t.lastError.Load().(string)
+	return TableStatus{
+		ConfigPath: t.path,
+		Version:    s.version,
+		LoadedAt:   s.loadedAt,
+		Routes:     s.config.Routes,
+		LastError:  lastError,
+	}
+}
+
+func buildSnapshot(cfg *gatewayConfig.GatewayConfig, previous *snapshot) (*snapshot, error) {
+	mux := chi.NewRouter()
+	canaries := utils.NewCanaryRegistry()
+	shadows := map[string]*utils.ShadowMirror{}
+	limiters := map[string]*rate.Limiter{}
+
+	for _, route := range cfg.Routes {
+		var handler http.Handler
+
+		if len(route.Variants) > 0 {
+			variants := make([]utils.CanaryVariantConfig, 0, len(route.Variants))
+			for _, v := range route.Variants {
+				variants = append(variants, utils.CanaryVariantConfig{Name: v.Name, Target: v.Target, Weight: v.Weight})
+			}
+
+			canary, err := utils.NewCanaryRoute(route.Name, route.PathPrefix, route.Sticky, variants)
+			if err != nil {
+				return nil, err
+			}
+			if previous != nil {
+				canary.InheritStats(previous.canaries.Get(route.Name))
+				canary.InheritWeights(previous.canaries.Get(route.Name))
+			}
+			canaries.Add(canary)
+			handler = canary
+		} else {
+			proxy := utils.ProxyToService(route.Target, route.PathPrefix)
+			if proxy == nil {
+				return nil, fmt.Errorf("route %s: invalid target %s", route.Name, route.Target)
+			}
+			handler = proxy
+		}
+
+		if route.Shadow != nil {
+			// Keep the comparison history when the shadow target is unchanged
+			var stats *utils.ShadowStats
+			if previous != nil {
+				if old, ok := previous.shadows[route.Name]; ok && old.Stats.Target == route.Shadow.Target {
+					stats = old.Stats
+				}
+			}
+
+			timeout := time.Duration(route.Shadow.TimeoutMs) * time.Millisecond
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+
+			mirror, err := utils.NewShadowMirror(route.Name, utils.ShadowConfig{
+				Target:        route.Shadow.Target,
+				PathPrefix:    route.PathPrefix,
+				SamplePercent: route.Shadow.SamplePercent,
+				Methods:       route.Shadow.Methods,
+				Timeout:       timeout,
+			}, stats)
+			if err != nil {
+				return nil, err
+			}
+			shadows[route.Name] = mirror
+			handler = mirror.Wrap(handler)
+		}
+
+		var policies []func(http.Handler) http.Handler
+		if route.RequireAuth {
+			policies = append(policies, middlewares.JWTAuthMiddleware)
+		}
+		if route.RateLimit != nil {
+			limiter := routeLimiter(route, previous)
+			limiters[route.Name] = limiter
+			policies = append(policies, middlewares.NewRateLimitMiddleware(limiter))
+		}
+
+		routeName := route.Name
+		mux.With(policies...).Handle(route.PathPrefix+"/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			utils.SetAccessLogRoute(r.Context(), routeName)
+			handler.ServeHTTP(w, r)
+		}))
+	}
+
+	version := 1
+	if previous != nil {
+		version = previous.version + 1
+	}
+
+	return &snapshot{
+		config:   cfg,
+		handler:  mux,
+		canaries: canaries,
+		shadows:  shadows,
+		limiters: limiters,
+		version:  version,
+		loadedAt: time.Now(),
+	}, nil
+}
+
+// routeLimiter reuses the route's bucket from the previous snapshot, so a reload does not refill it. A changed
+// limit is applied to the existing bucket.
+func routeLimiter(route gatewayConfig.RouteConfig, previous *snapshot) *rate.Limiter {
+	limit := rate.Limit(float64(route.RateLimit.RequestsPerMinute) / 60)
+	if previous != nil {
+		if limiter, ok := previous.limiters[route.Name]; ok {
+			if limiter.Limit() != limit {
+				limiter.SetLimit(limit)
+			}
+			if limiter.Burst() != route.RateLimit.Burst {
+				limiter.SetBurst(route.RateLimit.Burst)
+			}
+			return limiter
+		}
+	}
+	return middlewares.NewRouteLimiter(route.RateLimit.RequestsPerMinute, route.RateLimit.Burst)
+}
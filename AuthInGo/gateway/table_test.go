@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upstream answers every request with its name.
+func upstream(t *testing.T, name string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, name)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func writeConfig(t *testing.T, path string, config string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func serveTable(table *Table, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	table.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func routeConfig(target string, rateLimit string) string {
+	return `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","target":"` + target + `"` + rateLimit + `}]}`
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	blue := upstream(t, "blue")
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"malformed json", `{"routes":[`},
+		{"unknown field", `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","target":"` + blue + `","timeout":5}]}`},
+		{"missing name", `{"routes":[{"path_prefix":"/api/v1/hotels","target":"` + blue + `"}]}`},
+		{"prefix with trailing slash", `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels/","target":"` + blue + `"}]}`},
+		{"duplicate prefix", `{"routes":[{"name":"a","path_prefix":"/a","target":"` + blue + `"},{"name":"b","path_prefix":"/a","target":"` + blue + `"}]}`},
+		{"target and variants", `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","target":"` + blue + `","variants":[{"name":"v1","target":"` + blue + `","weight":100}]}]}`},
+		{"relative target", routeConfig("hotel-service:3000", "")},
+		{"variant weights below 100", `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","variants":[{"name":"v1","target":"` + blue + `","weight":60}]}]}`},
+		{"zero rate limit", routeConfig(blue, `,"rate_limit":{"requests_per_minute":0,"burst":1}`)},
+		{"shadow sample above 100", `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","target":"` + blue + `","shadow":{"target":"` + blue + `","sample_percent":150}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "gateway.json")
+			writeConfig(t, path, routeConfig(blue, ""))
+			table, err := NewTable(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			writeConfig(t, path, tt.config)
+			if err := table.Reload(); err == nil {
+				t.Fatal("expected the reload to be rejected")
+			}
+
+			status := table.Status()
+			if status.Version != 1 || status.LastError == "" {
+				t.Errorf("status version %d last error %q, want version 1 and the error", status.Version, status.LastError)
+			}
+			if code, body := serveTable(table, "/api/v1/hotels/1"); code != http.StatusOK || body != "blue" {
+				t.Errorf("served %d %q, want the last good config", code, body)
+			}
+		})
+	}
+}
+
+func TestNewTableRejectsInvalidInitialConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.json")
+	writeConfig(t, path, routeConfig("hotel-service:3000", ""))
+
+	if _, err := NewTable(path); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestReloadSwapsRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.json")
+	writeConfig(t, path, routeConfig(upstream(t, "blue"), ""))
+	table, err := NewTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, path, routeConfig(upstream(t, "green"), ""))
+	if err := table.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, body := serveTable(table, "/api/v1/hotels/1"); code != http.StatusOK || body != "green" {
+		t.Errorf("served %d %q, want the new target", code, body)
+	}
+	if status := table.Status(); status.Version != 2 || status.LastError != "" {
+		t.Errorf("status version %d last error %q, want version 2 without error", status.Version, status.LastError)
+	}
+}
+
+func TestReloadKeepsRateLimiterState(t *testing.T) {
+	blue := upstream(t, "blue")
+	path := filepath.Join(t.TempDir(), "gateway.json")
+	writeConfig(t, path, routeConfig(blue, `,"rate_limit":{"requests_per_minute":1,"burst":1}`))
+	table, err := NewTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code, _ := serveTable(table, "/api/v1/hotels/1"); code != http.StatusOK {
+		t.Fatalf("first request got %d", code)
+	}
+	if code, _ := serveTable(table, "/api/v1/hotels/1"); code != http.StatusTooManyRequests {
+		t.Fatalf("second request got %d, want 429", code)
+	}
+
+	// Swapping the target must not hand out a fresh bucket
+	writeConfig(t, path, routeConfig(upstream(t, "green"), `,"rate_limit":{"requests_per_minute":1,"burst":1}`))
+	if err := table.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serveTable(table, "/api/v1/hotels/1"); code != http.StatusTooManyRequests {
+		t.Errorf("request after reload got %d, want the bucket to stay empty", code)
+	}
+
+	// Dropping the limit from the route removes the policy
+	writeConfig(t, path, routeConfig(blue, ""))
+	if err := table.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serveTable(table, "/api/v1/hotels/1"); code != http.StatusOK {
+		t.Errorf("request without a limit got %d", code)
+	}
+}
+
+func TestReloadKeepsCanaryWeightsAndStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.json")
+	config := `{"routes":[{"name":"hotels","path_prefix":"/api/v1/hotels","variants":[` +
+		`{"name":"stable","target":"` + upstream(t, "stable") + `","weight":100},` +
+		`{"name":"canary","target":"` + upstream(t, "canary") + `","weight":0}]}]}`
+	writeConfig(t, path, config)
+	table, err := NewTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serveTable(table, "/api/v1/hotels/1")
+	if err := table.Canaries().Get("hotels").SetWeights(map[string]int{"stable": 0, "canary": 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, body := serveTable(table, "/api/v1/hotels/1"); body != "canary" {
+		t.Errorf("served by %q after reload, want the manually weighted canary", body)
+	}
+	stats := table.Canaries().Get("hotels").Stats()
+	if stats.Variants[0].Requests != 1 || stats.Variants[1].Requests != 1 {
+		t.Errorf("request counters %+v, want one request per variant", stats.Variants)
+	}
+}
+
+func TestWatchReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.json")
+	writeConfig(t, path, routeConfig(upstream(t, "blue"), ""))
+	table, err := NewTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go table.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	writeConfig(t, path, routeConfig(upstream(t, "green"), ""))
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for table.Status().Version < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("config change was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, body := serveTable(table, "/api/v1/hotels/1"); !strings.Contains(body, "green") {
+		t.Errorf("served %q, want the reloaded target", body)
+	}
+}
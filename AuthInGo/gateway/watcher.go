@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Watch reloads the table on SIGHUP and whenever the config file's modification time changes.
+// It blocks until ctx is cancelled.
+func (t *Table) Watch(ctx context.Context, pollInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastModified := t.modTime()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			fmt.Println("Received SIGHUP, reloading gateway config")
+			lastModified = t.modTime()
+			t.reloadAndReport()
+		case <-ticker.C:
+			modified := t.modTime()
+			if modified.IsZero() || modified.Equal(lastModified) {
+				continue
+			}
+			lastModified = modified
+			fmt.Println("Gateway config file changed, reloading")
+			t.reloadAndReport()
+		}
+	}
+}
+
+// reloadAndReport reloads the table and says which version stays active when the new config is rejected.
+func (t *Table) reloadAndReport() {
+	if err := t.Reload(); err != nil {
+		fmt.Println("Gateway config reload failed, still serving version", t.Status().Version, ":", err)
+	}
+}
+
+func (t *Table) modTime() time.Time {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"AuthInGo/utils"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AccessLogMiddleware writes one structured, redacted access log line per request.
+func AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		entry := &utils.AccessLogEntry{
+			Time:       start,
+			Method:     r.Method,
+			Path:       utils.RedactString(r.URL.Path),
+			Query:      utils.RedactQuery(r.URL.RawQuery),
+			Headers:    utils.RedactHeaders(r.Header),
+			RemoteAddr: r.RemoteAddr,
+		}
+
+		recorder := &utils.StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(utils.WithAccessLogEntry(r.Context(), entry)))
+
+		if entry.Route == "" {
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				entry.Route = rctx.RoutePattern()
+			}
+		}
+		entry.Status = recorder.Status
+		entry.Bytes = recorder.Bytes
+		entry.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+
+		utils.WriteAccessLog(entry)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	dbConfig "AuthInGo/config/db"
 	env "AuthInGo/config/env"
 	repo "AuthInGo/db/repositories"
+	"AuthInGo/utils"
 	"context"
 	"fmt"
 	"net/http"
@@ -37,7 +38,7 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 
 		_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
 			return []byte(env.GetString("JWT_SECRET", "TOKEN")), nil
-		})
+		}, jwt.WithExpirationRequired())
 
 		if err != nil {
 			http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
@@ -53,9 +54,12 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		fmt.Println("Authenticated user ID:", int64(userId), "Email:", email)
+		utils.LogRedacted("Authenticated user ID:", int64(userId), "Email:", email)
+
+		userIdStr := strconv.FormatFloat(userId, 'f', 0, 64)
+		utils.SetAccessLogUser(r.Context(), userIdStr)
 
-		ctx := context.WithValue(r.Context(), "userID", strconv.FormatFloat(userId, 'f', 0, 64))
+		ctx := context.WithValue(r.Context(), "userID", userIdStr)
 		ctx = context.WithValue(ctx, "email", email)
 		next.ServeHTTP(w, r.WithContext(ctx))
 
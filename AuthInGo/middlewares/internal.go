@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	env "AuthInGo/config/env"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+)
+
+// InternalTokenHeader carries the shared INTERNAL_SERVICE_TOKEN on calls from other services.
+const InternalTokenHeader = "X-Internal-Token"
+
+// RequireInternalToken lets only other services through, by the INTERNAL_SERVICE_TOKEN they send in the
+// X-Internal-Token header. Requests are rejected when no token is configured.
+func RequireInternalToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := env.GetString("INTERNAL_SERVICE_TOKEN", "")
+		if expected == "" {
+			fmt.Println("INTERNAL_SERVICE_TOKEN is not set, rejecting internal request")
+			http.Error(w, "Internal authentication is not configured", http.StatusUnauthorized)
+			return
+		}
+
+		// Compare digests so the comparison takes the same time whatever the lengths
+		provided := sha256.Sum256([]byte(r.Header.Get(InternalTokenHeader)))
+		want := sha256.Sum256([]byte(expected))
+		if subtle.ConstantTimeCompare(provided[:], want[:]) != 1 {
+			http.Error(w, "Invalid internal service token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireInternalToken(t *testing.T) {
+	handler := RequireInternalToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		configured string
+		sent       string
+		want       int
+	}{
+		{"matching token", "secret", "secret", http.StatusNoContent},
+		{"wrong token", "secret", "guess", http.StatusUnauthorized},
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"no token configured", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INTERNAL_SERVICE_TOKEN", tt.configured)
+			req := httptest.NewRequest(http.MethodGet, "/internal/users/7", nil)
+			if tt.sent != "" {
+				req.Header.Set(InternalTokenHeader, tt.sent)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -18,3 +18,21 @@ func RateLimitMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// NewRouteLimiter creates the token bucket of a per-route gateway policy.
+func NewRouteLimiter(requestsPerMinute int, burst int) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(float64(requestsPerMinute)/60), burst)
+}
+
+// NewRateLimitMiddleware throttles requests with the given bucket, which may be shared across route table reloads.
+func NewRateLimitMiddleware(routeLimiter *rate.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !routeLimiter.Allow() {
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"AuthInGo/dto"
 	"AuthInGo/utils"
 	"context"
-	"fmt"
 	"net/http"
 )
 
@@ -24,7 +23,7 @@ func UserLoginRequestValidator(next http.Handler) http.Handler {
 			return
 		}
 
-		fmt.Println("Payload received for login:", payload)
+		utils.LogRedacted("Payload received for login:", payload)
 
 		ctx := context.WithValue(r.Context(), "payload", payload) // Create a new context with the payload
 
@@ -141,3 +140,25 @@ func RemovePermissionRequestValidator(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func UpdateCanaryWeightsRequestValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload dto.UpdateCanaryWeightsRequestDTO
+
+		// Read and decode the JSON body into the payload
+		if err := utils.ReadJsonBody(r, &payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+
+		// Validate the payload using the Validator instance
+		if err := utils.Validator.Struct(payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Validation failed", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "payload", payload)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"AuthInGo/controllers"
+	"AuthInGo/gateway"
+	"AuthInGo/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GatewayRouter struct {
+	gatewayController *controllers.GatewayController
+	table             *gateway.Table
+}
+
+func NewGatewayRouter(_gatewayController *controllers.GatewayController, _table *gateway.Table) Router {
+	return &GatewayRouter{
+		gatewayController: _gatewayController,
+		table:             _table,
+	}
+}
+
+func (gr *GatewayRouter) Register(r chi.Router) {
+	// Proxy routes come from the reloadable gateway config; anything not matched above falls through to the table
+	r.Handle("/*", gr.table)
+
+	// Admin operations for the gateway config
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Get("/gateway/config", gr.gatewayController.GetConfigStatus)
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Post("/gateway/config/reload", gr.gatewayController.ReloadConfig)
+
+	// Comparison of primary and shadow responses for mirrored routes
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Get("/gateway/shadow", gr.gatewayController.GetShadowSummary)
+
+	// Admin operations for rolling variants forward or back
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Get("/gateway/canaries", gr.gatewayController.GetCanaryStats)
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin"), middlewares.UpdateCanaryWeightsRequestValidator).Put("/gateway/canaries/{name}/weights", gr.gatewayController.UpdateCanaryWeights)
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"AuthInGo/controllers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HotelPageRouter struct {
+	hotelPageController *controllers.HotelPageController
+}
+
+func NewHotelPageRouter(_hotelPageController *controllers.HotelPageController) Router {
+	return &HotelPageRouter{
+		hotelPageController: _hotelPageController,
+	}
+}
+
+func (hr *HotelPageRouter) Register(r chi.Router) {
+	// Composition endpoint aggregating the hotel and its room availability from HotelService and reviews from ReviewService
+	r.Get("/api/hotels/{id}/page", hr.hotelPageController.GetHotelPage)
+}
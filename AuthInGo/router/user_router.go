@@ -21,4 +21,12 @@ func (ur *UserRouter) Register(r chi.Router) {
 	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAnyRole("user", "admin")).Get("/profile", ur.userController.GetUserById)
 	r.With(middlewares.UserCreateRequestValidator).Post("/signup", ur.userController.CreateUser)
 	r.With(middlewares.UserLoginRequestValidator).Post("/login", ur.userController.LoginUser)
+
+	// Contact lookup for other services, e.g. ReviewService emailing a guest about a host response
+	r.With(middlewares.RequireInternalToken).Get("/internal/users/{userId}", ur.userController.GetUserContact)
+
+	// Hotel staff assignments, issued as hotel:<id>:respond permissions at login
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Get("/users/{userId}/hotels", ur.userController.GetUserHotels)
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Post("/users/{userId}/hotels/{hotelId}", ur.userController.AssignHotelToUser)
+	r.With(middlewares.JWTAuthMiddleware, middlewares.RequireAllRoles("admin")).Delete("/users/{userId}/hotels/{hotelId}", ur.userController.RemoveHotelFromUser)
 }
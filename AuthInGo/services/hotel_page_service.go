@@ -0,0 +1,97 @@
+package services
+
+import (
+	"AuthInGo/dto"
+	"AuthInGo/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type HotelPageService interface {
+	GetHotelPage(ctx context.Context, hotelId string, headers http.Header) *dto.HotelPageResponseDTO
+}
+
+// HotelPageConfig holds the upstream locations and the per-call timeout used when composing a hotel page.
+type HotelPageConfig struct {
+	HotelServiceUrl  string
+	ReviewServiceUrl string
+	Timeout          time.Duration
+}
+
+type HotelPageServiceImpl struct {
+	config HotelPageConfig
+	client *http.Client
+}
+
+func NewHotelPageService(_config HotelPageConfig) HotelPageService {
+	return &HotelPageServiceImpl{
+		config: _config,
+		client: &http.Client{},
+	}
+}
+
+func (h *HotelPageServiceImpl) GetHotelPage(ctx context.Context, hotelId string, headers http.Header) *dto.HotelPageResponseDTO {
+	fmt.Println("Composing hotel page in HotelPageService for hotel:", hotelId)
+
+	escapedId := url.PathEscape(hotelId)
+	hotelUrl := fmt.Sprintf("%s/api/v1/hotels/%s", h.config.HotelServiceUrl, escapedId)
+	reviewsUrl := fmt.Sprintf("%s/reviews/hotel?hotel_id=%s", h.config.ReviewServiceUrl, url.QueryEscape(hotelId))
+	// Room inventory lives in HotelService; BookingService only stores bookings, without rooms or dates
+	availabilityUrl := fmt.Sprintf("%s/api/v1/hotels/%s/availability", h.config.HotelServiceUrl, escapedId)
+
+	page := &dto.HotelPageResponseDTO{HotelId: hotelId}
+
+	// Fan out to all upstreams concurrently, each call bounded by its own timeout
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		page.Hotel = h.fetchSection(ctx, hotelUrl, headers)
+	}()
+	go func() {
+		defer wg.Done()
+		page.Reviews = h.fetchSection(ctx, reviewsUrl, headers)
+	}()
+	go func() {
+		defer wg.Done()
+		page.Availability = h.fetchSection(ctx, availabilityUrl, headers)
+	}()
+	wg.Wait()
+
+	page.Partial = page.Hotel.Error != "" || page.Reviews.Error != "" || page.Availability.Error != ""
+
+	return page
+}
+
+func (h *HotelPageServiceImpl) fetchSection(ctx context.Context, targetUrl string, headers http.Header) *dto.UpstreamSectionDTO {
+	callCtx, cancel := context.WithTimeout(ctx, h.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := utils.FetchUpstreamJson(callCtx, h.client, targetUrl, headers)
+	section := &dto.UpstreamSectionDTO{
+		Status:    result.Status,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	if result.Err != nil {
+		fmt.Println("Error fetching upstream", targetUrl, ":", result.Err)
+		if callCtx.Err() == context.DeadlineExceeded {
+			section.Status = http.StatusGatewayTimeout
+			section.Error = "upstream timed out"
+		} else {
+			if section.Status == 0 {
+				section.Status = http.StatusBadGateway
+			}
+			section.Error = result.Err.Error()
+		}
+		return section
+	}
+
+	section.Data = utils.UnwrapJsonData(result.Body)
+	return section
+}
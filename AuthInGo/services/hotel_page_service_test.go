@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// hotelPageUpstreams serves the hotel, availability and reviews endpoints. A nil handler answers 500.
+func hotelPageUpstreams(t *testing.T, hotel, availability, reviews http.HandlerFunc) HotelPageConfig {
+	t.Helper()
+	orFail := func(handler http.HandlerFunc) http.HandlerFunc {
+		if handler == nil {
+			return func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }
+		}
+		return handler
+	}
+
+	hotelMux := http.NewServeMux()
+	hotelMux.HandleFunc("/api/v1/hotels/7", orFail(hotel))
+	hotelMux.HandleFunc("/api/v1/hotels/7/availability", orFail(availability))
+	hotelServer := httptest.NewServer(hotelMux)
+	t.Cleanup(hotelServer.Close)
+
+	reviewServer := httptest.NewServer(orFail(reviews))
+	t.Cleanup(reviewServer.Close)
+
+	return HotelPageConfig{HotelServiceUrl: hotelServer.URL, ReviewServiceUrl: reviewServer.URL, Timeout: 100 * time.Millisecond}
+}
+
+func respond(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+func TestGetHotelPageMergesSections(t *testing.T) {
+	config := hotelPageUpstreams(t,
+		respond(`{"data":{"id":7,"name":"Sea View"},"success":true}`),
+		respond(`{"data":{"hotelId":7,"rooms":[]},"success":true}`),
+		respond(`{"data":{"reviews":[]},"success":true}`))
+
+	page := NewHotelPageService(config).GetHotelPage(context.Background(), "7", http.Header{})
+
+	if page.Partial {
+		t.Errorf("page is partial: %+v %+v %+v", page.Hotel, page.Availability, page.Reviews)
+	}
+	if string(page.Hotel.Data) != `{"id":7,"name":"Sea View"}` {
+		t.Errorf("hotel data %s, want the unwrapped data", page.Hotel.Data)
+	}
+	if string(page.Availability.Data) != `{"hotelId":7,"rooms":[]}` {
+		t.Errorf("availability data %s, want the unwrapped data", page.Availability.Data)
+	}
+}
+
+func TestGetHotelPageDegradesPerSection(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}
+	config := hotelPageUpstreams(t, respond(`{"data":{"id":7}}`), nil, slow)
+
+	page := NewHotelPageService(config).GetHotelPage(context.Background(), "7", http.Header{})
+
+	if !page.Partial {
+		t.Error("page with failed sections is not marked partial")
+	}
+	if page.Hotel.Error != "" {
+		t.Errorf("hotel section failed: %s", page.Hotel.Error)
+	}
+	if page.Availability.Error == "" || page.Availability.Status != http.StatusInternalServerError {
+		t.Errorf("availability section %+v, want a 500 error marker", page.Availability)
+	}
+	if page.Reviews.Status != http.StatusGatewayTimeout {
+		t.Errorf("reviews section %+v, want a 504 timeout marker", page.Reviews)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"AuthInGo/models"
 	"AuthInGo/utils"
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -15,15 +16,22 @@ type UserService interface {
 	GetUserById(id string) (*models.User, error)
 	CreateUser(payload *dto.CreateUserRequestDTO) (*models.User, error)
 	LoginUser(payload *dto.LoginUserRequestDTO) (string, error)
+	GetUserHotels(userId int64) ([]int64, error)
+	AssignHotelToUser(userId int64, hotelId int64) error
+	RemoveHotelFromUser(userId int64, hotelId int64) error
 }
 
 type UserServiceImpl struct {
-	userRepository db.UserRepository
+	userRepository       db.UserRepository
+	userRoleRepository   db.UserRoleRepository
+	hotelStaffRepository db.HotelStaffRepository
 }
 
-func NewUserService(_userRepository db.UserRepository) UserService {
+func NewUserService(_userRepository db.UserRepository, _userRoleRepository db.UserRoleRepository, _hotelStaffRepository db.HotelStaffRepository) UserService {
 	return &UserServiceImpl{
-		userRepository: _userRepository,
+		userRepository:       _userRepository,
+		userRoleRepository:   _userRoleRepository,
+		hotelStaffRepository: _hotelStaffRepository,
 	}
 }
 
@@ -85,10 +93,50 @@ func (u *UserServiceImpl) LoginUser(payload *dto.LoginUserRequestDTO) (string, e
 		return "", nil
 	}
 
-	// Step 4. If password matches, print a JWT token, else return error saying password does not match
+	// Step 4. Collect roles and permissions so downstream services can authorize without a DB lookup
+	roles, err := u.userRoleRepository.GetUserRoles(user.Id)
+	if err != nil {
+		fmt.Println("Error fetching user roles:", err)
+		return "", err
+	}
+
+	permissions, err := u.userRoleRepository.GetUserPermissions(user.Id)
+	if err != nil {
+		fmt.Println("Error fetching user permissions:", err)
+		return "", err
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+
+	permissionNames := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permissionNames = append(permissionNames, permission.Name)
+	}
+
+	// Hotel staff may respond to reviews of the hotels they are assigned to, and only those
+	hotelIds, err := u.hotelStaffRepository.GetUserHotels(user.Id)
+	if err != nil {
+		fmt.Println("Error fetching user hotels:", err)
+		return "", err
+	}
+
+	for _, hotelId := range hotelIds {
+		permissionNames = append(permissionNames, fmt.Sprintf("hotel:%d:respond", hotelId))
+	}
+
+	// Step 5. Password matches, so issue a short-lived JWT carrying the user's roles and permissions.
+	// Role and hotel changes reach downstream services once the token expires and the user logs in again
+	issuedAt := time.Now()
 	jwtPayload := jwt.MapClaims{
-		"email": user.Email,
-		"id":    user.Id,
+		"email":       user.Email,
+		"id":          user.Id,
+		"roles":       roleNames,
+		"permissions": permissionNames,
+		"iat":         issuedAt.Unix(),
+		"exp":         issuedAt.Add(time.Duration(env.GetInt("JWT_TTL_MINUTES", 15)) * time.Minute).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtPayload)
@@ -100,7 +148,19 @@ func (u *UserServiceImpl) LoginUser(payload *dto.LoginUserRequestDTO) (string, e
 		return "", err
 	}
 
-	fmt.Println("JWT Token:", tokenString)
+	fmt.Println("JWT token issued for user ID:", user.Id)
 
 	return tokenString, nil
 }
+
+func (u *UserServiceImpl) GetUserHotels(userId int64) ([]int64, error) {
+	return u.hotelStaffRepository.GetUserHotels(userId)
+}
+
+func (u *UserServiceImpl) AssignHotelToUser(userId int64, hotelId int64) error {
+	return u.hotelStaffRepository.AssignHotelToUser(userId, hotelId)
+}
+
+func (u *UserServiceImpl) RemoveHotelFromUser(userId int64, hotelId int64) error {
+	return u.hotelStaffRepository.RemoveHotelFromUser(userId, hotelId)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AccessLogEntry is one structured gateway access log line. Handlers further down the chain
+// fill in the route, upstream and user through the Set* helpers.
+type AccessLogEntry struct {
+	Time       time.Time   `json:"time"`
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Query      string      `json:"query,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Route      string      `json:"route"`
+	Upstream   string      `json:"upstream,omitempty"`
+	Variant    string      `json:"variant,omitempty"`
+	Status     int         `json:"status"`
+	LatencyMs  float64     `json:"latency_ms"`
+	Bytes      int64       `json:"bytes"`
+	UserId     string      `json:"user_id,omitempty"`
+	RemoteAddr string      `json:"remote_addr"`
+}
+
+func WithAccessLogEntry(ctx context.Context, entry *AccessLogEntry) context.Context {
+	return context.WithValue(ctx, "accessLogEntry", entry)
+}
+
+func accessLogEntryFrom(ctx context.Context) *AccessLogEntry {
+	entry, _ := ctx.Value("accessLogEntry").(*AccessLogEntry)
+	return entry
+}
+
+func SetAccessLogRoute(ctx context.Context, route string) {
+	if entry := accessLogEntryFrom(ctx); entry != nil {
+		entry.Route = route
+	}
+}
+
+func SetAccessLogUpstream(ctx context.Context, upstream string) {
+	if entry := accessLogEntryFrom(ctx); entry != nil {
+		entry.Upstream = upstream
+	}
+}
+
+func SetAccessLogVariant(ctx context.Context, variant string) {
+	if entry := accessLogEntryFrom(ctx); entry != nil {
+		entry.Variant = variant
+	}
+}
+
+func SetAccessLogUser(ctx context.Context, userId string) {
+	if entry := accessLogEntryFrom(ctx); entry != nil {
+		entry.UserId = userId
+	}
+}
+
+// WriteAccessLog prints the entry as a single JSON line.
+func WriteAccessLog(entry *AccessLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Println("Error encoding access log entry:", err)
+		return
+	}
+	fmt.Println(string(line))
+}
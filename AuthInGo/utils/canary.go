@@ -0,0 +1,331 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// CanaryVariantHeader lets a client pin a request to a named variant, e.g. "X-Canary-Variant: v2".
+const CanaryVariantHeader = "X-Canary-Variant"
+
+// CanaryVariant is one upstream version of a canary route together with its traffic counters.
+type CanaryVariant struct {
+	Name       string
+	Target     string
+	weight     int
+	configured int // weight from the config file
+	handler    http.Handler
+	requests   atomic.Int64
+	errors     atomic.Int64
+}
+
+type CanaryVariantStats struct {
+	Name     string `json:"name"`
+	Target   string `json:"target"`
+	Weight   int    `json:"weight"`
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+}
+
+type CanaryRouteStats struct {
+	Name       string               `json:"name"`
+	PathPrefix string               `json:"path_prefix"`
+	Sticky     bool                 `json:"sticky"`
+	Variants   []CanaryVariantStats `json:"variants"`
+}
+
+// CanaryRoute splits traffic for a path prefix between upstream variants.
+// A variant is chosen by the canary header first, then by a sticky hash of the user ID, then at random by weight.
+type CanaryRoute struct {
+	Name       string
+	PathPrefix string
+	Sticky     bool
+
+	mu       sync.RWMutex
+	variants []*CanaryVariant
+	manual   bool // weights were changed through SetWeights
+}
+
+type CanaryVariantConfig struct {
+	Name   string
+	Target string
+	Weight int
+}
+
+func NewCanaryRoute(name string, pathPrefix string, sticky bool, variants []CanaryVariantConfig) (*CanaryRoute, error) {
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("canary route %s has no variants", name)
+	}
+
+	route := &CanaryRoute{
+		Name:       name,
+		PathPrefix: pathPrefix,
+		Sticky:     sticky,
+	}
+
+	seen := map[string]bool{}
+	for _, v := range variants {
+		if seen[v.Name] {
+			return nil, fmt.Errorf("canary route %s has duplicate variant %s", name, v.Name)
+		}
+		seen[v.Name] = true
+
+		handler := ProxyToService(v.Target, pathPrefix)
+		if handler == nil {
+			return nil, fmt.Errorf("canary route %s has invalid target %s", name, v.Target)
+		}
+
+		route.variants = append(route.variants, &CanaryVariant{
+			Name:       v.Name,
+			Target:     v.Target,
+			weight:     v.Weight,
+			configured: v.Weight,
+			handler:    handler,
+		})
+	}
+
+	if err := validateCanaryWeights(route.variants, nil); err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
+
+// SetWeights atomically replaces the weights of the named variants. Variants not mentioned keep their weight.
+func (c *CanaryRoute) SetWeights(weights map[string]int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name := range weights {
+		if c.findVariant(name) == nil {
+			return fmt.Errorf("unknown variant %s", name)
+		}
+	}
+
+	if err := validateCanaryWeights(c.variants, weights); err != nil {
+		return err
+	}
+
+	for _, v := range c.variants {
+		if w, ok := weights[v.Name]; ok {
+			v.weight = w
+		}
+	}
+	c.manual = true
+
+	fmt.Println("Canary weights updated for route", c.Name, ":", weights)
+	return nil
+}
+
+// InheritStats carries request and error counters over from a previous version of the route,
+// for variants whose name and target did not change.
+func (c *CanaryRoute) InheritStats(previous *CanaryRoute) {
+	if previous == nil {
+		return
+	}
+
+	previous.mu.RLock()
+	defer previous.mu.RUnlock()
+
+	for _, v := range c.variants {
+		if old := previous.findVariant(v.Name); old != nil && old.Target == v.Target {
+			v.requests.Store(old.requests.Load())
+			v.errors.Store(old.errors.Load())
+		}
+	}
+}
+
+// InheritWeights keeps weights set through SetWeights on a previous version of the route, as long as it has the
+// same variants with the same configured weights. Changing the weights in the config file overrides them.
+func (c *CanaryRoute) InheritWeights(previous *CanaryRoute) {
+	if previous == nil {
+		return
+	}
+
+	previous.mu.RLock()
+	defer previous.mu.RUnlock()
+
+	if !previous.manual || len(previous.variants) != len(c.variants) {
+		return
+	}
+	for _, v := range c.variants {
+		if old := previous.findVariant(v.Name); old == nil || old.configured != v.configured {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range c.variants {
+		v.weight = previous.findVariant(v.Name).weight
+	}
+	c.manual = true
+	fmt.Println("Canary weights kept across reload for route", c.Name)
+}
+
+func (c *CanaryRoute) Stats() CanaryRouteStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := CanaryRouteStats{
+		Name:       c.Name,
+		PathPrefix: c.PathPrefix,
+		Sticky:     c.Sticky,
+	}
+	for _, v := range c.variants {
+		stats.Variants = append(stats.Variants, CanaryVariantStats{
+			Name:     v.Name,
+			Target:   v.Target,
+			Weight:   v.weight,
+			Requests: v.requests.Load(),
+			Errors:   v.errors.Load(),
+		})
+	}
+	return stats
+}
+
+func (c *CanaryRoute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	variant := c.pickVariant(r)
+
+	variant.requests.Add(1)
+	SetAccessLogVariant(r.Context(), variant.Name)
+	recorder := &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+	variant.handler.ServeHTTP(recorder, r)
+
+	if recorder.Status >= http.StatusInternalServerError {
+		variant.errors.Add(1)
+	}
+}
+
+func (c *CanaryRoute) pickVariant(r *http.Request) *CanaryVariant {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if name := r.Header.Get(CanaryVariantHeader); name != "" {
+		if v := c.findVariant(name); v != nil {
+			return v
+		}
+	}
+
+	total := 0
+	for _, v := range c.variants {
+		total += v.weight
+	}
+
+	var point int
+	if userId := requestUserId(r); c.Sticky && userId != "" {
+		// Hashing the user ID keeps a user on the same variant as long as the weights do not change
+		h := fnv.New32a()
+		h.Write([]byte(c.Name + ":" + userId))
+		point = int(h.Sum32() % uint32(total))
+	} else {
+		point = rand.IntN(total)
+	}
+
+	for _, v := range c.variants {
+		if point < v.weight {
+			return v
+		}
+		point -= v.weight
+	}
+	return c.variants[len(c.variants)-1]
+}
+
+func (c *CanaryRoute) findVariant(name string) *CanaryVariant {
+	for _, v := range c.variants {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+func validateCanaryWeights(variants []*CanaryVariant, overrides map[string]int) error {
+	total := 0
+	for _, v := range variants {
+		w := v.weight
+		if o, ok := overrides[v.Name]; ok {
+			w = o
+		}
+		if w < 0 {
+			return fmt.Errorf("weight for variant %s must not be negative", v.Name)
+		}
+		total += w
+	}
+	if total != 100 {
+		return fmt.Errorf("variant weights must add up to 100, got %d", total)
+	}
+	return nil
+}
+
+func requestUserId(r *http.Request) string {
+	if userId, ok := r.Context().Value("userID").(string); ok && userId != "" {
+		return userId
+	}
+	return r.Header.Get("X-User-ID")
+}
+
+// CanaryRegistry keeps all canary routes of the gateway by name.
+type CanaryRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]*CanaryRoute
+}
+
+func NewCanaryRegistry() *CanaryRegistry {
+	return &CanaryRegistry{
+		routes: map[string]*CanaryRoute{},
+	}
+}
+
+func (cr *CanaryRegistry) Add(route *CanaryRoute) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.routes[route.Name] = route
+}
+
+func (cr *CanaryRegistry) Get(name string) *CanaryRoute {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.routes[name]
+}
+
+func (cr *CanaryRegistry) All() []*CanaryRoute {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	routes := make([]*CanaryRoute, 0, len(cr.routes))
+	for _, route := range cr.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+	return routes
+}
+
+// StatusRecorder captures the status code written by a wrapped handler.
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+	Bytes  int64
+}
+
+func (s *StatusRecorder) WriteHeader(status int) {
+	s.Status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *StatusRecorder) Write(b []byte) (int, error) {
+	n, err := s.ResponseWriter.Write(b)
+	s.Bytes += int64(n)
+	return n, err
+}
+
+func (s *StatusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// canaryUpstreams starts one upstream per variant that answers with the variant name.
+func canaryUpstreams(t *testing.T, weights map[string]int) []CanaryVariantConfig {
+	t.Helper()
+	variants := []CanaryVariantConfig{}
+	for _, name := range []string{"stable", "canary"} {
+		name := name
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+			io.WriteString(w, name)
+		}))
+		t.Cleanup(server.Close)
+		variants = append(variants, CanaryVariantConfig{Name: name, Target: server.URL, Weight: weights[name]})
+	}
+	return variants
+}
+
+func serveCanary(t *testing.T, route *CanaryRoute, path string, header http.Header) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/hotels"+path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	route.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestNewCanaryRouteValidatesVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []CanaryVariantConfig
+	}{
+		{"no variants", nil},
+		{"weights below 100", []CanaryVariantConfig{{"a", "http://a", 50}, {"b", "http://b", 40}}},
+		{"negative weight", []CanaryVariantConfig{{"a", "http://a", 110}, {"b", "http://b", -10}}},
+		{"duplicate variant", []CanaryVariantConfig{{"a", "http://a", 50}, {"a", "http://b", 50}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCanaryRoute("hotels", "/api/v1/hotels", false, tt.variants); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCanaryRoutePicksVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int
+		sticky  bool
+		header  http.Header
+		want    string
+	}{
+		{"all weight on stable", map[string]int{"stable": 100}, false, nil, "stable"},
+		{"all weight on canary", map[string]int{"canary": 100}, false, nil, "canary"},
+		{"header forces a variant without weight", map[string]int{"stable": 100}, false, http.Header{CanaryVariantHeader: {"canary"}}, "canary"},
+		{"unknown header variant falls back to weights", map[string]int{"stable": 100}, false, http.Header{CanaryVariantHeader: {"v9"}}, "stable"},
+		{"sticky user follows the weights", map[string]int{"canary": 100}, true, http.Header{"X-User-Id": {"42"}}, "canary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := NewCanaryRoute("hotels", "/api/v1/hotels", tt.sticky, canaryUpstreams(t, tt.weights))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 20; i++ {
+				if got := serveCanary(t, route, "/1", tt.header); got != tt.want {
+					t.Fatalf("request %d served by %q, want %q", i, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCanaryRouteSplitsByWeight(t *testing.T) {
+	route, err := NewCanaryRoute("hotels", "/api/v1/hotels", false, canaryUpstreams(t, map[string]int{"stable": 80, "canary": 20}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		serveCanary(t, route, "/1", nil)
+	}
+
+	stats := route.Stats()
+	canary := stats.Variants[1].Requests
+	if stats.Variants[0].Requests+canary != 1000 {
+		t.Fatalf("counted %d and %d requests, want 1000 in total", stats.Variants[0].Requests, canary)
+	}
+	if canary < 120 || canary > 280 {
+		t.Errorf("canary served %d of 1000 requests, want about 200", canary)
+	}
+}
+
+func TestCanaryRouteKeepsStickyUsersOnOneVariant(t *testing.T) {
+	route, err := NewCanaryRoute("hotels", "/api/v1/hotels", true, canaryUpstreams(t, map[string]int{"stable": 50, "canary": 50}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for user := 0; user < 20; user++ {
+		header := http.Header{"X-User-Id": {fmt.Sprint(user)}}
+		first := serveCanary(t, route, "/1", header)
+		seen[first] = true
+		for i := 0; i < 5; i++ {
+			if got := serveCanary(t, route, "/1", header); got != first {
+				t.Fatalf("user %d moved from %s to %s", user, first, got)
+			}
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("20 users all landed on %v, want both variants used", seen)
+	}
+}
+
+func TestCanaryRouteCountsUpstreamErrors(t *testing.T) {
+	route, err := NewCanaryRoute("hotels", "/api/v1/hotels", false, canaryUpstreams(t, map[string]int{"stable": 100}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serveCanary(t, route, "/1", nil)
+	serveCanary(t, route, "/fail", nil)
+
+	stable := route.Stats().Variants[0]
+	if stable.Requests != 2 || stable.Errors != 1 {
+		t.Errorf("got %d requests and %d errors, want 2 and 1", stable.Requests, stable.Errors)
+	}
+}
+
+func TestCanaryRouteSetWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int
+		wantErr bool
+		want    string
+	}{
+		{"shift all traffic", map[string]int{"stable": 0, "canary": 100}, false, "canary"},
+		{"unknown variant", map[string]int{"v9": 100, "stable": 0}, true, "stable"},
+		{"sum above 100", map[string]int{"canary": 50}, true, "stable"},
+		{"negative weight", map[string]int{"stable": 110, "canary": -10}, true, "stable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := NewCanaryRoute("hotels", "/api/v1/hotels", false, canaryUpstreams(t, map[string]int{"stable": 100}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = route.SetWeights(tt.weights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetWeights error %v, want error %v", err, tt.wantErr)
+			}
+			if got := serveCanary(t, route, "/1", nil); got != tt.want {
+				t.Errorf("served by %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanaryRouteInheritsManualWeights(t *testing.T) {
+	upstreams := canaryUpstreams(t, map[string]int{"stable": 90, "canary": 10})
+	previous, _ := NewCanaryRoute("hotels", "/api/v1/hotels", false, upstreams)
+	if err := previous.SetWeights(map[string]int{"stable": 0, "canary": 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, _ := NewCanaryRoute("hotels", "/api/v1/hotels", false, upstreams)
+	reloaded.InheritWeights(previous)
+	if got := reloaded.Stats().Variants[1].Weight; got != 100 {
+		t.Errorf("canary weight after reload %d, want the manual 100", got)
+	}
+
+	upstreams[0].Weight, upstreams[1].Weight = 50, 50
+	changed, _ := NewCanaryRoute("hotels", "/api/v1/hotels", false, upstreams)
+	changed.InheritWeights(previous)
+	if got := changed.Stats().Variants[1].Weight; got != 50 {
+		t.Errorf("canary weight after a config change %d, want the configured 50", got)
+	}
+}
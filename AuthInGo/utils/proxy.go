@@ -22,17 +22,20 @@ func ProxyToService(targetBaseUrl string, pathPrefix string) http.HandlerFunc {
 	originalDirector := proxy.Director
 
 	proxy.Director = func(r *http.Request) {
-		originalDirector(r)
-
 		originalPath := r.URL.Path
 
 		strippedPath := strings.TrimPrefix(originalPath, pathPrefix)
 
-		r.URL.Host = target.Host
-		r.URL.Path = target.Path + strippedPath
+		// Strip the gateway prefix before the default director joins the target path
+		r.URL.Path = strippedPath
+		r.URL.RawPath = ""
+
+		originalDirector(r)
 
 		r.Host = target.Host
 
+		SetAccessLogUpstream(r.Context(), target.Host)
+
 		if userId, ok := r.Context().Value("userID").(string); ok {
 			r.Header.Set("X-User-ID", userId)
 		}
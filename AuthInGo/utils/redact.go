@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// RedactionConfig controls what is masked before anything is written to the logs.
+type RedactionConfig struct {
+	Fields     []string // JSON keys, header names and query parameters, matched case-insensitively
+	MaskEmails bool     // mask email addresses appearing anywhere in a value
+	MaskTokens bool     // mask JWTs and bearer tokens appearing anywhere in a value
+	Mask       string
+}
+
+var DefaultRedactionConfig = RedactionConfig{
+	Fields:     []string{"password", "token", "access_token", "refresh_token", "jwt", "secret", "authorization", "cookie", "set-cookie", "email"},
+	MaskEmails: true,
+	MaskTokens: true,
+	Mask:       "[REDACTED]",
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/\-]+=*`)
+)
+
+type redactor struct {
+	config RedactionConfig
+	fields map[string]bool
+}
+
+var activeRedactor atomic.Pointer[redactor]
+
+func init() {
+	SetRedactionConfig(DefaultRedactionConfig)
+}
+
+// SetRedactionConfig replaces the redaction rules used by all log helpers.
+func SetRedactionConfig(cfg RedactionConfig) {
+	if cfg.Mask == "" {
+		cfg.Mask = DefaultRedactionConfig.Mask
+	}
+	fields := map[string]bool{}
+	for _, f := range cfg.Fields {
+		fields[strings.ToLower(f)] = true
+	}
+	activeRedactor.Store(&redactor{config: cfg, fields: fields})
+}
+
+// RedactString masks emails and tokens inside free text.
+func RedactString(s string) string {
+	red := activeRedactor.Load()
+	if red.config.MaskTokens {
+		s = bearerPattern.ReplaceAllString(s, "Bearer "+red.config.Mask)
+		s = jwtPattern.ReplaceAllString(s, red.config.Mask)
+	}
+	if red.config.MaskEmails {
+		s = emailPattern.ReplaceAllString(s, red.config.Mask)
+	}
+	return s
+}
+
+// Redact renders a value for logging with sensitive fields masked. Structs, maps and slices are walked as JSON.
+func Redact(v any) string {
+	switch value := v.(type) {
+	case string:
+		return RedactString(value)
+	case error:
+		return RedactString(value.Error())
+	case nil:
+		return "<nil>"
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return RedactString(fmt.Sprint(v))
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return RedactString(string(raw))
+	}
+
+	masked, err := json.Marshal(redactValue(activeRedactor.Load(), decoded))
+	if err != nil {
+		return RedactString(string(raw))
+	}
+	return RedactString(string(masked))
+}
+
+func redactValue(red *redactor, v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, inner := range value {
+			if red.fields[strings.ToLower(k)] {
+				value[k] = red.config.Mask
+			} else {
+				value[k] = redactValue(red, inner)
+			}
+		}
+		return value
+	case []any:
+		for i, inner := range value {
+			value[i] = redactValue(red, inner)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// RedactHeaders returns a copy of the headers with sensitive headers masked.
+func RedactHeaders(h http.Header) http.Header {
+	red := activeRedactor.Load()
+	out := http.Header{}
+	for k, values := range h {
+		for _, v := range values {
+			if red.fields[strings.ToLower(k)] {
+				out.Add(k, red.config.Mask)
+			} else {
+				out.Add(k, RedactString(v))
+			}
+		}
+	}
+	return out
+}
+
+// RedactQuery masks sensitive query parameters of a raw query string.
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return RedactString(rawQuery)
+	}
+	red := activeRedactor.Load()
+	for k, vs := range values {
+		for i := range vs {
+			if red.fields[strings.ToLower(k)] {
+				vs[i] = red.config.Mask
+			} else {
+				vs[i] = RedactString(vs[i])
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// LogRedacted prints a log line like fmt.Println, masking sensitive data in every value.
+func LogRedacted(message string, values ...any) {
+	args := make([]any, 0, len(values)+1)
+	args = append(args, RedactString(message))
+	for _, v := range values {
+		args = append(args, Redact(v))
+	}
+	fmt.Println(args...)
+}
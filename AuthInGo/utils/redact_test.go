@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testJwt = "eyJhbGciOiJIUzI1NiJ9.eyJpZCI6MX0.c2lnbmF0dXJl"
+
+// captureStdout returns everything fn prints to standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func useRedactionConfig(t *testing.T, cfg RedactionConfig) {
+	t.Helper()
+	SetRedactionConfig(cfg)
+	t.Cleanup(func() { SetRedactionConfig(DefaultRedactionConfig) })
+}
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "hotel 7 booked", "hotel 7 booked"},
+		{"email", "login by jane.doe@example.com failed", "login by [REDACTED] failed"},
+		{"jwt", "JWT Token: " + testJwt, "JWT Token: [REDACTED]"},
+		{"bearer token", "Authorization: Bearer abc.def-123", "Authorization: Bearer [REDACTED]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactString(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type login struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Name     string `json:"name"`
+	}
+
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"nil", nil, "<nil>"},
+		{"struct fields", login{Email: "jane@example.com", Password: "hunter2", Name: "Jane"}, `{"email":"[REDACTED]","name":"Jane","password":"[REDACTED]"}`},
+		{"nested fields are matched case-insensitively", map[string]any{"user": map[string]any{"Token": "abc", "id": 1}}, `{"user":{"Token":"[REDACTED]","id":1}}`},
+		{"values inside lists", []any{map[string]any{"secret": "x"}, "mail jane@example.com"}, `[{"secret":"[REDACTED]"},"mail [REDACTED]"]`},
+		{"email in a free-form field", map[string]any{"note": "ask jane@example.com"}, `{"note":"ask [REDACTED]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{
+		"Authorization":   {"Bearer " + testJwt},
+		"Cookie":          {"session=abc"},
+		"X-Forwarded-For": {"10.0.0.1"},
+		"X-Debug":         {"token " + testJwt},
+	}
+
+	redacted := RedactHeaders(headers)
+
+	want := map[string]string{
+		"Authorization":   "[REDACTED]",
+		"Cookie":          "[REDACTED]",
+		"X-Forwarded-For": "10.0.0.1",
+		"X-Debug":         "token [REDACTED]",
+	}
+	for k, v := range want {
+		if got := redacted.Get(k); got != v {
+			t.Errorf("header %s: got %q, want %q", k, got, v)
+		}
+	}
+	if headers.Get("Authorization") != "Bearer "+testJwt {
+		t.Error("the original headers were modified")
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	got, err := url.ParseQuery(RedactQuery("access_token=abc&page=2&q=jane@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("access_token") != "[REDACTED]" || got.Get("page") != "2" || got.Get("q") != "[REDACTED]" {
+		t.Errorf("got %v", got)
+	}
+	if RedactQuery("") != "" {
+		t.Error("empty query should stay empty")
+	}
+}
+
+func TestSetRedactionConfig(t *testing.T) {
+	useRedactionConfig(t, RedactionConfig{
+		Fields:     []string{"phone"},
+		MaskEmails: false,
+		MaskTokens: true,
+		Mask:       "***",
+	})
+
+	got := Redact(map[string]any{"phone": "555-0100", "password": "hunter2", "contact": "jane@example.com " + testJwt})
+	want := `{"contact":"jane@example.com ***","password":"hunter2","phone":"***"}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestLogRedacted(t *testing.T) {
+	out := captureStdout(t, func() {
+		LogRedacted("Payload received for jane@example.com:", map[string]string{"email": "jane@example.com", "password": "hunter2"}, 42)
+	})
+
+	want := `Payload received for [REDACTED]: {"email":"[REDACTED]","password":"[REDACTED]"} 42` + "\n"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Error("password leaked into the log")
+	}
+}
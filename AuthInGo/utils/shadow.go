@@ -0,0 +1,389 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShadowHeader is set on every mirrored request so the candidate upstream can tell shadow traffic apart.
+const ShadowHeader = "X-Shadow-Request"
+
+const (
+	maxShadowBodyBytes  = 1 << 20 // bodies above 1MB are not mirrored or compared
+	maxShadowInFlight   = 32
+	maxRecentShadowDiff = 50
+	maxShadowDiffPaths  = 20
+)
+
+type ShadowConfig struct {
+	Target        string
+	PathPrefix    string
+	SamplePercent float64
+	Methods       []string
+	Timeout       time.Duration
+}
+
+// ShadowMirror copies a sample of live requests to a shadow upstream after the primary response has been sent.
+// Shadow responses are discarded; only the comparison with the primary response is kept.
+type ShadowMirror struct {
+	Name     string
+	config   ShadowConfig
+	target   *url.URL
+	methods  map[string]bool
+	client   *http.Client
+	inFlight chan struct{}
+	Stats    *ShadowStats
+}
+
+// NewShadowMirror creates a mirror for a route. Passing the stats of a previous mirror keeps its history across reloads.
+func NewShadowMirror(name string, cfg ShadowConfig, stats *ShadowStats) (*ShadowMirror, error) {
+	target, err := url.Parse(cfg.Target)
+	if err != nil {
+		return nil, fmt.Errorf("shadow for route %s has invalid target %s: %w", name, cfg.Target, err)
+	}
+
+	methods := map[string]bool{}
+	for _, m := range cfg.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+	if len(methods) == 0 {
+		// Only safe methods are mirrored unless configured otherwise, so the shadow never duplicates writes
+		methods[http.MethodGet] = true
+		methods[http.MethodHead] = true
+	}
+
+	if stats == nil {
+		stats = &ShadowStats{Target: cfg.Target}
+	}
+
+	return &ShadowMirror{
+		Name:     name,
+		config:   cfg,
+		target:   target,
+		methods:  methods,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		inFlight: make(chan struct{}, maxShadowInFlight),
+		Stats:    stats,
+	}, nil
+}
+
+// Wrap returns a handler that serves the request through primary and mirrors a sample of it to the shadow.
+func (s *ShadowMirror) Wrap(primary http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.methods[r.Method] || rand.Float64()*100 >= s.config.SamplePercent {
+			primary.ServeHTTP(w, r)
+			return
+		}
+
+		requestBody, ok := bufferRequestBody(r)
+		if !ok {
+			primary.ServeHTTP(w, r)
+			return
+		}
+
+		shadowReq, err := s.newShadowRequest(r, requestBody)
+		if err != nil {
+			fmt.Println("Error building shadow request:", err)
+			primary.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &shadowRecorder{StatusRecorder: StatusRecorder{ResponseWriter: w, Status: http.StatusOK}}
+		start := time.Now()
+		primary.ServeHTTP(recorder, r)
+		primaryResult := shadowResult{
+			status:  recorder.Status,
+			latency: time.Since(start),
+			body:    decodeBody(recorder.Header().Get("Content-Encoding"), recorder.body.Bytes()),
+		}
+		if recorder.truncated {
+			return
+		}
+
+		select {
+		case s.inFlight <- struct{}{}:
+		default:
+			s.Stats.recordDropped()
+			return
+		}
+
+		method, path := r.Method, r.URL.Path
+		go func() {
+			defer func() { <-s.inFlight }()
+			s.Stats.record(method, path, primaryResult, s.send(shadowReq))
+		}()
+	})
+}
+
+func (s *ShadowMirror) newShadowRequest(r *http.Request, body []byte) (*http.Request, error) {
+	shadowUrl := *s.target
+	shadowUrl.Path = strings.TrimSuffix(s.target.Path, "/") + strings.TrimPrefix(r.URL.Path, s.config.PathPrefix)
+	shadowUrl.RawQuery = r.URL.RawQuery
+
+	req, err := http.NewRequest(r.Method, shadowUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = r.Header.Clone()
+	// Let the client negotiate compression itself so the body arrives decoded
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set(ShadowHeader, "true")
+	if userId := requestUserId(r); userId != "" {
+		req.Header.Set("X-User-ID", userId)
+	}
+
+	return req, nil
+}
+
+func (s *ShadowMirror) send(req *http.Request) shadowResult {
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return shadowResult{err: err, latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxShadowBodyBytes))
+	return shadowResult{status: resp.StatusCode, latency: time.Since(start), body: body, err: err}
+}
+
+type shadowResult struct {
+	status  int
+	latency time.Duration
+	body    []byte
+	err     error
+}
+
+// ShadowDiff describes one compared request whose shadow response differed from the primary.
+type ShadowDiff struct {
+	At               time.Time `json:"at"`
+	Method           string    `json:"method"`
+	Path             string    `json:"path"`
+	PrimaryStatus    int       `json:"primary_status"`
+	ShadowStatus     int       `json:"shadow_status"`
+	PrimaryLatencyMs int64     `json:"primary_latency_ms"`
+	ShadowLatencyMs  int64     `json:"shadow_latency_ms"`
+	BodyDiffPaths    []string  `json:"body_diff_paths,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+type ShadowSummary struct {
+	Route               string       `json:"route"`
+	Target              string       `json:"target"`
+	Compared            int64        `json:"compared"`
+	Matched             int64        `json:"matched"`
+	StatusMismatches    int64        `json:"status_mismatches"`
+	BodyMismatches      int64        `json:"body_mismatches"`
+	ShadowErrors        int64        `json:"shadow_errors"`
+	Dropped             int64        `json:"dropped"`
+	AvgPrimaryLatencyMs float64      `json:"avg_primary_latency_ms"`
+	AvgShadowLatencyMs  float64      `json:"avg_shadow_latency_ms"`
+	RecentDifferences   []ShadowDiff `json:"recent_differences"`
+}
+
+// ShadowStats accumulates the comparison results of a shadow mirror.
+type ShadowStats struct {
+	Target string
+
+	mu               sync.Mutex
+	compared         int64
+	matched          int64
+	statusMismatches int64
+	bodyMismatches   int64
+	shadowErrors     int64
+	dropped          int64
+	primaryLatency   time.Duration
+	shadowLatency    time.Duration
+	recent           []ShadowDiff
+}
+
+func (st *ShadowStats) recordDropped() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.dropped++
+}
+
+func (st *ShadowStats) record(method string, path string, primary shadowResult, shadow shadowResult) {
+	diff := ShadowDiff{
+		At:               time.Now(),
+		Method:           method,
+		Path:             path,
+		PrimaryStatus:    primary.status,
+		ShadowStatus:     shadow.status,
+		PrimaryLatencyMs: primary.latency.Milliseconds(),
+		ShadowLatencyMs:  shadow.latency.Milliseconds(),
+	}
+	if shadow.err == nil && primary.status == shadow.status {
+		diff.BodyDiffPaths = DiffJsonBodies(primary.body, shadow.body)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.compared++
+	st.primaryLatency += primary.latency
+	st.shadowLatency += shadow.latency
+
+	switch {
+	case shadow.err != nil:
+		st.shadowErrors++
+		diff.Error = shadow.err.Error()
+	case primary.status != shadow.status:
+		st.statusMismatches++
+	default:
+		if len(diff.BodyDiffPaths) == 0 {
+			st.matched++
+			return
+		}
+		st.bodyMismatches++
+	}
+
+	st.recent = append(st.recent, diff)
+	if len(st.recent) > maxRecentShadowDiff {
+		st.recent = st.recent[len(st.recent)-maxRecentShadowDiff:]
+	}
+}
+
+func (st *ShadowStats) Summary(route string) ShadowSummary {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	summary := ShadowSummary{
+		Route:             route,
+		Target:            st.Target,
+		Compared:          st.compared,
+		Matched:           st.matched,
+		StatusMismatches:  st.statusMismatches,
+		BodyMismatches:    st.bodyMismatches,
+		ShadowErrors:      st.shadowErrors,
+		Dropped:           st.dropped,
+		RecentDifferences: append([]ShadowDiff{}, st.recent...),
+	}
+	if st.compared > 0 {
+		summary.AvgPrimaryLatencyMs = float64(st.primaryLatency.Milliseconds()) / float64(st.compared)
+		summary.AvgShadowLatencyMs = float64(st.shadowLatency.Milliseconds()) / float64(st.compared)
+	}
+	return summary
+}
+
+// DiffJsonBodies returns the JSON paths at which two bodies differ. Bodies that are not JSON are compared byte for byte.
+func DiffJsonBodies(a []byte, b []byte) []string {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		if bytes.Equal(a, b) {
+			return nil
+		}
+		return []string{"$"}
+	}
+
+	var paths []string
+	diffJsonValues("$", left, right, &paths)
+	return paths
+}
+
+func diffJsonValues(path string, a any, b any, paths *[]string) {
+	if len(*paths) >= maxShadowDiffPaths {
+		return
+	}
+
+	switch left := a.(type) {
+	case map[string]any:
+		right, ok := b.(map[string]any)
+		if !ok {
+			*paths = append(*paths, path)
+			return
+		}
+		keys := map[string]bool{}
+		for k := range left {
+			keys[k] = true
+		}
+		for k := range right {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffJsonValues(path+"."+k, left[k], right[k], paths)
+		}
+	case []any:
+		right, ok := b.([]any)
+		if !ok || len(left) != len(right) {
+			*paths = append(*paths, path)
+			return
+		}
+		for i := range left {
+			diffJsonValues(fmt.Sprintf("%s[%d]", path, i), left[i], right[i], paths)
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+// shadowRecorder tees the primary response body so it can be compared with the shadow response.
+type shadowRecorder struct {
+	StatusRecorder
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (s *shadowRecorder) Write(b []byte) (int, error) {
+	if !s.truncated {
+		if s.body.Len()+len(b) > maxShadowBodyBytes {
+			s.truncated = true
+			s.body.Reset()
+		} else {
+			s.body.Write(b)
+		}
+	}
+	return s.StatusRecorder.Write(b)
+}
+
+// bufferRequestBody reads the request body so it can be replayed to both upstreams.
+// It reports false, leaving the body readable, when the body is too large to mirror.
+func bufferRequestBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxShadowBodyBytes+1))
+	if err != nil || len(buf) > maxShadowBodyBytes {
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(buf), r.Body))
+		return nil, false
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(buf))
+	return buf, true
+}
+
+func decodeBody(contentEncoding string, body []byte) []byte {
+	if contentEncoding != "gzip" {
+		return body
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return body
+	}
+	defer reader.Close()
+	decoded, err := io.ReadAll(io.LimitReader(reader, maxShadowBodyBytes))
+	if err != nil {
+		return body
+	}
+	return decoded
+}
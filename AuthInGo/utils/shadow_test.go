@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffJsonBodies(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		want  []string
+	}{
+		{"identical", `{"a":1,"b":[1,2]}`, `{"a":1,"b":[1,2]}`, nil},
+		{"key order and whitespace", `{"a":1,"b":2}`, `{ "b": 2, "a": 1 }`, nil},
+		{"changed value", `{"a":1,"b":2}`, `{"a":1,"b":3}`, []string{"$.b"}},
+		{"missing key", `{"a":1,"b":2}`, `{"a":1}`, []string{"$.b"}},
+		{"added key", `{"a":1}`, `{"a":1,"c":null}`, nil},
+		{"nested change", `{"data":{"hotel":{"name":"Sea"}}}`, `{"data":{"hotel":{"name":"Bay"}}}`, []string{"$.data.hotel.name"}},
+		{"array element", `{"ids":[1,2,3]}`, `{"ids":[1,5,3]}`, []string{"$.ids[1]"}},
+		{"array length", `{"ids":[1,2]}`, `{"ids":[1,2,3]}`, []string{"$.ids"}},
+		{"type change", `{"a":{"b":1}}`, `{"a":[1]}`, []string{"$.a"}},
+		{"several paths sorted", `{"z":1,"a":1}`, `{"z":2,"a":2}`, []string{"$.a", "$.z"}},
+		{"equal plain text", `ok`, `ok`, nil},
+		{"different plain text", `ok`, `not ok`, []string{"$"}},
+		{"json against text", `{"a":1}`, `oops`, []string{"$"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffJsonBodies([]byte(tt.left), []byte(tt.right)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffJsonBodiesCapsPaths(t *testing.T) {
+	var left, right strings.Builder
+	left.WriteString("[")
+	right.WriteString("[")
+	for i := 0; i < 2*maxShadowDiffPaths; i++ {
+		if i > 0 {
+			left.WriteString(",")
+			right.WriteString(",")
+		}
+		left.WriteString("1")
+		right.WriteString("2")
+	}
+	left.WriteString("]")
+	right.WriteString("]")
+
+	if got := DiffJsonBodies([]byte(left.String()), []byte(right.String())); len(got) != maxShadowDiffPaths {
+		t.Errorf("got %d paths, want %d", len(got), maxShadowDiffPaths)
+	}
+}
+
+// mirrorShadow starts primary and shadow upstreams, mirrors every GET to the shadow and serves one request.
+func mirrorShadow(t *testing.T, primary, shadow http.HandlerFunc) ShadowSummary {
+	t.Helper()
+	primaryServer := httptest.NewServer(primary)
+	t.Cleanup(primaryServer.Close)
+	shadowServer := httptest.NewServer(shadow)
+	t.Cleanup(shadowServer.Close)
+
+	mirror, err := NewShadowMirror("hotels", ShadowConfig{
+		Target:        shadowServer.URL,
+		PathPrefix:    "/api/v1/hotels",
+		SamplePercent: 100,
+		Timeout:       time.Second,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	mirror.Wrap(ProxyToService(primaryServer.URL, "/api/v1/hotels")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/hotels/7", nil))
+	if rec.Body.String() == "" {
+		t.Fatal("primary response was not passed through")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		summary := mirror.Stats.Summary("hotels")
+		if summary.Compared == 1 {
+			return summary
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("shadow comparison was not recorded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShadowMirrorComparesResponses(t *testing.T) {
+	primary := func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"id":7,"name":"Sea View"}`)
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		summary := mirrorShadow(t, primary, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/7" || r.Header.Get(ShadowHeader) != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `{"name":"Sea View","id":7}`)
+		})
+		if summary.Matched != 1 || len(summary.RecentDifferences) != 0 {
+			t.Errorf("summary %+v, want one match", summary)
+		}
+	})
+
+	t.Run("body mismatch", func(t *testing.T) {
+		summary := mirrorShadow(t, primary, func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"id":7,"name":"Bay View"}`)
+		})
+		if summary.BodyMismatches != 1 || !reflect.DeepEqual(summary.RecentDifferences[0].BodyDiffPaths, []string{"$.name"}) {
+			t.Errorf("summary %+v, want a body mismatch at $.name", summary)
+		}
+	})
+
+	t.Run("status mismatch", func(t *testing.T) {
+		summary := mirrorShadow(t, primary, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		if summary.StatusMismatches != 1 || summary.RecentDifferences[0].ShadowStatus != http.StatusInternalServerError {
+			t.Errorf("summary %+v, want a status mismatch", summary)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// UpstreamResult is the outcome of a single call made by the gateway to a downstream service.
+type UpstreamResult struct {
+	Status int
+	Body   json.RawMessage
+	Err    error
+}
+
+// FetchUpstreamJson performs a GET request against a downstream service and returns the raw JSON body.
+// The supplied context carries the per-call timeout.
+func FetchUpstreamJson(ctx context.Context, client *http.Client, targetUrl string, headers http.Header) UpstreamResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetUrl, nil)
+	if err != nil {
+		return UpstreamResult{Err: err}
+	}
+
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return UpstreamResult{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return UpstreamResult{Status: resp.StatusCode, Err: err}
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return UpstreamResult{Status: resp.StatusCode, Body: body, Err: fmt.Errorf("upstream responded with status %d", resp.StatusCode)}
+	}
+
+	if !json.Valid(body) {
+		return UpstreamResult{Status: resp.StatusCode, Err: fmt.Errorf("upstream returned invalid JSON")}
+	}
+
+	return UpstreamResult{Status: resp.StatusCode, Body: body}
+}
+
+// UnwrapJsonData returns the "data" field of a standard service envelope ({success, message, data}),
+// or the body unchanged when it is not wrapped.
+func UnwrapJsonData(body json.RawMessage) json.RawMessage {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body
+	}
+	if data, ok := envelope["data"]; ok {
+		return data
+	}
+	return body
+}
@@ -4,7 +4,8 @@ import dotenv from 'dotenv';
 type ServerConfig = {
     PORT: number,
     REDIS_SERVER_URL: string,
-    LOCK_TTL: number
+    LOCK_TTL: number,
+    INTERNAL_SERVICE_TOKEN: string
 }
 
 function loadEnv() {
@@ -17,5 +18,6 @@ loadEnv();
 export const serverConfig: ServerConfig = {
     PORT: Number(process.env.PORT) || 3001,
     REDIS_SERVER_URL: process.env.REDIS_SERVER_URL || 'redis://localhost:6379',
-    LOCK_TTL: Number(process.env.LOCK_TTL) || 5000 // Default to 5 seconds
+    LOCK_TTL: Number(process.env.LOCK_TTL) || 5000, // Default to 5 seconds
+    INTERNAL_SERVICE_TOKEN: process.env.INTERNAL_SERVICE_TOKEN || '' // Shared with the services allowed to read bookings
 };
\ No newline at end of file
@@ -1,5 +1,5 @@
 import { Request, Response } from 'express';
-import { confirmBookingService, createBookingService } from '../services/booking.service';
+import { confirmBookingService, createBookingService, getBookingByIdService } from '../services/booking.service';
 
 export const createBookingHandler = async (req: Request, res: Response) => {
 
@@ -11,6 +11,18 @@ export const createBookingHandler = async (req: Request, res: Response) => {
     });
 }
 
+export const getBookingHandler = async (req: Request, res: Response) => {
+    const booking = await getBookingByIdService(Number(req.params.bookingId));
+
+    res.status(200).json({
+        id: booking.id,
+        userId: booking.userId,
+        hotelId: booking.hotelId,
+        status: booking.status,
+        createdAt: booking.createdAt,
+    });
+}
+
 export const confirmBookingHandler = async (req: Request, res: Response) => {
     const booking = await confirmBookingService(req.params.idempotencyKey);
 
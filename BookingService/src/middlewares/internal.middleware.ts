@@ -0,0 +1,34 @@
+import crypto from 'crypto';
+import { NextFunction, Request, Response } from 'express';
+import { serverConfig } from '../config';
+import logger from '../config/logger.config';
+import { UnauthorizedError } from '../utils/errors/app.error';
+
+export const INTERNAL_TOKEN_HEADER = 'x-internal-token';
+
+/**
+ * Lets only other services through, by the shared INTERNAL_SERVICE_TOKEN they send in the X-Internal-Token header.
+ * Requests are rejected when no token is configured.
+ */
+export const requireInternalToken = (req: Request, res: Response, next: NextFunction) => {
+    const expected = serverConfig.INTERNAL_SERVICE_TOKEN;
+    const provided = req.header(INTERNAL_TOKEN_HEADER);
+
+    if(!expected) {
+        logger.error('INTERNAL_SERVICE_TOKEN is not set, rejecting internal request');
+        return next(new UnauthorizedError('Internal authentication is not configured'));
+    }
+
+    if(!provided || !tokensMatch(provided, expected)) {
+        return next(new UnauthorizedError('Invalid internal service token'));
+    }
+
+    next();
+}
+
+function tokensMatch(provided: string, expected: string) {
+    // Compare digests so the comparison takes the same time whatever the lengths
+    const providedDigest = crypto.createHash('sha256').update(provided).digest();
+    const expectedDigest = crypto.createHash('sha256').update(expected).digest();
+    return crypto.timingSafeEqual(providedDigest, expectedDigest);
+}
@@ -1,12 +1,14 @@
 import express from 'express';
 import {  validateRequestBody } from '../../validators';
 import { createBookingSchema } from '../../validators/booking.validator';
-import { confirmBookingHandler, createBookingHandler } from '../../controllers/booking.controller';
+import { confirmBookingHandler, createBookingHandler, getBookingHandler } from '../../controllers/booking.controller';
+import { requireInternalToken } from '../../middlewares/internal.middleware';
 
 const bookingRouter = express.Router();
 
 bookingRouter.post('/', validateRequestBody(createBookingSchema), createBookingHandler);
 bookingRouter.post('/confirm/:idempotencyKey', confirmBookingHandler); 
+bookingRouter.get('/:bookingId', requireInternalToken, getBookingHandler); // Internal: ReviewService checks stays
 
 
 export default bookingRouter;
\ No newline at end of file
@@ -1,5 +1,5 @@
 import { CreateBookingDTO } from '../dto/booking.dto';
-import { confirmBooking, createBooking, createIdempotencyKey, finalizeIdempotencyKey, getIdempotencyKeyWithLock } from '../repositories/booking.repository';
+import { confirmBooking, createBooking, createIdempotencyKey, finalizeIdempotencyKey, getBookingById, getIdempotencyKeyWithLock } from '../repositories/booking.repository';
 import { BadRequestError, InternalServerError, NotFoundError } from '../utils/errors/app.error';
 import { generateIdempotencyKey } from '../utils/generateIdempotencyKey';
 
@@ -34,6 +34,20 @@ export async function createBookingService(createBookingDTO: CreateBookingDTO) {
     }
 }
 
+export async function getBookingByIdService(bookingId: number) {
+    if(!Number.isInteger(bookingId) || bookingId <= 0) {
+        throw new BadRequestError('Invalid booking id');
+    }
+
+    const booking = await getBookingById(bookingId);
+
+    if(!booking) {
+        throw new NotFoundError(`Booking with id ${bookingId} not found`);
+    }
+
+    return booking;
+}
+
 // Todo: explore the function for potential issues and improvements
 export async function confirmBookingService(idempotencyKey: string) {
 
@@ -6,6 +6,7 @@ type ServerConfig = {
     REDIS_PORT?: number,
     REDIS_HOST?: string,
     ROOM_CRON: string,
+    INTERNAL_SERVICE_TOKEN: string,
 }
 
 type DBConfig = {
@@ -28,6 +29,7 @@ export const serverConfig: ServerConfig = {
     REDIS_PORT: process.env.REDIS_PORT ? Number(process.env.REDIS_PORT) : 6379,
     REDIS_HOST: process.env.REDIS_HOST || 'localhost',
     ROOM_CRON: process.env.ROOM_CRON || '0 2 * * *',
+    INTERNAL_SERVICE_TOKEN: process.env.INTERNAL_SERVICE_TOKEN || '', // Shared with ReviewService, which pushes ratings
 };
 
 export const dbConfig: DBConfig = {
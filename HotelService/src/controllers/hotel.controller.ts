@@ -1,5 +1,5 @@
 import { Request, Response, NextFunction } from "express";
-import { createHotelService, deleteHotelService, getAllHotelsService, getHotelByIdService } from "../services/hotel.service";
+import { createHotelService, deleteHotelService, getAllHotelsService, getHotelAvailabilityService, getHotelByIdService, updateHotelRatingService } from "../services/hotel.service";
 import { StatusCodes } from "http-status-codes";
 
 export async function createHotelHandler(req: Request, res: Response, next: NextFunction) {
@@ -30,6 +30,23 @@ export async function getHotelByIdHandler(req: Request, res: Response, next: Nex
     })
 }
 
+export async function getHotelAvailabilityHandler(req: Request, res: Response, next: NextFunction) {
+    // 1. Call the service layer
+
+    const availabilityResponse = await getHotelAvailabilityService(
+        Number(req.params.id),
+        req.query.from as string | undefined,
+        req.query.to as string | undefined);
+
+    // 2. Send the response
+
+    res.status(StatusCodes.OK).json({
+        message: "Hotel availability found successfully",
+        data: availabilityResponse,
+        success: true,
+    })
+}
+
 export async function getAllHotelsHandler(req: Request, res: Response, next: NextFunction) {
 
     // 1. Call the service layer
@@ -60,6 +77,21 @@ export async function deleteHotelHandler(req: Request, res: Response, next: Next
     
 }
 
+export async function updateHotelRatingHandler(req: Request, res: Response, next: NextFunction) {
+
+    // 1. Call the service layer
+
+    const hotelResponse = await updateHotelRatingService(Number(req.params.id), req.body);
+
+    // 2. Send the response
+    res.status(StatusCodes.OK).json({
+        message: "Hotel rating updated successfully",
+        data: hotelResponse,
+        success: true,
+    });
+
+}
+
 export async function updateHotelHandler(req: Request, res: Response, next: NextFunction) {
 
     res.status(StatusCodes.NOT_IMPLEMENTED);
@@ -0,0 +1,18 @@
+import { QueryInterface } from "sequelize";
+
+module.exports = {
+  async up (queryInterface: QueryInterface) {
+
+    await queryInterface.sequelize.query(`
+      ALTER TABLE hotels
+      ADD COLUMN category_ratings JSON DEFAULT NULL
+    `);
+  },
+
+  async down (queryInterface: QueryInterface) {
+    await queryInterface.sequelize.query(`
+      ALTER TABLE hotels
+      DROP COLUMN category_ratings
+    `);
+  }
+};
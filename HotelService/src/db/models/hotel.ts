@@ -19,6 +19,7 @@ class Hotel extends Model<
   declare deletedAt: CreationOptional<Date | null>;
   declare rating?: number;
   declare ratingCount?: number;
+  declare categoryRatings?: Record<string, number> | null;
 }
 
 Hotel.init(
@@ -60,6 +61,10 @@ Hotel.init(
       type: 'INTEGER',
       defaultValue: null,
     },
+    categoryRatings: {
+      type: 'JSON',
+      defaultValue: null,
+    },
   },
   {
     tableName: 'hotels',
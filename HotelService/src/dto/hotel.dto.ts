@@ -4,4 +4,21 @@ export type createHotelDTO = {
     location: string;
     rating?: number;
     ratingCount?: number;
-}
\ No newline at end of file
+}
+
+export type updateHotelRatingDTO = {
+    rating: number;
+    ratingCount: number;
+    categoryRatings?: Record<string, number>;
+}
+
+export type hotelAvailabilityDTO = {
+    hotelId: number;
+    from: string; // YYYY-MM-DD, inclusive
+    to: string; // YYYY-MM-DD, exclusive
+    rooms: Array<{
+        roomCategoryId: number;
+        date: string;
+        price: number;
+    }>;
+}
@@ -38,4 +38,22 @@ export class HotelRepository extends BaseRepository<Hotel> {
         return true;
     }
 
+    async updateRating(id: number, rating: number, ratingCount: number, categoryRatings?: Record<string, number>) {
+        const hotel = await Hotel.findByPk(id);
+
+        if(!hotel) {
+            logger.error(`Hotel not found: ${id}`);
+            throw new NotFoundError(`Hotel with id ${id} not found`);
+        }
+
+        hotel.rating = rating;
+        hotel.ratingCount = ratingCount;
+        if(categoryRatings) {
+            hotel.categoryRatings = categoryRatings;
+        }
+        await hotel.save();
+        logger.info(`Hotel rating updated: ${hotel.id}`);
+        return hotel;
+    }
+
 }
\ No newline at end of file
@@ -1,4 +1,4 @@
-import { CreationAttributes } from "sequelize";
+import { CreationAttributes, Op } from "sequelize";
 import Room from "../db/models/room";
 import BaseRepository from "./base.repository";
 
@@ -55,4 +55,23 @@ export class RoomRepository extends BaseRepository<Room> {
             latestDate: new Date(result.latestDate)
         }));
     }
-}
\ No newline at end of file
+
+    /**
+     * Rooms of the hotel not yet booked on dates in [from, to), ordered by date and room category.
+     */
+    async findAvailableByHotelId(hotelId: number, from: Date, to: Date) {
+        return await this.model.findAll({
+            where: {
+                hotelId,
+                bookingId: null,
+                deletedAt: null,
+                dateOfAvailability: {
+                    [Op.gte]: from,
+                    [Op.lt]: to
+                }
+            },
+            attributes: ['roomCategoryId', 'dateOfAvailability', 'price'],
+            order: [['dateOfAvailability', 'ASC'], ['roomCategoryId', 'ASC']]
+        });
+    }
+}
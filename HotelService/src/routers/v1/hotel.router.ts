@@ -1,7 +1,8 @@
 import express from 'express';
-import { createHotelHandler, deleteHotelHandler, getAllHotelsHandler, getHotelByIdHandler } from '../../controllers/hotel.controller';
-import { validateRequestBody } from '../../validators';
-import { hotelSchema } from '../../validators/hotel.validator';
+import { createHotelHandler, deleteHotelHandler, getAllHotelsHandler, getHotelAvailabilityHandler, getHotelByIdHandler, updateHotelRatingHandler } from '../../controllers/hotel.controller';
+import { validateQueryParams, validateRequestBody } from '../../validators';
+import { hotelAvailabilitySchema, hotelRatingSchema, hotelSchema } from '../../validators/hotel.validator';
+import { requireInternalToken } from '../../middlewares/internal.middleware';
 
 const hotelRouter = express.Router();
 
@@ -12,8 +13,20 @@ hotelRouter.post(
 
 hotelRouter.get('/:id', getHotelByIdHandler); 
 
+hotelRouter.get(
+    '/:id/availability',
+    validateQueryParams(hotelAvailabilitySchema),
+    getHotelAvailabilityHandler);
+
 hotelRouter.get('/', getAllHotelsHandler);
 
 hotelRouter.delete('/:id', deleteHotelHandler);
 
+// Internal: only ReviewService updates ratings
+hotelRouter.put(
+    '/:id/rating',
+    requireInternalToken,
+    validateRequestBody(hotelRatingSchema),
+    updateHotelRatingHandler);
+
 export default hotelRouter;
\ No newline at end of file
@@ -1,8 +1,15 @@
-import { createHotelDTO } from "../dto/hotel.dto";
+import { createHotelDTO, hotelAvailabilityDTO, updateHotelRatingDTO } from "../dto/hotel.dto";
 import { HotelRepository } from "../repositories/hotel.repository";
+import { RoomRepository } from "../repositories/room.repository";
+import { BadRequestError, NotFoundError } from "../utils/errors/app.error";
 
 
 const hotelRepository = new HotelRepository();
+const roomRepository = new RoomRepository();
+
+const DEFAULT_AVAILABILITY_DAYS = 7;
+const MAX_AVAILABILITY_DAYS = 31;
+const DAY_MS = 24 * 60 * 60 * 1000;
 
 
 export async function createHotelService(hotelData: createHotelDTO) {
@@ -23,4 +30,49 @@ export async function getAllHotelsService() {
 export async function deleteHotelService(id: number) {
     const response = await hotelRepository.softDelete(id);
     return response;
-}
\ No newline at end of file
+}
+
+export async function updateHotelRatingService(id: number, ratingData: updateHotelRatingDTO) {
+    const hotel = await hotelRepository.updateRating(id, ratingData.rating, ratingData.ratingCount, ratingData.categoryRatings);
+    return hotel;
+}
+
+function parseDate(value: string, name: string): Date {
+    const date = new Date(`${value}T00:00:00.000Z`);
+    if (isNaN(date.getTime()) || date.toISOString().slice(0, 10) !== value) {
+        throw new BadRequestError(`${name} is not a valid date`);
+    }
+    return date;
+}
+
+/**
+ * Lists the hotel's unbooked rooms per date between from (inclusive) and to (exclusive).
+ * Defaults to the next seven days from today (UTC).
+ */
+export async function getHotelAvailabilityService(id: number, fromParam?: string, toParam?: string): Promise<hotelAvailabilityDTO> {
+    const from = fromParam ? parseDate(fromParam, "from") : parseDate(new Date().toISOString().slice(0, 10), "from");
+    const to = toParam ? parseDate(toParam, "to") : new Date(from.getTime() + DEFAULT_AVAILABILITY_DAYS * DAY_MS);
+
+    const days = (to.getTime() - from.getTime()) / DAY_MS;
+    if (days <= 0 || days > MAX_AVAILABILITY_DAYS) {
+        throw new BadRequestError(`to must be after from and at most ${MAX_AVAILABILITY_DAYS} days later`);
+    }
+
+    const hotel = await hotelRepository.findById(id);
+    if (!hotel || hotel.deletedAt) {
+        throw new NotFoundError(`Hotel with id ${id} not found`);
+    }
+
+    const rooms = await roomRepository.findAvailableByHotelId(id, from, to);
+
+    return {
+        hotelId: id,
+        from: from.toISOString().slice(0, 10),
+        to: to.toISOString().slice(0, 10),
+        rooms: rooms.map((room) => ({
+            roomCategoryId: room.roomCategoryId,
+            date: new Date(room.dateOfAvailability).toISOString().slice(0, 10),
+            price: room.price,
+        })),
+    };
+}
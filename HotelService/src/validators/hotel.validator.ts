@@ -6,4 +6,17 @@ export const hotelSchema = z.object({
     location : z.string().min(1),
     rating : z.number().optional(),
     ratingCount : z.number().optional(),
-});
\ No newline at end of file
+});
+
+export const hotelRatingSchema = z.object({
+    rating : z.number().min(0).max(5),
+    ratingCount : z.number().int().min(0),
+    categoryRatings : z.record(z.string(), z.number().min(0).max(5)).optional(),
+});
+
+const isoDate = z.string().regex(/^\d{4}-\d{2}-\d{2}$/, "Date must be YYYY-MM-DD");
+
+export const hotelAvailabilitySchema = z.object({
+    from : isoDate.optional(),
+    to : isoDate.optional(),
+});
@@ -1,20 +1,67 @@
 package app
 
 import (
+	"ReviewService/blobstore"
+	"ReviewService/clients"
 	dbConfig "ReviewService/config/db"
 	config "ReviewService/config/env"
 	"ReviewService/controllers"
 	repo "ReviewService/db/repositories"
+	"ReviewService/events"
+	"ReviewService/fraud"
+	"ReviewService/langid"
+	"ReviewService/middlewares"
+	"ReviewService/models"
+	"ReviewService/notifications"
 	"ReviewService/router"
+	"ReviewService/search"
+	"ReviewService/sentiment"
 	"ReviewService/services"
+	"ReviewService/utils"
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // Config holds the configuration for the server.
 type Config struct {
-	Addr string // PORT
+	Addr             string // PORT
+	JWTSecret        string
+	JWTPublicKeyPath string
+	InternalToken    string        // shared with BookingService and HotelService for internal endpoints
+	IdempotencyStale time.Duration // in-progress idempotency keys untouched this long are taken over by a retry
+	IdempotencyTTL   time.Duration // finished idempotency keys expire this long after their request
+	BookingService   string
+	BookingTimeout   time.Duration
+	HotelService     string
+	HotelTimeout     time.Duration
+	AuthService      string
+	AuthTimeout      time.Duration
+	Notifier         string // "mailer" or "log"
+	MailerQueue      string
+	RatingSync       services.RatingSyncConfig
+	RatingSyncPoll   time.Duration
+	RatingPrior      services.RatingPrior
+	Prescreen        services.PrescreenConfig
+	Photos           services.PhotoConfig
+	BlobStore        string // "local" or "s3"
+	BlobLocalDir     string
+	S3               blobstore.S3Config
+	Retention        services.RetentionConfig
+	RetentionPoll    time.Duration
+	EventBroker      string // "bullmq" or "memory"
+	BullMQ           events.BullMQConfig
+	Outbox           services.OutboxRelayConfig
+	OutboxPoll       time.Duration
+	SentimentBatch   int // reviews per batch of the sentiment backfill
+	Reports          services.ReportConfig
+	Import           services.ImportConfig
+	ExportBatch      int // reviews read per query of an export
+	Fraud            services.FraudConfig
+	FingerprintBatch int // reviews per batch of the fingerprint backfill
+	LanguageBatch    int // reviews per batch of the language backfill
 }
 
 type Application struct {
@@ -27,7 +74,144 @@ func NewConfig() Config {
 	port := config.GetString("PORT", ":8081")
 
 	return Config{
-		Addr: port,
+		Addr:             port,
+		JWTSecret:        config.GetString("JWT_SECRET", ""),
+		JWTPublicKeyPath: config.GetString("JWT_PUBLIC_KEY_PATH", ""),
+		InternalToken:    config.GetString("INTERNAL_SERVICE_TOKEN", ""),
+		IdempotencyStale: time.Duration(config.GetInt("IDEMPOTENCY_STALE_SECONDS", 60)) * time.Second,
+		IdempotencyTTL:   time.Duration(config.GetInt("IDEMPOTENCY_KEY_TTL_HOURS", 24)) * time.Hour,
+		BookingService:   config.GetString("BOOKING_SERVICE_URL", "http://localhost:3002"),
+		BookingTimeout:   time.Duration(config.GetInt("BOOKING_SERVICE_TIMEOUT_MS", 2000)) * time.Millisecond,
+		HotelService:     config.GetString("HOTEL_SERVICE_URL", "http://localhost:3000"),
+		HotelTimeout:     time.Duration(config.GetInt("HOTEL_SERVICE_TIMEOUT_MS", 2000)) * time.Millisecond,
+		AuthService:      config.GetString("AUTH_SERVICE_URL", "http://localhost:3001"),
+		AuthTimeout:      time.Duration(config.GetInt("AUTH_SERVICE_TIMEOUT_MS", 2000)) * time.Millisecond,
+		Notifier:         config.GetString("NOTIFIER", "mailer"),
+		MailerQueue:      config.GetString("MAILER_QUEUE", notifications.MailerQueue),
+		RatingSync: services.RatingSyncConfig{
+			BatchSize:       config.GetInt("RATING_SYNC_BATCH_SIZE", 50),
+			MaxAttempts:     config.GetInt("RATING_SYNC_MAX_ATTEMPTS", 3),
+			RetryBackoff:    time.Duration(config.GetInt("RATING_SYNC_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+			FailureDelay:    time.Duration(config.GetInt("RATING_SYNC_FAILURE_DELAY_SECONDS", 60)) * time.Second,
+			MaxFailureDelay: time.Duration(config.GetInt("RATING_SYNC_MAX_FAILURE_DELAY_SECONDS", 3600)) * time.Second,
+		},
+		RatingSyncPoll: time.Duration(config.GetInt("RATING_SYNC_INTERVAL_SECONDS", 30)) * time.Second,
+		RatingPrior: services.RatingPrior{
+			Mean:   config.GetFloat("RATING_PRIOR_MEAN", 3.5),
+			Weight: config.GetFloat("RATING_PRIOR_WEIGHT", 10),
+		},
+		Prescreen: services.PrescreenConfig{
+			BannedTerms: strings.Split(config.GetString("MODERATION_BANNED_TERMS", ""), ","),
+			AutoApprove: config.GetBool("MODERATION_AUTO_APPROVE", true),
+		},
+		Photos: services.PhotoConfig{
+			MaxBytes:      int64(config.GetInt("PHOTO_MAX_BYTES", 5<<20)),
+			MaxPerReview:  config.GetInt("PHOTO_MAX_PER_REVIEW", 10),
+			MaxPixels:     config.GetInt("PHOTO_MAX_PIXELS", 40_000_000),
+			ThumbnailSize: config.GetInt("PHOTO_THUMBNAIL_SIZE", 320),
+		},
+		BlobStore:    config.GetString("BLOB_STORE", "local"),
+		BlobLocalDir: config.GetString("BLOB_LOCAL_DIR", "./uploads"),
+		S3: blobstore.S3Config{
+			Endpoint:  config.GetString("S3_ENDPOINT", ""),
+			Region:    config.GetString("S3_REGION", "us-east-1"),
+			Bucket:    config.GetString("S3_BUCKET", ""),
+			AccessKey: config.GetString("S3_ACCESS_KEY", ""),
+			SecretKey: config.GetString("S3_SECRET_KEY", ""),
+			Timeout:   time.Duration(config.GetInt("S3_TIMEOUT_MS", 10000)) * time.Millisecond,
+		},
+		Retention: services.RetentionConfig{
+			RetentionDays: config.GetInt("RETENTION_DAYS", 90),
+			BatchSize:     config.GetInt("RETENTION_BATCH_SIZE", 100),
+			DryRun:        config.GetBool("RETENTION_DRY_RUN", false),
+		},
+		RetentionPoll: time.Duration(config.GetInt("RETENTION_INTERVAL_HOURS", 24)) * time.Hour,
+		EventBroker:   config.GetString("EVENT_BROKER", "bullmq"),
+		BullMQ: events.BullMQConfig{
+			Addr:     config.GetString("REDIS_ADDR", "localhost:6379"),
+			Password: config.GetString("REDIS_PASSWORD", ""),
+			DB:       config.GetInt("REDIS_DB", 0),
+			Queue:    config.GetString("REVIEW_EVENTS_QUEUE", "queue-review-events"),
+			Attempts: config.GetInt("REVIEW_EVENTS_JOB_ATTEMPTS", 3),
+			Timeout:  time.Duration(config.GetInt("REDIS_TIMEOUT_MS", 2000)) * time.Millisecond,
+		},
+		Outbox: services.OutboxRelayConfig{
+			BatchSize:     config.GetInt("OUTBOX_BATCH_SIZE", 100),
+			KeepPublished: time.Duration(config.GetInt("OUTBOX_KEEP_PUBLISHED_HOURS", 168)) * time.Hour,
+		},
+		OutboxPoll:     time.Duration(config.GetInt("OUTBOX_INTERVAL_MS", 1000)) * time.Millisecond,
+		SentimentBatch: config.GetInt("SENTIMENT_BACKFILL_BATCH_SIZE", 500),
+		Reports: services.ReportConfig{
+			HideThreshold: config.GetFloat("REPORT_HIDE_THRESHOLD", 3),
+			ReasonWeights: map[string]float64{
+				models.ReportSpam:               config.GetFloat("REPORT_WEIGHT_SPAM", 1),
+				models.ReportOffensive:          config.GetFloat("REPORT_WEIGHT_OFFENSIVE", 1.5),
+				models.ReportIrrelevant:         config.GetFloat("REPORT_WEIGHT_IRRELEVANT", 0.5),
+				models.ReportConflictOfInterest: config.GetFloat("REPORT_WEIGHT_CONFLICT_OF_INTEREST", 1),
+			},
+			HostWeight: config.GetFloat("REPORT_HOST_WEIGHT", 0.5),
+		},
+		Import: services.ImportConfig{
+			BatchSize: config.GetInt("IMPORT_BATCH_SIZE", 500),
+			MaxErrors: config.GetInt("IMPORT_MAX_ERRORS", 1000),
+			MaxBytes:  int64(config.GetInt("IMPORT_MAX_BYTES", 50<<20)),
+		},
+		ExportBatch: config.GetInt("EXPORT_BATCH_SIZE", 1000),
+		Fraud: services.FraudConfig{
+			HoldThreshold: config.GetFloat("FRAUD_HOLD_THRESHOLD", 0.5),
+			Weights: map[string]float64{
+				fraud.SignalBurst:         config.GetFloat("FRAUD_WEIGHT_BURST", 0.6),
+				fraud.SignalDuplicateText: config.GetFloat("FRAUD_WEIGHT_DUPLICATE_TEXT", 0.8),
+				fraud.SignalRatingOutlier: config.GetFloat("FRAUD_WEIGHT_RATING_OUTLIER", 0.35),
+				fraud.SignalQuickReview:   config.GetFloat("FRAUD_WEIGHT_QUICK_REVIEW", 0.35),
+			},
+			BurstWindow:       time.Duration(config.GetInt("FRAUD_BURST_WINDOW_MINUTES", 60)) * time.Minute,
+			BurstCount:        config.GetInt("FRAUD_BURST_COUNT", 3),
+			DuplicateLow:      config.GetFloat("FRAUD_DUPLICATE_MIN_SIMILARITY", 0.5),
+			DuplicateHigh:     config.GetFloat("FRAUD_DUPLICATE_MAX_SIMILARITY", 0.9),
+			OutlierMinHistory: config.GetInt("FRAUD_OUTLIER_MIN_HISTORY", 3),
+			QuickReviewWindow: time.Duration(config.GetInt("FRAUD_QUICK_REVIEW_MINUTES", 60)) * time.Minute,
+		},
+		FingerprintBatch: config.GetInt("FINGERPRINT_BACKFILL_BATCH_SIZE", 500),
+		LanguageBatch:    config.GetInt("LANGUAGE_BACKFILL_BATCH_SIZE", 500),
+	}
+}
+
+// newBlobStore builds the blob store selected by BLOB_STORE.
+func newBlobStore(cfg Config) (blobstore.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "local":
+		return blobstore.NewLocalBlobStore(cfg.BlobLocalDir)
+	case "s3":
+		return blobstore.NewS3BlobStore(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", cfg.BlobStore)
+	}
+}
+
+// newEventBroker builds the broker selected by EVENT_BROKER.
+func newEventBroker(cfg Config) (events.Broker, error) {
+	switch cfg.EventBroker {
+	case "bullmq":
+		return events.NewBullMQBroker(cfg.BullMQ), nil
+	case "memory":
+		return events.NewInMemoryBroker(), nil
+	default:
+		return nil, fmt.Errorf("unknown EVENT_BROKER %q", cfg.EventBroker)
+	}
+}
+
+// newNotifier builds the notifier selected by NOTIFIER. The mailer queue lives in the Redis of the BullMQ broker.
+func newNotifier(cfg Config) (notifications.Notifier, error) {
+	switch cfg.Notifier {
+	case "mailer":
+		users := clients.NewHttpUserClient(cfg.AuthService, cfg.InternalToken, cfg.AuthTimeout)
+		queue := events.NewBullMQQueue(events.NewRedisClient(cfg.BullMQ), cfg.BullMQ.Prefix, cfg.MailerQueue)
+		return notifications.NewMailerNotifier(users, queue, cfg.BullMQ.Attempts), nil
+	case "log":
+		return notifications.NewLogNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unknown NOTIFIER %q", cfg.Notifier)
 	}
 }
 
@@ -47,14 +231,79 @@ func (app *Application) Run() error {
 		return err
 	}
 
+	verifier, err := utils.NewTokenVerifier(app.Config.JWTPublicKeyPath, app.Config.JWTSecret)
+	if err != nil {
+		fmt.Println("Error setting up token verifier:", err)
+		return err
+	}
+
+	blobs, err := newBlobStore(app.Config)
+	if err != nil {
+		fmt.Println("Error setting up blob store:", err)
+		return err
+	}
+
+	broker, err := newEventBroker(app.Config)
+	if err != nil {
+		fmt.Println("Error setting up event broker:", err)
+		return err
+	}
+
+	notifier, err := newNotifier(app.Config)
+	if err != nil {
+		fmt.Println("Error setting up notifier:", err)
+		return err
+	}
+
 	rr := repo.NewReviewRepository(db)
-	rs := services.NewReviewService(rr)
+	bc := clients.NewHttpBookingClient(app.Config.BookingService, app.Config.InternalToken, app.Config.BookingTimeout)
+	si := search.NewMySQLReviewSearchIndex(db)
+	rpr := repo.NewReviewResponseRepository(db)
+	prr := repo.NewReviewPhotoRepository(db)
+	pre := services.NewReviewPrescreener(app.Config.Prescreen)
+	sa := sentiment.NewAnalyzer()
+	lid := langid.NewIdentifier()
+	rs := services.NewReviewService(rr, services.NewReviewDetailsLoader(rr, rpr, prr), bc, si, pre, sa, services.NewFraudScreener(rr, app.Config.Fraud), lid)
 	rc := controllers.NewReviewController(rs)
-	rRouter := router.NewReviewRouter(rc)
+
+	authMiddleware := middlewares.NewJWTAuthMiddleware(verifier)
+	ha := services.NewClaimsHotelAuthorizer()
+	rps := services.NewReviewResponseService(rr, rpr, ha, notifier)
+	rpRouter := router.NewReviewResponseRouter(controllers.NewReviewResponseController(rps), authMiddleware)
+	vs := services.NewReviewVoteService(rr, repo.NewReviewVoteRepository(db))
+	vRouter := router.NewReviewVoteRouter(controllers.NewReviewVoteController(vs), authMiddleware)
+	ps := services.NewReviewPhotoService(rr, prr, blobs, app.Config.Photos)
+	pRouter := router.NewReviewPhotoRouter(controllers.NewReviewPhotoController(ps, app.Config.Photos.MaxRequestBytes()), authMiddleware)
+	rrr := repo.NewReviewReportRepository(db)
+	mRouter := router.NewModerationRouter(controllers.NewModerationController(services.NewModerationService(rr, rrr, si)), authMiddleware)
+	reps := services.NewReviewReportService(rr, rrr, ha, si, app.Config.Reports)
+	repRouter := router.NewReviewReportRouter(controllers.NewReviewReportController(reps), authMiddleware)
+
+	hrs := services.NewHotelRatingService(rr, app.Config.RatingPrior)
+	hRouter := router.NewHotelRouter(controllers.NewHotelController(hrs))
+
+	hc := clients.NewHttpHotelClient(app.Config.HotelService, app.Config.InternalToken, app.Config.HotelTimeout)
+	rss := services.NewRatingSyncService(rr, hc, app.Config.RatingSync)
+	go rss.Run(context.Background(), app.Config.RatingSyncPoll)
+
+	ir := repo.NewIdempotencyKeyRepository(db)
+	retention := app.Config.Retention
+	retention.IdempotencyKeyTTL = app.Config.IdempotencyTTL
+	rts := services.NewRetentionService(rr, ir, blobs, retention)
+	go rts.Run(context.Background(), app.Config.RetentionPoll)
+	aRouter := router.NewReviewAdminRouter(controllers.NewReviewAdminController(services.NewReviewAdminService(rr, blobs, si, rts)), authMiddleware)
+	ims := services.NewReviewImportService(rr, si, pre, sa, lid, app.Config.Import)
+	exs := services.NewReviewExportService(rr, app.Config.ExportBatch)
+	tRouter := router.NewReviewTransferRouter(controllers.NewReviewTransferController(ims, exs, app.Config.Import.MaxBytes), authMiddleware)
+
+	relay := services.NewOutboxRelay(repo.NewOutboxRepository(db), broker, app.Config.Outbox)
+	go relay.Run(context.Background(), app.Config.OutboxPoll)
+
+	rRouter := router.NewReviewRouter(rc, authMiddleware, middlewares.NewIdempotencyMiddleware(ir, app.Config.IdempotencyStale, app.Config.IdempotencyTTL))
 
 	server := &http.Server{
 		Addr:         app.Config.Addr,
-		Handler:      router.SetupRouter(rRouter),
+		Handler:      router.SetupRouter(rRouter, hRouter, mRouter, rpRouter, vRouter, pRouter, aRouter, repRouter, tRouter),
 		ReadTimeout:  10 * time.Second, // Set read timeout to 10 seconds
 		WriteTimeout: 10 * time.Second, // Set write timeout to 10 seconds
 	}
@@ -63,3 +312,41 @@ func (app *Application) Run() error {
 
 	return server.ListenAndServe()
 }
+
+// RunSentimentBackfill analyzes the reviews stored without a current sentiment analysis, then returns.
+// It is safe to run while the service is serving requests, and to run again after an interruption.
+func (app *Application) RunSentimentBackfill() error {
+	return runBackfill("sentiment", app.Config.SentimentBatch, func(reviewRepository repo.ReviewRepository) services.BackfillService {
+		return services.NewSentimentBackfillService(reviewRepository, sentiment.NewAnalyzer())
+	})
+}
+
+// RunFingerprintBackfill fingerprints the reviews stored without one, so new reviews are checked against them
+// for near-duplicate text. It is safe to run while the service is serving requests, and to run again.
+func (app *Application) RunFingerprintBackfill() error {
+	return runBackfill("fingerprint", app.Config.FingerprintBatch, services.NewFingerprintBackfillService)
+}
+
+// RunLanguageBackfill identifies the language of the reviews stored without one, so language filters and ordering
+// see them. It is safe to run while the service is serving requests, and to run again.
+func (app *Application) RunLanguageBackfill() error {
+	return runBackfill("language", app.Config.LanguageBatch, func(reviewRepository repo.ReviewRepository) services.BackfillService {
+		return services.NewLanguageBackfillService(reviewRepository, langid.NewIdentifier())
+	})
+}
+
+// runBackfill connects to the database and runs the backfill newBackfill builds on it, which reports its progress.
+func runBackfill(name string, batchSize int, newBackfill func(repo.ReviewRepository) services.BackfillService) error {
+	db, err := dbConfig.SetupDB()
+	if err != nil {
+		fmt.Println("Error setting up database:", err)
+		return err
+	}
+	defer db.Close()
+
+	if _, err := newBackfill(repo.NewReviewRepository(db)).Backfill(batchSize); err != nil {
+		fmt.Printf("Error running the %s backfill: %v\n", name, err)
+		return err
+	}
+	return nil
+}
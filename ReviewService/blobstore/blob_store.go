@@ -0,0 +1,18 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is returned by Get and Delete when no blob is stored under the key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps binary objects such as review photos. Keys are slash-separated paths like "reviews/12/ab34.jpg".
+type BlobStore interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// Get returns the blob's content and content type. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, string, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// InternalTokenHeader carries the shared INTERNAL_SERVICE_TOKEN on calls to internal-only endpoints of other services.
+const InternalTokenHeader = "X-Internal-Token"
+
+const (
+	BookingStatusPending   = "PENDING"
+	BookingStatusConfirmed = "CONFIRMED"
+	BookingStatusCancelled = "CANCELLED"
+)
+
+// Booking mirrors the fields of BookingService's Booking model that ReviewService relies on.
+type Booking struct {
+	Id        int64     `json:"id"`
+	UserId    int64     `json:"userId"`
+	HotelId   int64     `json:"hotelId"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BookingClient looks up bookings in BookingService. GetBooking returns nil, nil when the booking does not exist.
+type BookingClient interface {
+	GetBooking(bookingId int64) (*Booking, error)
+}
+
+type HttpBookingClient struct {
+	baseUrl       string
+	internalToken string
+	client        *http.Client
+}
+
+func NewHttpBookingClient(_baseUrl string, _internalToken string, timeout time.Duration) BookingClient {
+	return &HttpBookingClient{
+		baseUrl:       strings.TrimSuffix(_baseUrl, "/"),
+		internalToken: _internalToken,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (b *HttpBookingClient) GetBooking(bookingId int64) (*Booking, error) {
+	url := fmt.Sprintf("%s/api/v1/bookings/%d", b.baseUrl, bookingId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(InternalTokenHeader, b.internalToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		fmt.Println("Error calling BookingService:", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("booking service responded with status %d", resp.StatusCode)
+	}
+
+	booking := &Booking{}
+	if err := json.NewDecoder(resp.Body).Decode(booking); err != nil {
+		fmt.Println("Error decoding booking:", err)
+		return nil, err
+	}
+
+	return booking, nil
+}
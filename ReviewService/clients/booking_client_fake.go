@@ -0,0 +1,37 @@
+package clients
+
+import "sync"
+
+// InMemoryBookingClient is a BookingClient backed by a map, for tests and local development.
+type InMemoryBookingClient struct {
+	mu       sync.RWMutex
+	bookings map[int64]*Booking
+}
+
+func NewInMemoryBookingClient(bookings ...*Booking) *InMemoryBookingClient {
+	c := &InMemoryBookingClient{
+		bookings: map[int64]*Booking{},
+	}
+	for _, booking := range bookings {
+		c.Add(booking)
+	}
+	return c
+}
+
+func (c *InMemoryBookingClient) Add(booking *Booking) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bookings[booking.Id] = booking
+}
+
+func (c *InMemoryBookingClient) GetBooking(bookingId int64) (*Booking, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	booking, ok := c.bookings[bookingId]
+	if !ok {
+		return nil, nil
+	}
+	copied := *booking
+	return &copied, nil
+}
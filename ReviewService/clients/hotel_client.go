@@ -0,0 +1,70 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrHotelNotFound is returned when HotelService has no hotel with the given id.
+var ErrHotelNotFound = errors.New("hotel not found")
+
+// HotelClient pushes review aggregates to HotelService.
+type HotelClient interface {
+	// UpdateRating pushes the overall average and count, and the average of each category that has ratings.
+	UpdateRating(hotelId int64, rating float64, ratingCount int64, categoryRatings map[string]float64) error
+}
+
+type HttpHotelClient struct {
+	baseUrl       string
+	internalToken string
+	client        *http.Client
+}
+
+func NewHttpHotelClient(_baseUrl string, _internalToken string, timeout time.Duration) HotelClient {
+	return &HttpHotelClient{
+		baseUrl:       strings.TrimSuffix(_baseUrl, "/"),
+		internalToken: _internalToken,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HttpHotelClient) UpdateRating(hotelId int64, rating float64, ratingCount int64, categoryRatings map[string]float64) error {
+	url := fmt.Sprintf("%s/api/v1/hotels/%d/rating", h.baseUrl, hotelId)
+
+	body, err := json.Marshal(map[string]any{
+		"rating":          rating,
+		"ratingCount":     ratingCount,
+		"categoryRatings": categoryRatings,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(InternalTokenHeader, h.internalToken)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		fmt.Println("Error calling HotelService:", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrHotelNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("hotel service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
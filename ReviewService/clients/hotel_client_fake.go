@@ -0,0 +1,56 @@
+package clients
+
+import "sync"
+
+// HotelRating is the last rating pushed for a hotel through the InMemoryHotelClient.
+type HotelRating struct {
+	Rating          float64
+	RatingCount     int64
+	CategoryRatings map[string]float64
+}
+
+// InMemoryHotelClient is a HotelClient that records pushed ratings, for tests and local development.
+// Hotels must be registered with AddHotel, otherwise UpdateRating returns ErrHotelNotFound.
+type InMemoryHotelClient struct {
+	mu      sync.RWMutex
+	ratings map[int64]*HotelRating
+}
+
+func NewInMemoryHotelClient(hotelIds ...int64) *InMemoryHotelClient {
+	c := &InMemoryHotelClient{
+		ratings: map[int64]*HotelRating{},
+	}
+	for _, id := range hotelIds {
+		c.AddHotel(id)
+	}
+	return c
+}
+
+func (c *InMemoryHotelClient) AddHotel(hotelId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ratings[hotelId] = &HotelRating{}
+}
+
+func (c *InMemoryHotelClient) UpdateRating(hotelId int64, rating float64, ratingCount int64, categoryRatings map[string]float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.ratings[hotelId]; !ok {
+		return ErrHotelNotFound
+	}
+	c.ratings[hotelId] = &HotelRating{Rating: rating, RatingCount: ratingCount, CategoryRatings: categoryRatings}
+	return nil
+}
+
+func (c *InMemoryHotelClient) GetRating(hotelId int64) *HotelRating {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rating, ok := c.ratings[hotelId]
+	if !ok {
+		return nil
+	}
+	copied := *rating
+	return &copied
+}
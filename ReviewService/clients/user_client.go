@@ -0,0 +1,72 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// User is a user's contact as served by AuthInGo's internal user lookup.
+type User struct {
+	Id    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+// UserClient looks up users in AuthInGo. GetUser returns nil, nil when the user does not exist.
+type UserClient interface {
+	GetUser(userId int64) (*User, error)
+}
+
+type HttpUserClient struct {
+	baseUrl       string
+	internalToken string
+	client        *http.Client
+}
+
+func NewHttpUserClient(_baseUrl string, _internalToken string, timeout time.Duration) UserClient {
+	return &HttpUserClient{
+		baseUrl:       strings.TrimSuffix(_baseUrl, "/"),
+		internalToken: _internalToken,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (u *HttpUserClient) GetUser(userId int64) (*User, error) {
+	url := fmt.Sprintf("%s/internal/users/%d", u.baseUrl, userId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(InternalTokenHeader, u.internalToken)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		fmt.Println("Error calling AuthInGo:", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth service responded with status %d", resp.StatusCode)
+	}
+
+	// AuthInGo wraps its payloads in {"status", "message", "data"}
+	body := struct {
+		Data *User `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		fmt.Println("Error decoding user:", err)
+		return nil, err
+	}
+	if body.Data == nil {
+		return nil, fmt.Errorf("auth service returned no user for ID %d", userId)
+	}
+
+	return body.Data, nil
+}
@@ -0,0 +1,37 @@
+package clients
+
+import "sync"
+
+// InMemoryUserClient is a UserClient backed by a map, for tests and local development.
+type InMemoryUserClient struct {
+	mu    sync.RWMutex
+	users map[int64]*User
+}
+
+func NewInMemoryUserClient(users ...*User) *InMemoryUserClient {
+	c := &InMemoryUserClient{
+		users: map[int64]*User{},
+	}
+	for _, user := range users {
+		c.Add(user)
+	}
+	return c
+}
+
+func (c *InMemoryUserClient) Add(user *User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[user.Id] = user
+}
+
+func (c *InMemoryUserClient) GetUser(userId int64) (*User, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	user, ok := c.users[userId]
+	if !ok {
+		return nil, nil
+	}
+	copied := *user
+	return &copied, nil
+}
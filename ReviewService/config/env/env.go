@@ -62,3 +62,20 @@ func GetBool(key string, fallback bool) bool {
 
 	return boolValue
 }
+
+func GetFloat(key string, fallback float64) float64 {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return fallback
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		fmt.Printf("Error converting %s to float: %v\n", key, err)
+		return fallback
+	}
+
+	return floatValue
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HotelController struct {
+	HotelRatingService services.HotelRatingService
+}
+
+func NewHotelController(_hotelRatingService services.HotelRatingService) *HotelController {
+	return &HotelController{
+		HotelRatingService: _hotelRatingService,
+	}
+}
+
+func (hc *HotelController) GetRatingSummary(w http.ResponseWriter, r *http.Request) {
+	hotelId := chi.URLParam(r, "id")
+	if hotelId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Hotel ID is required", fmt.Errorf("missing hotel ID"))
+		return
+	}
+
+	summary, err := hc.HotelRatingService.GetRatingSummary(hotelId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch rating summary", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Rating summary fetched successfully", summary)
+}
+
+func (hc *HotelController) GetAspectSummary(w http.ResponseWriter, r *http.Request) {
+	hotelId := chi.URLParam(r, "id")
+	if hotelId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Hotel ID is required", fmt.Errorf("missing hotel ID"))
+		return
+	}
+
+	summary, err := hc.HotelRatingService.GetAspectSummary(hotelId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch aspect summary", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Aspect summary fetched successfully", summary)
+}
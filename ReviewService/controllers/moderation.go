@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ModerationController struct {
+	ModerationService services.ModerationService
+}
+
+func NewModerationController(_moderationService services.ModerationService) *ModerationController {
+	return &ModerationController{
+		ModerationService: _moderationService,
+	}
+}
+
+func (mc *ModerationController) ListReviews(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Fetching moderation queue in ModerationController")
+
+	query, err := reviewListQueryFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	page, err := mc.ModerationService.ListReviews(r.URL.Query().Get("status"), query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch moderation queue", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Moderation queue fetched successfully", page)
+}
+
+func (mc *ModerationController) ListReportedReviews(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Fetching reported reviews in ModerationController")
+
+	query, err := reviewListQueryFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	page, err := mc.ModerationService.ListReported(r.URL.Query().Get("status"), query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch reported reviews", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reported reviews fetched successfully", page)
+}
+
+func (mc *ModerationController) ApproveReview(w http.ResponseWriter, r *http.Request) {
+	mc.moderate(w, r, mc.ModerationService.Approve, "approve")
+}
+
+func (mc *ModerationController) RejectReview(w http.ResponseWriter, r *http.Request) {
+	mc.moderate(w, r, mc.ModerationService.Reject, "reject")
+}
+
+func (mc *ModerationController) HideReview(w http.ResponseWriter, r *http.Request) {
+	mc.moderate(w, r, mc.ModerationService.Hide, "hide")
+}
+
+type moderationDecision func(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error)
+
+func (mc *ModerationController) moderate(w http.ResponseWriter, r *http.Request, decide moderationDecision, action string) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.ModerateReviewRequestDTO)
+	moderator := r.Context().Value("authUser").(*models.AuthUser)
+
+	review, err := decide(reviewId, moderator, &payload)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to "+action+" review", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review moderated successfully", review)
+}
+
+func (mc *ModerationController) GetReviewHistory(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	history, err := mc.ModerationService.GetHistory(reviewId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch review history", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review history fetched successfully", history)
+}
+
+func (mc *ModerationController) GetFraudChecks(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	checks, err := mc.ModerationService.GetFraudChecks(reviewId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch fraud checks", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Fraud checks fetched successfully", checks)
+}
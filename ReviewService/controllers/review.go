@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"ReviewService/dto"
+	"ReviewService/models"
 	"ReviewService/services"
 	"ReviewService/utils"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -44,13 +46,14 @@ func (rc *ReviewController) GetReviewById(w http.ResponseWriter, r *http.Request
 
 func (rc *ReviewController) CreateReview(w http.ResponseWriter, r *http.Request) {
 	payload := r.Context().Value("payload").(dto.CreateReviewRequestDTO)
+	author := r.Context().Value("authUser").(*models.AuthUser)
 
 	fmt.Println("Payload received:", payload)
 
-	review, err := rc.ReviewService.CreateReview(&payload)
+	review, err := rc.ReviewService.CreateReview(author, &payload)
 
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to create review", err)
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to create review", err)
 		return
 	}
 
@@ -68,13 +71,14 @@ func (rc *ReviewController) UpdateReview(w http.ResponseWriter, r *http.Request)
 	}
 
 	payload := r.Context().Value("payload").(dto.UpdateReviewRequestDTO)
+	caller := r.Context().Value("authUser").(*models.AuthUser)
 
 	fmt.Println("Payload received:", payload)
 
-	review, err := rc.ReviewService.UpdateReview(reviewId, &payload)
+	review, err := rc.ReviewService.UpdateReview(reviewId, caller, &payload)
 
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to update review", err)
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to update review", err)
 		return
 	}
 
@@ -91,10 +95,12 @@ func (rc *ReviewController) DeleteReview(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := rc.ReviewService.DeleteReview(reviewId)
+	caller := r.Context().Value("authUser").(*models.AuthUser)
+
+	err := rc.ReviewService.DeleteReview(reviewId, caller)
 
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to delete review", err)
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to delete review", err)
 		return
 	}
 
@@ -105,14 +111,23 @@ func (rc *ReviewController) DeleteReview(w http.ResponseWriter, r *http.Request)
 func (rc *ReviewController) GetAllReviews(w http.ResponseWriter, r *http.Request) {
 	fmt.Println("Fetching all reviews in ReviewController")
 
-	reviews, err := rc.ReviewService.GetAllReviews()
+	query, err := reviewListQueryFromRequest(r)
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reviews", err)
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
 		return
 	}
 
-	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", reviews)
-	fmt.Println("Reviews fetched successfully, count:", len(reviews))
+	// The order depends on Accept-Language, so caches must key on it
+	w.Header().Add("Vary", "Accept-Language")
+
+	page, err := rc.ReviewService.GetAllReviews(query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch reviews", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", page)
+	fmt.Println("Reviews fetched successfully, count:", len(page.Reviews))
 }
 
 func (rc *ReviewController) GetReviewsByUserId(w http.ResponseWriter, r *http.Request) {
@@ -124,14 +139,22 @@ func (rc *ReviewController) GetReviewsByUserId(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	reviews, err := rc.ReviewService.GetReviewsByUserId(userId)
+	query, err := reviewListQueryFromRequest(r)
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reviews by user ID", err)
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
 		return
 	}
 
-	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", reviews)
-	fmt.Println("Reviews fetched successfully for user ID:", userId, "count:", len(reviews))
+	w.Header().Add("Vary", "Accept-Language")
+
+	page, err := rc.ReviewService.GetReviewsByUserId(userId, query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch reviews by user ID", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", page)
+	fmt.Println("Reviews fetched successfully for user ID:", userId, "count:", len(page.Reviews))
 }
 
 func (rc *ReviewController) GetReviewsByHotelId(w http.ResponseWriter, r *http.Request) {
@@ -143,14 +166,22 @@ func (rc *ReviewController) GetReviewsByHotelId(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	reviews, err := rc.ReviewService.GetReviewsByHotelId(hotelId)
+	query, err := reviewListQueryFromRequest(r)
 	if err != nil {
-		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reviews by hotel ID", err)
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
 		return
 	}
 
-	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", reviews)
-	fmt.Println("Reviews fetched successfully for hotel ID:", hotelId, "count:", len(reviews))
+	w.Header().Add("Vary", "Accept-Language")
+
+	page, err := rc.ReviewService.GetReviewsByHotelId(hotelId, query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch reviews by hotel ID", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", page)
+	fmt.Println("Reviews fetched successfully for hotel ID:", hotelId, "count:", len(page.Reviews))
 }
 
 func (rc *ReviewController) GetReviewsByBookingId(w http.ResponseWriter, r *http.Request) {
@@ -171,3 +202,76 @@ func (rc *ReviewController) GetReviewsByBookingId(w http.ResponseWriter, r *http
 	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews fetched successfully", reviews)
 	fmt.Println("Reviews fetched successfully for booking ID:", bookingId, "count:", len(reviews))
 }
+
+func (rc *ReviewController) SearchReviews(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Searching reviews in ReviewController")
+
+	values := r.URL.Query()
+	query := &dto.ReviewSearchQuery{
+		Q:       values.Get("q"),
+		HotelId: values.Get("hotel_id"),
+	}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", fmt.Errorf("limit must be an integer"))
+			return
+		}
+		query.Limit = limit
+	}
+
+	hits, err := rc.ReviewService.SearchReviews(query)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to search reviews", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews searched successfully", hits)
+	fmt.Println("Reviews searched successfully, count:", len(hits))
+}
+
+// reviewListQueryFromRequest reads the pagination, sorting and filter query parameters shared by review listings.
+func reviewListQueryFromRequest(r *http.Request) (*dto.ReviewListQuery, error) {
+	values := r.URL.Query()
+	query := &dto.ReviewListQuery{
+		Cursor:         values.Get("cursor"),
+		Sort:           values.Get("sort"),
+		From:           values.Get("from"),
+		To:             values.Get("to"),
+		Lang:           values.Get("lang"),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	}
+
+	ints := map[string]*int{
+		"limit":      &query.Limit,
+		"min_rating": &query.MinRating,
+		"max_rating": &query.MaxRating,
+	}
+	for name, target := range ints {
+		if raw := values.Get(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an integer", name)
+			}
+			*target = value
+		}
+	}
+
+	if raw := values.Get("has_comment"); raw != "" {
+		hasComment, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("has_comment must be true or false")
+		}
+		query.HasComment = &hasComment
+	}
+
+	if raw := values.Get("include_total"); raw != "" {
+		includeTotal, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("include_total must be true or false")
+		}
+		query.IncludeTotal = includeTotal
+	}
+
+	return query, nil
+}
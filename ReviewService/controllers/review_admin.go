@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewAdminController struct {
+	ReviewAdminService services.ReviewAdminService
+}
+
+func NewReviewAdminController(_reviewAdminService services.ReviewAdminService) *ReviewAdminController {
+	return &ReviewAdminController{
+		ReviewAdminService: _reviewAdminService,
+	}
+}
+
+func (ac *ReviewAdminController) RestoreReview(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	review, err := ac.ReviewAdminService.RestoreReview(reviewId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to restore review", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review restored successfully", review)
+}
+
+func (ac *ReviewAdminController) PurgeReview(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	dryRun, err := dryRunFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	report, err := ac.ReviewAdminService.PurgeReview(reviewId, dryRun)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to purge review", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review purged successfully", report)
+}
+
+func (ac *ReviewAdminController) PurgeExpired(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := dryRunFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	report, err := ac.ReviewAdminService.PurgeExpired(dryRun)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to purge expired reviews", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Expired reviews purged successfully", report)
+}
+
+func dryRunFromRequest(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("dry_run must be true or false")
+	}
+	return dryRun, nil
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"ReviewService/models"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// photoFormMemory is how much of a multipart upload is buffered in memory before spilling to temp files.
+const photoFormMemory = 8 << 20
+
+type ReviewPhotoController struct {
+	ReviewPhotoService services.ReviewPhotoService
+	maxRequestBytes    int64
+}
+
+func NewReviewPhotoController(_reviewPhotoService services.ReviewPhotoService, _maxRequestBytes int64) *ReviewPhotoController {
+	return &ReviewPhotoController{
+		ReviewPhotoService: _reviewPhotoService,
+		maxRequestBytes:    _maxRequestBytes,
+	}
+}
+
+func (pc *ReviewPhotoController) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, pc.maxRequestBytes)
+	if err := r.ParseMultipartForm(photoFormMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.WriteJsonErrorResponse(w, http.StatusRequestEntityTooLarge, "Upload is too large", err)
+			return
+		}
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid multipart form", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	uploader := r.Context().Value("authUser").(*models.AuthUser)
+
+	photos, err := pc.ReviewPhotoService.UploadPhotos(reviewId, uploader, r.MultipartForm.File["photos"])
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to upload review photos", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusCreated, "Review photos uploaded successfully", photos)
+}
+
+func (pc *ReviewPhotoController) GetPhotos(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	photos, err := pc.ReviewPhotoService.GetPhotos(reviewId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch review photos", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review photos fetched successfully", photos)
+}
+
+func (pc *ReviewPhotoController) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	pc.servePhoto(w, r, false)
+}
+
+func (pc *ReviewPhotoController) GetThumbnail(w http.ResponseWriter, r *http.Request) {
+	pc.servePhoto(w, r, true)
+}
+
+func (pc *ReviewPhotoController) servePhoto(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	body, contentType, err := pc.ReviewPhotoService.OpenPhoto(chi.URLParam(r, "id"), chi.URLParam(r, "photoId"), thumbnail)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to fetch review photo", err)
+		return
+	}
+	defer body.Close()
+
+	// Blob keys are never reused, so the bytes behind a photo URL never change
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		fmt.Println("Error streaming review photo:", err)
+	}
+}
+
+func (pc *ReviewPhotoController) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	caller := r.Context().Value("authUser").(*models.AuthUser)
+
+	if err := pc.ReviewPhotoService.DeletePhoto(chi.URLParam(r, "id"), chi.URLParam(r, "photoId"), caller); err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to delete review photo", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review photo deleted successfully", nil)
+}
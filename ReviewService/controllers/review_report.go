@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewReportController struct {
+	ReviewReportService services.ReviewReportService
+}
+
+func NewReviewReportController(_reviewReportService services.ReviewReportService) *ReviewReportController {
+	return &ReviewReportController{
+		ReviewReportService: _reviewReportService,
+	}
+}
+
+func (rc *ReviewReportController) Report(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.ReviewReportRequestDTO)
+	reporter := r.Context().Value("authUser").(*models.AuthUser)
+
+	report, err := rc.ReviewReportService.Report(reviewId, reporter, &payload)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to report review", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusCreated, "Review reported successfully", report)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewResponseController struct {
+	ReviewResponseService services.ReviewResponseService
+}
+
+func NewReviewResponseController(_reviewResponseService services.ReviewResponseService) *ReviewResponseController {
+	return &ReviewResponseController{
+		ReviewResponseService: _reviewResponseService,
+	}
+}
+
+func (rc *ReviewResponseController) CreateResponse(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.ReviewResponseRequestDTO)
+	responder := r.Context().Value("authUser").(*models.AuthUser)
+
+	response, err := rc.ReviewResponseService.CreateResponse(reviewId, responder, &payload)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to create review response", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusCreated, "Review response created successfully", response)
+}
+
+func (rc *ReviewResponseController) UpdateResponse(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.ReviewResponseRequestDTO)
+	responder := r.Context().Value("authUser").(*models.AuthUser)
+
+	response, err := rc.ReviewResponseService.UpdateResponse(reviewId, responder, &payload)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to update review response", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review response updated successfully", response)
+}
+
+func (rc *ReviewResponseController) DeleteResponse(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	responder := r.Context().Value("authUser").(*models.AuthUser)
+
+	if err := rc.ReviewResponseService.DeleteResponse(reviewId, responder); err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to delete review response", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Review response deleted successfully", nil)
+}
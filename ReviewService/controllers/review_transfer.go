@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"ReviewService/dto"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ReviewTransferController struct {
+	ReviewImportService services.ReviewImportService
+	ReviewExportService services.ReviewExportService
+	maxImportBytes      int64
+}
+
+func NewReviewTransferController(_reviewImportService services.ReviewImportService, _reviewExportService services.ReviewExportService, _maxImportBytes int64) *ReviewTransferController {
+	return &ReviewTransferController{
+		ReviewImportService: _reviewImportService,
+		ReviewExportService: _reviewExportService,
+		maxImportBytes:      _maxImportBytes,
+	}
+}
+
+// importFormats maps the accepted Content-Types to an import format.
+var importFormats = map[string]string{
+	"text/csv":                services.ImportFormatCSV,
+	"application/x-ndjson":    services.ImportFormatJSONL,
+	"application/jsonl":       services.ImportFormatJSONL,
+	"application/x-jsonlines": services.ImportFormatJSONL,
+}
+
+func (tc *ReviewTransferController) ImportReviews(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[mediaType]
+	}
+	if format == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusUnsupportedMediaType, "Unsupported import format", fmt.Errorf("send text/csv or application/x-ndjson, or set format to csv or jsonl"))
+		return
+	}
+
+	dryRun, err := dryRunFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	// A large file takes longer than the server timeouts allow
+	clearDeadlines(w)
+	r.Body = http.MaxBytesReader(w, r.Body, tc.maxImportBytes)
+
+	report, err := tc.ReviewImportService.Import(format, r.Body, dryRun)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to import reviews", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Reviews imported successfully", report)
+}
+
+// ExportReviews streams the matching reviews as CSV or NDJSON. The status is known only once the last row is written,
+// so it is sent in the X-Export-Status (complete or failed) and X-Export-Rows trailers.
+func (tc *ReviewTransferController) ExportReviews(w http.ResponseWriter, r *http.Request) {
+	list, err := reviewListQueryFromRequest(r)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	values := r.URL.Query()
+	export, err := tc.ReviewExportService.PrepareExport(&dto.ReviewExportQuery{
+		Format:  values.Get("format"),
+		HotelId: values.Get("hotel_id"),
+		UserId:  values.Get("user_id"),
+		Status:  values.Get("status"),
+		List:    list,
+	})
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to export reviews", err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if export.Format == services.ExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+	filename := fmt.Sprintf("reviews-%s.%s", time.Now().UTC().Format("20060102-150405"), export.Format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Trailer", "X-Export-Status, X-Export-Rows")
+	w.WriteHeader(http.StatusOK)
+
+	clearDeadlines(w)
+	controller := http.NewResponseController(w)
+	rows, err := tc.ReviewExportService.WriteExport(export, w, controller.Flush)
+
+	status := "complete"
+	if err != nil {
+		fmt.Println("Error exporting reviews:", err)
+		status = "failed"
+	}
+	w.Header().Set("X-Export-Status", status)
+	w.Header().Set("X-Export-Rows", strconv.Itoa(rows))
+}
+
+// clearDeadlines lifts the server read and write timeouts for a long-running transfer.
+func clearDeadlines(w http.ResponseWriter) {
+	controller := http.NewResponseController(w)
+	if err := controller.SetReadDeadline(time.Time{}); err != nil {
+		fmt.Println("Error clearing read deadline:", err)
+	}
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		fmt.Println("Error clearing write deadline:", err)
+	}
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/services"
+	"ReviewService/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewVoteController struct {
+	ReviewVoteService services.ReviewVoteService
+}
+
+func NewReviewVoteController(_reviewVoteService services.ReviewVoteService) *ReviewVoteController {
+	return &ReviewVoteController{
+		ReviewVoteService: _reviewVoteService,
+	}
+}
+
+func (vc *ReviewVoteController) Vote(w http.ResponseWriter, r *http.Request) {
+	reviewId := chi.URLParam(r, "id")
+	if reviewId == "" {
+		utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Review ID is required", fmt.Errorf("missing review ID"))
+		return
+	}
+
+	payload := r.Context().Value("payload").(dto.ReviewVoteRequestDTO)
+	voter := r.Context().Value("authUser").(*models.AuthUser)
+
+	result, err := vc.ReviewVoteService.Vote(reviewId, voter, &payload)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, utils.StatusFromError(err, http.StatusInternalServerError), "Failed to record vote", err)
+		return
+	}
+
+	utils.WriteJsonSuccessResponse(w, http.StatusOK, "Vote recorded successfully", result)
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews
+ ADD COLUMN is_verified_stay BOOLEAN NOT NULL DEFAULT FALSE AFTER is_synced;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP COLUMN is_verified_stay;
+-- +goose StatementEnd
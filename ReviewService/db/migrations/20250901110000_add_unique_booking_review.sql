@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Soft delete duplicate active reviews, keeping the earliest review per (booking_id, user_id)
+UPDATE reviews r
+ JOIN reviews keep
+   ON keep.booking_id = r.booking_id
+  AND keep.user_id = r.user_id
+  AND keep.deleted_at IS NULL
+  AND keep.id < r.id
+ SET r.deleted_at = CURRENT_TIMESTAMP
+ WHERE r.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- active_marker is 1 for live rows and NULL for soft-deleted ones, so deleted reviews never collide
+ALTER TABLE reviews
+ ADD COLUMN active_marker TINYINT GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED,
+ ADD UNIQUE INDEX uq_booking_user_active (booking_id, user_id, active_marker);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP INDEX uq_booking_user_active,
+ DROP COLUMN active_marker;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ idem_key VARCHAR(255) NOT NULL,
+ user_id BIGINT NOT NULL,
+ request_hash CHAR(64) NOT NULL,
+ finalized BOOLEAN NOT NULL DEFAULT FALSE,
+ status_code INT NULL,
+ response_body MEDIUMTEXT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+ UNIQUE INDEX uq_idem_key_user (idem_key, user_id),
+ INDEX idx_idempotency_keys_updated_at (updated_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_is_synced_hotel_id ON reviews (is_synced, hotel_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_is_synced_hotel_id ON reviews;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hotels whose last rating push failed, held back until retry_at so they do not block the rest of the queue
+CREATE TABLE rating_sync_failures (
+ hotel_id BIGINT PRIMARY KEY,
+ attempts INT NOT NULL,
+ last_error VARCHAR(1000) NOT NULL,
+ retry_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE rating_sync_failures;
+-- +goose StatementEnd
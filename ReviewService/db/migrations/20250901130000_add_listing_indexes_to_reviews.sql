@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_hotel_created_id ON reviews (hotel_id, created_at, id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_hotel_rating_created_id ON reviews (hotel_id, rating, created_at, id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_user_created_id ON reviews (user_id, created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_user_created_id ON reviews;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP INDEX idx_hotel_rating_created_id ON reviews;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP INDEX idx_hotel_created_id ON reviews;
+-- +goose StatementEnd
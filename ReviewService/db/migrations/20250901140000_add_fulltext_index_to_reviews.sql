@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews
+ ADD FULLTEXT INDEX ft_reviews_comment (comment);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP INDEX ft_reviews_comment;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reviews
+ ADD COLUMN moderation_status ENUM('pending', 'approved', 'rejected', 'hidden') NOT NULL DEFAULT 'pending' AFTER is_verified_stay,
+ ADD COLUMN moderation_reason VARCHAR(500) NULL AFTER moderation_status,
+ ADD COLUMN moderated_by BIGINT NULL AFTER moderation_reason,
+ ADD COLUMN moderated_at TIMESTAMP NULL AFTER moderated_by,
+ ADD INDEX idx_moderation_status_created_id (moderation_status, created_at, id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Reviews published before moderation existed stay live
+UPDATE reviews SET moderation_status = 'approved', updated_at = updated_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP INDEX idx_moderation_status_created_id,
+ DROP COLUMN moderated_at,
+ DROP COLUMN moderated_by,
+ DROP COLUMN moderation_reason,
+ DROP COLUMN moderation_status;
+-- +goose StatementEnd
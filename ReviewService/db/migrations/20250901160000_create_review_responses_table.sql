@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_responses (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ responder_id BIGINT NOT NULL,
+ body TEXT NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+ deleted_at TIMESTAMP NULL,
+ -- one live response per review; deleted responses do not count
+ active_marker TINYINT GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED,
+ UNIQUE INDEX uq_review_response_active (review_id, active_marker),
+ CONSTRAINT fk_review_responses_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE review_responses;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_votes (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ user_id BIGINT NOT NULL,
+ helpful BOOLEAN NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+ UNIQUE INDEX uq_review_votes_review_user (review_id, user_id),
+ CONSTRAINT fk_review_votes_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Vote totals are kept on the review so relevance can be ranked without joining every vote
+ALTER TABLE reviews
+ ADD COLUMN helpful_count INT NOT NULL DEFAULT 0 AFTER moderated_at,
+ ADD COLUMN not_helpful_count INT NOT NULL DEFAULT 0 AFTER helpful_count;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP COLUMN not_helpful_count,
+ DROP COLUMN helpful_count;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE review_votes;
+-- +goose StatementEnd
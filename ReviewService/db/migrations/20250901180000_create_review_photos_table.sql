@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_photos (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ user_id BIGINT NOT NULL,
+ blob_key VARCHAR(255) NOT NULL,
+ thumbnail_key VARCHAR(255) NOT NULL,
+ content_type VARCHAR(32) NOT NULL,
+ size_bytes INT NOT NULL,
+ width INT NOT NULL,
+ height INT NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ deleted_at TIMESTAMP NULL,
+ INDEX idx_review_photos_review (review_id, deleted_at),
+ CONSTRAINT fk_review_photos_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE review_photos;
+-- +goose StatementEnd
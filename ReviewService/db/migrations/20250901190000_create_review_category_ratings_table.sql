@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_category_ratings (
+ review_id BIGINT NOT NULL,
+ category ENUM('cleanliness', 'accuracy', 'check_in', 'communication', 'location', 'value') NOT NULL,
+ rating TINYINT NOT NULL,
+ PRIMARY KEY (review_id, category),
+ CONSTRAINT chk_category_rating CHECK (rating BETWEEN 1 AND 5),
+ CONSTRAINT fk_review_category_ratings_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE review_category_ratings;
+-- +goose StatementEnd
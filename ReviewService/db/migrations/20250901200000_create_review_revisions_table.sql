@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_revisions (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ revision INT NOT NULL,
+ editor_id BIGINT NOT NULL,
+ comment TEXT NOT NULL,
+ rating INT NOT NULL,
+ category_ratings JSON NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ UNIQUE INDEX uq_review_revision (review_id, revision),
+ CONSTRAINT fk_review_revisions_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE reviews ADD COLUMN edited_at TIMESTAMP NULL AFTER updated_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Existing reviews start their history with their current content, as written by their author
+INSERT INTO review_revisions (review_id, revision, editor_id, comment, rating, category_ratings, created_at)
+SELECT r.id, 1, r.user_id, r.comment, r.rating,
+ (SELECT JSON_OBJECTAGG(c.category, c.rating) FROM review_category_ratings c WHERE c.review_id = r.id),
+ r.created_at
+FROM reviews r;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews DROP COLUMN edited_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE review_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox_events (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ event_id CHAR(36) NOT NULL,
+ event_type VARCHAR(64) NOT NULL,
+ aggregate_id BIGINT NOT NULL,
+ payload JSON NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ published_at TIMESTAMP NULL,
+ attempts INT NOT NULL DEFAULT 0,
+ last_error TEXT NULL,
+ UNIQUE INDEX uq_outbox_event_id (event_id),
+ -- the relay reads unpublished events in insertion order
+ INDEX idx_outbox_published_id (published_at, id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE outbox_events;
+-- +goose StatementEnd
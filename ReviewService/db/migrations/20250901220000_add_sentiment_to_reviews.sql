@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Both stay NULL until the review is analyzed; existing reviews are analyzed by the backfill command
+ALTER TABLE reviews
+ ADD COLUMN sentiment_score DECIMAL(4,3) NULL AFTER not_helpful_count,
+ ADD COLUMN sentiment_version SMALLINT NULL AFTER sentiment_score;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE review_aspects (
+ review_id BIGINT NOT NULL,
+ aspect VARCHAR(32) NOT NULL,
+ sentiment DECIMAL(4,3) NOT NULL,
+ PRIMARY KEY (review_id, aspect),
+ CONSTRAINT fk_review_aspects_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE review_aspects;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP COLUMN sentiment_version,
+ DROP COLUMN sentiment_score;
+-- +goose StatementEnd
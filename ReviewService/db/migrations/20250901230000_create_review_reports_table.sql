@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE review_reports (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ reporter_id BIGINT NOT NULL,
+ reason ENUM('spam', 'offensive', 'irrelevant', 'conflict_of_interest') NOT NULL,
+ details VARCHAR(500) NULL,
+ weight DECIMAL(5,2) NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ resolved_at TIMESTAMP NULL,
+ resolved_by BIGINT NULL,
+ UNIQUE INDEX uq_review_reports_review_reporter (review_id, reporter_id),
+ CONSTRAINT fk_review_reports_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Totals of the open (unresolved) reports are kept on the review so the moderation list can be ordered without joining every report.
+-- takedown_requeued marks a hidden or rejected review its author edited back into the queue: it stays pending until a moderator decides
+ALTER TABLE reviews
+ ADD COLUMN takedown_requeued BOOLEAN NOT NULL DEFAULT FALSE AFTER moderated_at,
+ ADD COLUMN report_count INT NOT NULL DEFAULT 0 AFTER not_helpful_count,
+ ADD COLUMN report_weight DECIMAL(7,2) NOT NULL DEFAULT 0 AFTER report_count,
+ ADD INDEX idx_report_count_weight (report_count, report_weight);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP INDEX idx_report_count_weight,
+ DROP COLUMN report_weight,
+ DROP COLUMN report_count,
+ DROP COLUMN takedown_requeued;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE review_reports;
+-- +goose StatementEnd
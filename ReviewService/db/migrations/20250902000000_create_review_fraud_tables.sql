@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+-- MinHash signature of each review's comment. signature is NULL for comments too short to compare;
+-- reviews without a row are fingerprinted by the backfill command
+CREATE TABLE review_fingerprints (
+ review_id BIGINT PRIMARY KEY,
+ signature VARBINARY(256) NULL,
+ CONSTRAINT fk_review_fingerprints_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- LSH band keys of the signatures; reviews sharing a key are near-duplicate candidates
+CREATE TABLE review_fingerprint_bands (
+ band_key BIGINT NOT NULL,
+ review_id BIGINT NOT NULL,
+ PRIMARY KEY (band_key, review_id),
+ INDEX idx_review_fingerprint_bands_review (review_id),
+ CONSTRAINT fk_review_fingerprint_bands_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE review_fraud_checks (
+ id BIGINT AUTO_INCREMENT PRIMARY KEY,
+ review_id BIGINT NOT NULL,
+ score DECIMAL(4,3) NOT NULL,
+ decision ENUM('pass', 'hold') NOT NULL,
+ signals JSON NOT NULL,
+ created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ INDEX idx_review_fraud_checks_review (review_id),
+ CONSTRAINT fk_review_fraud_checks_review FOREIGN KEY (review_id) REFERENCES reviews (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Serves the per-user burst count
+ALTER TABLE reviews ADD INDEX idx_user_created (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews DROP INDEX idx_user_created;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE review_fraud_checks;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE review_fingerprint_bands;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE review_fingerprints;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL until the comment is identified; existing reviews are identified by the backfill command.
+-- "und" marks comments too short or ambiguous to identify
+ALTER TABLE reviews
+ ADD COLUMN language VARCHAR(8) NULL AFTER sentiment_version,
+ ADD INDEX idx_hotel_language (hotel_id, language);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviews
+ DROP INDEX idx_hotel_language,
+ DROP COLUMN language;
+-- +goose StatementEnd
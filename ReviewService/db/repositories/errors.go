@@ -0,0 +1,20 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrDuplicateEntry is returned when an insert violates a unique index.
+var ErrDuplicateEntry = errors.New("duplicate entry")
+
+// ErrConcurrentChange is returned when a conditional update finds the row changed or deleted since it was read.
+var ErrConcurrentChange = errors.New("changed concurrently")
+
+const mysqlDuplicateEntryCode = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntryCode
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type IdempotencyKeyRepository interface {
+	// Create stores a new, unfinalized key. It returns ErrDuplicateEntry if the user already used the key.
+	Create(idemKey string, userId int64, requestHash string) (*models.IdempotencyKey, error)
+	GetByKey(idemKey string, userId int64) (*models.IdempotencyKey, error)
+	Finalize(id int64, statusCode int, responseBody string) error
+	Delete(id int64) error
+	// TakeOver claims an unfinalized key untouched for staleAfter, left behind by a request that never finished.
+	// It returns false when the key was finalized or claimed in the meantime.
+	TakeOver(id int64, staleAfter time.Duration) (bool, error)
+	// Renew hands a finalized key last used more than ttl ago to a new request, forgetting the stored response.
+	// It returns false when the key has not expired or was renewed in the meantime.
+	Renew(id int64, requestHash string, ttl time.Duration) (bool, error)
+	// DeleteExpired removes up to limit keys last used more than ttl ago and returns how many it removed.
+	DeleteExpired(ttl time.Duration, limit int) (int64, error)
+}
+
+type IdempotencyKeyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewIdempotencyKeyRepository(_db *sql.DB) IdempotencyKeyRepository {
+	return &IdempotencyKeyRepositoryImpl{
+		db: _db,
+	}
+}
+
+func (i *IdempotencyKeyRepositoryImpl) Create(idemKey string, userId int64, requestHash string) (*models.IdempotencyKey, error) {
+	query := "INSERT INTO idempotency_keys (idem_key, user_id, request_hash) VALUES (?, ?, ?)"
+	result, err := i.db.Exec(query, idemKey, userId, requestHash)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, ErrDuplicateEntry
+		}
+		fmt.Println("Error creating idempotency key:", err)
+		return nil, err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		fmt.Println("Error getting last insert ID:", err)
+		return nil, err
+	}
+
+	return &models.IdempotencyKey{
+		Id:          lastInsertID,
+		IdemKey:     idemKey,
+		UserId:      userId,
+		RequestHash: requestHash,
+	}, nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) GetByKey(idemKey string, userId int64) (*models.IdempotencyKey, error) {
+	query := "SELECT id, idem_key, user_id, request_hash, finalized, status_code, response_body, created_at, updated_at FROM idempotency_keys WHERE idem_key = ? AND user_id = ?"
+	row := i.db.QueryRow(query, idemKey, userId)
+
+	key := &models.IdempotencyKey{}
+	err := row.Scan(&key.Id, &key.IdemKey, &key.UserId, &key.RequestHash, &key.Finalized, &key.StatusCode, &key.ResponseBody, &key.CreatedAt, &key.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning idempotency key:", err)
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) Finalize(id int64, statusCode int, responseBody string) error {
+	query := "UPDATE idempotency_keys SET finalized = TRUE, status_code = ?, response_body = ? WHERE id = ?"
+	_, err := i.db.Exec(query, statusCode, responseBody, id)
+	if err != nil {
+		fmt.Println("Error finalizing idempotency key:", err)
+		return err
+	}
+	return nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) Delete(id int64) error {
+	query := "DELETE FROM idempotency_keys WHERE id = ?"
+	_, err := i.db.Exec(query, id)
+	if err != nil {
+		fmt.Println("Error deleting idempotency key:", err)
+		return err
+	}
+	return nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) TakeOver(id int64, staleAfter time.Duration) (bool, error) {
+	query := "UPDATE idempotency_keys SET updated_at = CURRENT_TIMESTAMP WHERE id = ? AND finalized = FALSE AND updated_at < NOW() - INTERVAL ? SECOND"
+	result, err := i.db.Exec(query, id, int64(staleAfter.Seconds()))
+	if err != nil {
+		fmt.Println("Error taking over idempotency key:", err)
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return false, err
+	}
+	return claimed > 0, nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) Renew(id int64, requestHash string, ttl time.Duration) (bool, error) {
+	query := "UPDATE idempotency_keys SET finalized = FALSE, status_code = NULL, response_body = NULL, request_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND finalized = TRUE AND updated_at < NOW() - INTERVAL ? SECOND"
+	result, err := i.db.Exec(query, requestHash, id, int64(ttl.Seconds()))
+	if err != nil {
+		fmt.Println("Error renewing idempotency key:", err)
+		return false, err
+	}
+	renewed, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return false, err
+	}
+	return renewed > 0, nil
+}
+
+func (i *IdempotencyKeyRepositoryImpl) DeleteExpired(ttl time.Duration, limit int) (int64, error) {
+	query := "DELETE FROM idempotency_keys WHERE updated_at < NOW() - INTERVAL ? SECOND ORDER BY updated_at LIMIT ?"
+	result, err := i.db.Exec(query, int64(ttl.Seconds()), limit)
+	if err != nil {
+		fmt.Println("Error deleting expired idempotency keys:", err)
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"ReviewService/models"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OutboxRepository interface {
+	// GetUnpublished returns the oldest events not yet published, in the order they were written.
+	GetUnpublished(limit int) ([]*models.OutboxEvent, error)
+	MarkPublished(id int64) error
+	MarkFailed(id int64, reason string) error
+	// DeletePublishedBefore removes events published before the given time and returns how many.
+	DeletePublishedBefore(before time.Time) (int64, error)
+}
+
+type OutboxRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(_db *sql.DB) OutboxRepository {
+	return &OutboxRepositoryImpl{
+		db: _db,
+	}
+}
+
+// insertReviewEvent records an event for the review inside tx, with the review's state as tx sees it,
+// so the event is stored exactly when the change it describes is.
+func insertReviewEvent(tx *sql.Tx, eventType string, reviewId int64) error {
+	return insertReviewEvents(tx, eventType, []int64{reviewId})
+}
+
+// insertReviewEvents records one event per review inside tx, in ID order, loading the reviews in a few queries.
+func insertReviewEvents(tx *sql.Tx, eventType string, reviewIds []int64) error {
+	if len(reviewIds) == 0 {
+		return nil
+	}
+	placeholders, args := inClause(reviewIds)
+
+	rows, err := tx.Query("SELECT "+ReviewColumns+" FROM reviews WHERE id IN ("+placeholders+") ORDER BY id", args...)
+	if err != nil {
+		fmt.Println("Error loading reviews for events:", err)
+		return err
+	}
+	var reviews []*models.Review
+	byId := map[int64]*models.Review{}
+	for rows.Next() {
+		review, err := ScanReview(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		review.CategoryRatings = map[string]int{}
+		review.Aspects = map[string]float64{}
+		reviews = append(reviews, review)
+		byId[review.Id] = review
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(reviews) != len(reviewIds) {
+		return fmt.Errorf("loaded %d of %d reviews for events", len(reviews), len(reviewIds))
+	}
+
+	rows, err = tx.Query("SELECT review_id, category, rating FROM review_category_ratings WHERE review_id IN ("+placeholders+")", args...)
+	if err != nil {
+		fmt.Println("Error loading category ratings for events:", err)
+		return err
+	}
+	for rows.Next() {
+		var reviewId int64
+		var category string
+		var rating int
+		if err := rows.Scan(&reviewId, &category, &rating); err != nil {
+			rows.Close()
+			return err
+		}
+		byId[reviewId].CategoryRatings[category] = rating
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.Query("SELECT review_id, aspect, sentiment FROM review_aspects WHERE review_id IN ("+placeholders+")", args...)
+	if err != nil {
+		fmt.Println("Error loading review aspects for events:", err)
+		return err
+	}
+	for rows.Next() {
+		var reviewId int64
+		var aspect string
+		var sentiment float64
+		if err := rows.Scan(&reviewId, &aspect, &sentiment); err != nil {
+			rows.Close()
+			return err
+		}
+		byId[reviewId].Aspects[aspect] = sentiment
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	occurredAt := time.Now().UTC().Format(time.RFC3339Nano)
+	var values []string
+	var eventArgs []any
+	for _, review := range reviews {
+		eventId, err := newEventId()
+		if err != nil {
+			return err
+		}
+		payload, err := json.Marshal(&models.ReviewEventPayload{
+			EventId:    eventId,
+			Type:       eventType,
+			OccurredAt: occurredAt,
+			Review:     review,
+		})
+		if err != nil {
+			return err
+		}
+		values = append(values, "(?, ?, ?, ?)")
+		eventArgs = append(eventArgs, eventId, eventType, review.Id, payload)
+	}
+
+	query := "INSERT INTO outbox_events (event_id, event_type, aggregate_id, payload) VALUES " + strings.Join(values, ", ")
+	if _, err := tx.Exec(query, eventArgs...); err != nil {
+		fmt.Println("Error writing outbox events:", err)
+		return err
+	}
+	return nil
+}
+
+// newEventId returns a random (version 4) UUID.
+func newEventId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func (o *OutboxRepositoryImpl) GetUnpublished(limit int) ([]*models.OutboxEvent, error) {
+	query := "SELECT id, event_id, event_type, aggregate_id, payload, created_at, published_at, attempts, last_error FROM outbox_events WHERE published_at IS NULL ORDER BY id LIMIT ?"
+	rows, err := o.db.Query(query, limit)
+	if err != nil {
+		fmt.Println("Error fetching outbox events:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.OutboxEvent
+	for rows.Next() {
+		event := &models.OutboxEvent{}
+		if err := rows.Scan(&event.Id, &event.EventId, &event.EventType, &event.AggregateId, &event.Payload, &event.CreatedAt, &event.PublishedAt, &event.Attempts, &event.LastError); err != nil {
+			fmt.Println("Error scanning outbox event:", err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (o *OutboxRepositoryImpl) MarkPublished(id int64) error {
+	query := "UPDATE outbox_events SET published_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL WHERE id = ?"
+	if _, err := o.db.Exec(query, id); err != nil {
+		fmt.Println("Error marking outbox event published:", err)
+		return err
+	}
+	return nil
+}
+
+func (o *OutboxRepositoryImpl) MarkFailed(id int64, reason string) error {
+	query := "UPDATE outbox_events SET attempts = attempts + 1, last_error = ? WHERE id = ?"
+	if _, err := o.db.Exec(query, reason, id); err != nil {
+		fmt.Println("Error recording outbox event failure:", err)
+		return err
+	}
+	return nil
+}
+
+func (o *OutboxRepositoryImpl) DeletePublishedBefore(before time.Time) (int64, error) {
+	query := "DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < ?"
+	result, err := o.db.Exec(query, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		fmt.Println("Error deleting published outbox events:", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// replaceAspects swaps a review's aspect sentiments for the given set inside tx.
+func replaceAspects(tx *sql.Tx, reviewId int64, aspects map[string]float64) error {
+	if _, err := tx.Exec("DELETE FROM review_aspects WHERE review_id = ?", reviewId); err != nil {
+		fmt.Println("Error clearing review aspects:", err)
+		return err
+	}
+	if len(aspects) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(aspects))
+	for aspect := range aspects {
+		names = append(names, aspect)
+	}
+	sort.Strings(names)
+
+	var values []string
+	var args []any
+	for _, aspect := range names {
+		values = append(values, "(?, ?, ?)")
+		args = append(args, reviewId, aspect, aspects[aspect])
+	}
+
+	query := "INSERT INTO review_aspects (review_id, aspect, sentiment) VALUES " + strings.Join(values, ", ")
+	if _, err := tx.Exec(query, args...); err != nil {
+		fmt.Println("Error storing review aspects:", err)
+		return err
+	}
+	return nil
+}
+
+// GetAspects loads the aspect sentiments of several reviews in one query, keyed by review ID.
+// Reviews that mention no aspect are absent from the result.
+func (r *ReviewRepositoryImpl) GetAspects(reviewIds []int64) (map[int64]map[string]float64, error) {
+	aspects := map[int64]map[string]float64{}
+	if len(reviewIds) == 0 {
+		return aspects, nil
+	}
+
+	placeholders, args := inClause(reviewIds)
+	rows, err := r.db.Query("SELECT review_id, aspect, sentiment FROM review_aspects WHERE review_id IN ("+placeholders+")", args...)
+	if err != nil {
+		fmt.Println("Error fetching review aspects:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewId int64
+		var aspect string
+		var sentiment float64
+		if err := rows.Scan(&reviewId, &aspect, &sentiment); err != nil {
+			fmt.Println("Error scanning review aspect:", err)
+			return nil, err
+		}
+		if aspects[reviewId] == nil {
+			aspects[reviewId] = map[string]float64{}
+		}
+		aspects[reviewId][aspect] = sentiment
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return aspects, nil
+}
+
+// GetHotelAspectSummary aggregates the aspects of a hotel's active, approved, analyzed reviews. A mention counts
+// as positive or negative when its sentiment is at least neutralBoundary away from zero, and neutral otherwise.
+func (r *ReviewRepositoryImpl) GetHotelAspectSummary(hotelId int64, neutralBoundary float64) (*models.HotelAspectSummary, error) {
+	summary := &models.HotelAspectSummary{HotelId: hotelId, Aspects: map[string]models.AspectAggregate{}}
+	countQuery := "SELECT COUNT(*) FROM reviews WHERE hotel_id = ? AND deleted_at IS NULL AND moderation_status = 'approved' AND sentiment_version IS NOT NULL"
+	if err := r.db.QueryRow(countQuery, hotelId).Scan(&summary.AnalyzedCount); err != nil {
+		fmt.Println("Error counting analyzed reviews:", err)
+		return nil, err
+	}
+
+	query := `SELECT a.aspect, COUNT(*),
+		SUM(a.sentiment >= ?), SUM(a.sentiment > -? AND a.sentiment < ?), SUM(a.sentiment <= -?),
+		SUM(a.sentiment)
+	FROM review_aspects a
+	JOIN reviews r ON r.id = a.review_id
+	WHERE r.hotel_id = ? AND r.deleted_at IS NULL AND r.moderation_status = 'approved' AND r.sentiment_version IS NOT NULL
+	GROUP BY a.aspect`
+	rows, err := r.db.Query(query, neutralBoundary, neutralBoundary, neutralBoundary, neutralBoundary, hotelId)
+	if err != nil {
+		fmt.Println("Error aggregating review aspects:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var aspect string
+		var aggregate models.AspectAggregate
+		if err := rows.Scan(&aspect, &aggregate.Mentions, &aggregate.Positive, &aggregate.Neutral, &aggregate.Negative, &aggregate.SentimentSum); err != nil {
+			fmt.Println("Error scanning aspect aggregate:", err)
+			return nil, err
+		}
+		summary.Aspects[aspect] = aggregate
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// GetUnanalyzed returns reviews, deleted ones included, whose sentiment is missing or was produced by an analyzer
+// older than version, in ID order after afterId.
+func (r *ReviewRepositoryImpl) GetUnanalyzed(version int, afterId int64, limit int) ([]*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE id > ? AND (sentiment_version IS NULL OR sentiment_version < ?) ORDER BY id LIMIT ?"
+	rows, err := r.db.Query(query, afterId, version, limit)
+	if err != nil {
+		fmt.Println("Error fetching unanalyzed reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReviews(rows)
+}
+
+// SetSentiment stores a backfilled analysis. It is skipped, returning false, when the review has been analyzed
+// with version or later in the meantime, so an edit made during the backfill is never overwritten with an
+// analysis of the old comment. Derived data changing is not an edit, so updated_at and the sync flag are kept.
+func (r *ReviewRepositoryImpl) SetSentiment(id int64, score float64, version int, aspects map[string]float64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE reviews SET sentiment_score = ?, sentiment_version = ?, updated_at = updated_at WHERE id = ? AND (sentiment_version IS NULL OR sentiment_version < ?)"
+	stored, err := execRowsAffected(tx, query, score, version, id, version)
+	if err != nil {
+		fmt.Println("Error storing sentiment:", err)
+		return false, err
+	}
+	if stored == 0 {
+		return false, nil
+	}
+
+	if err := replaceAspects(tx, id, aspects); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing sentiment:", err)
+		return false, err
+	}
+	return true, nil
+}
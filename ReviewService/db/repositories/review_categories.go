@@ -0,0 +1,116 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// replaceCategoryRatings swaps a review's category ratings for the given set inside tx.
+func replaceCategoryRatings(tx *sql.Tx, reviewId int64, ratings map[string]int) error {
+	if _, err := tx.Exec("DELETE FROM review_category_ratings WHERE review_id = ?", reviewId); err != nil {
+		fmt.Println("Error clearing category ratings:", err)
+		return err
+	}
+	if len(ratings) == 0 {
+		return nil
+	}
+
+	var values []string
+	var args []any
+	for _, category := range models.ReviewCategories {
+		if rating, ok := ratings[category]; ok {
+			values = append(values, "(?, ?, ?)")
+			args = append(args, reviewId, category, rating)
+		}
+	}
+	if len(values) != len(ratings) {
+		return fmt.Errorf("unknown review category in %v", ratings)
+	}
+
+	query := "INSERT INTO review_category_ratings (review_id, category, rating) VALUES " + strings.Join(values, ", ")
+	if _, err := tx.Exec(query, args...); err != nil {
+		fmt.Println("Error storing category ratings:", err)
+		return err
+	}
+	return nil
+}
+
+// GetCategoryRatings loads the category ratings of several reviews in one query, keyed by review ID.
+// Reviews without category ratings are absent from the result.
+func (r *ReviewRepositoryImpl) GetCategoryRatings(reviewIds []int64) (map[int64]map[string]int, error) {
+	ratings := map[int64]map[string]int{}
+	if len(reviewIds) == 0 {
+		return ratings, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(reviewIds)), ", ")
+	args := make([]any, len(reviewIds))
+	for i, id := range reviewIds {
+		args[i] = id
+	}
+
+	query := "SELECT review_id, category, rating FROM review_category_ratings WHERE review_id IN (" + placeholders + ")"
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		fmt.Println("Error fetching category ratings:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewId int64
+		var category string
+		var rating int
+		if err := rows.Scan(&reviewId, &category, &rating); err != nil {
+			fmt.Println("Error scanning category rating:", err)
+			return nil, err
+		}
+		if ratings[reviewId] == nil {
+			ratings[reviewId] = map[string]int{}
+		}
+		ratings[reviewId][category] = rating
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// getHotelCategoryAggregates sums the category ratings of a hotel's active, approved reviews.
+// Categories nobody rated are absent from the result.
+func getHotelCategoryAggregates(q queryer, hotelId int64) (map[string]models.RatingAggregate, error) {
+	query := `SELECT c.category, COUNT(*), SUM(c.rating)
+	FROM review_category_ratings c
+	JOIN reviews r ON r.id = c.review_id
+	WHERE r.hotel_id = ? AND r.deleted_at IS NULL AND r.moderation_status = 'approved'
+	GROUP BY c.category`
+	rows, err := q.Query(query, hotelId)
+	if err != nil {
+		fmt.Println("Error aggregating category ratings:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	aggregates := map[string]models.RatingAggregate{}
+	for rows.Next() {
+		var category string
+		var aggregate models.RatingAggregate
+		if err := rows.Scan(&category, &aggregate.Count, &aggregate.Sum); err != nil {
+			fmt.Println("Error scanning category aggregate:", err)
+			return nil, err
+		}
+		aggregates[category] = aggregate
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return aggregates, nil
+}
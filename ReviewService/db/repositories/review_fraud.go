@@ -0,0 +1,207 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// insertFingerprints stores the fingerprints of the reviews that have one, with their band keys, inside tx.
+func insertFingerprints(tx *sql.Tx, reviews []*models.Review) error {
+	var values, bandValues []string
+	var args, bandArgs []any
+	for _, review := range reviews {
+		if review.Fingerprint == nil {
+			continue
+		}
+		values = append(values, "(?, ?)")
+		args = append(args, review.Id, review.Fingerprint.Signature)
+		for _, band := range review.Fingerprint.Bands {
+			bandValues = append(bandValues, "(?, ?)")
+			bandArgs = append(bandArgs, band, review.Id)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec("INSERT INTO review_fingerprints (review_id, signature) VALUES "+strings.Join(values, ", "), args...); err != nil {
+		fmt.Println("Error storing review fingerprints:", err)
+		return err
+	}
+	if len(bandValues) == 0 {
+		return nil
+	}
+	// Identical bands within one signature give the same key twice
+	if _, err := tx.Exec("INSERT IGNORE INTO review_fingerprint_bands (band_key, review_id) VALUES "+strings.Join(bandValues, ", "), bandArgs...); err != nil {
+		fmt.Println("Error storing review fingerprint bands:", err)
+		return err
+	}
+	return nil
+}
+
+// replaceFingerprint swaps a review's fingerprint for review.Fingerprint inside tx. A nil fingerprint leaves it alone.
+func replaceFingerprint(tx *sql.Tx, review *models.Review) error {
+	if review.Fingerprint == nil {
+		return nil
+	}
+	for _, table := range []string{"review_fingerprint_bands", "review_fingerprints"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE review_id = ?", review.Id); err != nil {
+			fmt.Println("Error clearing", table+":", err)
+			return err
+		}
+	}
+	return insertFingerprints(tx, []*models.Review{review})
+}
+
+// insertFraudCheck records the fraud check of a new review inside tx.
+func insertFraudCheck(tx *sql.Tx, reviewId int64, check *models.FraudCheck) error {
+	signals, err := json.Marshal(check.Signals)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO review_fraud_checks (review_id, score, decision, signals) VALUES (?, ?, ?, ?)", reviewId, check.Score, check.Decision, signals); err != nil {
+		fmt.Println("Error recording fraud check:", err)
+		return err
+	}
+	return nil
+}
+
+// GetFraudChecks returns the fraud checks recorded for a review, oldest first.
+func (r *ReviewRepositoryImpl) GetFraudChecks(reviewId int64) ([]*models.FraudCheck, error) {
+	rows, err := r.db.Query("SELECT id, review_id, score, decision, signals, created_at FROM review_fraud_checks WHERE review_id = ? ORDER BY id", reviewId)
+	if err != nil {
+		fmt.Println("Error fetching fraud checks:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	checks := []*models.FraudCheck{}
+	for rows.Next() {
+		check := &models.FraudCheck{}
+		var signals []byte
+		if err := rows.Scan(&check.Id, &check.ReviewId, &check.Score, &check.Decision, &signals, &check.CreatedAt); err != nil {
+			fmt.Println("Error scanning fraud check:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal(signals, &check.Signals); err != nil {
+			fmt.Println("Error decoding fraud check signals:", err)
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+	return checks, nil
+}
+
+// CountRecentByUser counts the reviews a user wrote within window, deleted ones included.
+func (r *ReviewRepositoryImpl) CountRecentByUser(userId int64, window time.Duration) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM reviews WHERE user_id = ? AND created_at >= NOW() - INTERVAL ? SECOND"
+	if err := r.db.QueryRow(query, userId, int64(window.Seconds())).Scan(&count); err != nil {
+		fmt.Println("Error counting recent reviews:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetUserRatingStats summarizes the ratings of a user's active reviews.
+func (r *ReviewRepositoryImpl) GetUserRatingStats(userId int64) (*models.UserRatingStats, error) {
+	stats := &models.UserRatingStats{}
+	query := "SELECT COUNT(*), COALESCE(AVG(rating), 0), COALESCE(STDDEV_POP(rating), 0) FROM reviews WHERE user_id = ? AND deleted_at IS NULL"
+	if err := r.db.QueryRow(query, userId).Scan(&stats.Count, &stats.Mean, &stats.StdDev); err != nil {
+		fmt.Println("Error fetching user rating stats:", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// FindFingerprintMatches returns active reviews whose fingerprint shares a band key with bands, the ones sharing
+// the most keys first.
+func (r *ReviewRepositoryImpl) FindFingerprintMatches(bands []int64, limit int) ([]*models.FingerprintMatch, error) {
+	if len(bands) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := inClause(bands)
+	query := `SELECT b.review_id, r.user_id, f.signature FROM review_fingerprint_bands b
+	JOIN review_fingerprints f ON f.review_id = b.review_id
+	JOIN reviews r ON r.id = b.review_id AND r.deleted_at IS NULL
+	WHERE b.band_key IN (` + placeholders + `)
+	GROUP BY b.review_id, r.user_id, f.signature
+	ORDER BY COUNT(*) DESC, b.review_id DESC LIMIT ?`
+	rows, err := r.db.Query(query, append(args, limit)...)
+	if err != nil {
+		fmt.Println("Error fetching fingerprint matches:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*models.FingerprintMatch
+	for rows.Next() {
+		match := &models.FingerprintMatch{}
+		if err := rows.Scan(&match.ReviewId, &match.UserId, &match.Signature); err != nil {
+			fmt.Println("Error scanning fingerprint match:", err)
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+	return matches, nil
+}
+
+// GetUnfingerprinted returns reviews, deleted ones included, that have no fingerprint yet, in ID order after afterId.
+func (r *ReviewRepositoryImpl) GetUnfingerprinted(afterId int64, limit int) ([]*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE id > ? AND NOT EXISTS (SELECT 1 FROM review_fingerprints f WHERE f.review_id = reviews.id) ORDER BY id LIMIT ?"
+	rows, err := r.db.Query(query, afterId, limit)
+	if err != nil {
+		fmt.Println("Error fetching unfingerprinted reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReviews(rows)
+}
+
+// SetFingerprint stores a backfilled fingerprint. It is skipped, returning false, when the review was fingerprinted
+// in the meantime, so an edit made during the backfill keeps the fingerprint of its new comment.
+func (r *ReviewRepositoryImpl) SetFingerprint(review *models.Review) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	stored, err := execRowsAffected(tx, "INSERT IGNORE INTO review_fingerprints (review_id, signature) VALUES (?, ?)", review.Id, review.Fingerprint.Signature)
+	if err != nil {
+		fmt.Println("Error storing review fingerprint:", err)
+		return false, err
+	}
+	if stored == 0 {
+		return false, nil
+	}
+
+	for _, band := range review.Fingerprint.Bands {
+		if _, err := tx.Exec("INSERT IGNORE INTO review_fingerprint_bands (band_key, review_id) VALUES (?, ?)", band, review.Id); err != nil {
+			fmt.Println("Error storing review fingerprint band:", err)
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review fingerprint:", err)
+		return false, err
+	}
+	return true, nil
+}
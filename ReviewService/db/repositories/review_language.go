@@ -0,0 +1,77 @@
+package db
+
+import (
+	"ReviewService/models"
+	"fmt"
+	"strings"
+)
+
+// languageRank is an ORDER BY expression giving a review's position among the preferred languages, from 1,
+// and one past the last for any other language, including reviews not identified yet.
+func languageRank(preferred []string) (string, []any) {
+	args := make([]any, 0, len(preferred)+1)
+	for _, language := range preferred {
+		args = append(args, language)
+	}
+	args = append(args, len(preferred)+1)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(preferred)), ", ")
+	return "COALESCE(NULLIF(FIELD(language, " + placeholders + "), 0), ?)", args
+}
+
+// getHotelLanguageCounts counts a hotel's active, approved reviews per language. Reviews not identified yet are left out.
+func (r *ReviewRepositoryImpl) getHotelLanguageCounts(hotelId int64) (map[string]int64, error) {
+	query := "SELECT language, COUNT(*) FROM reviews WHERE hotel_id = ? AND deleted_at IS NULL AND moderation_status = 'approved' AND language IS NOT NULL GROUP BY language"
+	rows, err := r.db.Query(query, hotelId)
+	if err != nil {
+		fmt.Println("Error counting hotel review languages:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var language string
+		var count int64
+		if err := rows.Scan(&language, &count); err != nil {
+			fmt.Println("Error scanning language count:", err)
+			return nil, err
+		}
+		counts[language] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetUnidentified returns reviews, deleted ones included, whose language has not been identified, in ID order
+// after afterId.
+func (r *ReviewRepositoryImpl) GetUnidentified(afterId int64, limit int) ([]*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE id > ? AND language IS NULL ORDER BY id LIMIT ?"
+	rows, err := r.db.Query(query, afterId, limit)
+	if err != nil {
+		fmt.Println("Error fetching unidentified reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReviews(rows)
+}
+
+// SetLanguage stores a backfilled language. It is skipped, returning false, when the review's language was set in
+// the meantime by an edit. Like SetSentiment it keeps updated_at and the sync flag.
+func (r *ReviewRepositoryImpl) SetLanguage(id int64, language string) (bool, error) {
+	result, err := r.db.Exec("UPDATE reviews SET language = ?, updated_at = updated_at WHERE id = ? AND language IS NULL", language, id)
+	if err != nil {
+		fmt.Println("Error storing review language:", err)
+		return false, err
+	}
+	stored, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return false, err
+	}
+	return stored > 0, nil
+}
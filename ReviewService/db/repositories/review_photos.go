@@ -0,0 +1,194 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type ReviewPhotoRepository interface {
+	// CreateAll inserts the photos of one review together, or none of them when the review would end up
+	// with more than limit live photos. The limit is checked under a lock on the review row, so concurrent
+	// uploads cannot overshoot it.
+	CreateAll(reviewId int64, photos []*models.ReviewPhoto, limit int) ([]*models.ReviewPhoto, error)
+	GetById(id int64) (*models.ReviewPhoto, error)
+	GetByReviewId(reviewId int64) ([]*models.ReviewPhoto, error)
+	GetByReviewIds(reviewIds []int64) (map[int64][]*models.ReviewPhoto, error)
+	CountByReviewId(reviewId int64) (int, error)
+	Delete(id int64) error
+}
+
+const reviewPhotoColumns = "id, review_id, user_id, blob_key, thumbnail_key, content_type, size_bytes, width, height, created_at, deleted_at"
+
+func scanReviewPhoto(row RowScanner) (*models.ReviewPhoto, error) {
+	photo := &models.ReviewPhoto{}
+	err := row.Scan(&photo.Id, &photo.ReviewId, &photo.UserId, &photo.BlobKey, &photo.ThumbnailKey, &photo.ContentType, &photo.SizeBytes, &photo.Width, &photo.Height, &photo.CreatedAt, &photo.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+type ReviewPhotoRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReviewPhotoRepository(_db *sql.DB) ReviewPhotoRepository {
+	return &ReviewPhotoRepositoryImpl{
+		db: _db,
+	}
+}
+
+// PhotoLimitError is returned by CreateAll when the photos would take a review past its limit.
+type PhotoLimitError struct {
+	Limit    int
+	Existing int
+}
+
+func (e *PhotoLimitError) Error() string {
+	return fmt.Sprintf("review already has %d of at most %d photos", e.Existing, e.Limit)
+}
+
+func (r *ReviewPhotoRepositoryImpl) CreateAll(reviewId int64, photos []*models.ReviewPhoto, limit int) ([]*models.ReviewPhoto, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the review row so concurrent uploads to the same review count and insert one after another
+	var locked int64
+	if err := tx.QueryRow("SELECT id FROM reviews WHERE id = ? FOR UPDATE", reviewId).Scan(&locked); err != nil {
+		fmt.Println("Error locking review:", err)
+		return nil, err
+	}
+
+	var existing int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM review_photos WHERE review_id = ? AND deleted_at IS NULL", reviewId).Scan(&existing); err != nil {
+		fmt.Println("Error counting review photos:", err)
+		return nil, err
+	}
+	if existing+len(photos) > limit {
+		return nil, &PhotoLimitError{Limit: limit, Existing: existing}
+	}
+
+	created := make([]*models.ReviewPhoto, 0, len(photos))
+	query := "INSERT INTO review_photos (review_id, user_id, blob_key, thumbnail_key, content_type, size_bytes, width, height) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	for _, photo := range photos {
+		result, err := tx.Exec(query, reviewId, photo.UserId, photo.BlobKey, photo.ThumbnailKey, photo.ContentType, photo.SizeBytes, photo.Width, photo.Height)
+		if err != nil {
+			fmt.Println("Error creating review photo:", err)
+			return nil, err
+		}
+
+		lastInsertID, err := result.LastInsertId()
+		if err != nil {
+			fmt.Println("Error getting last insert ID:", err)
+			return nil, err
+		}
+
+		stored, err := scanReviewPhoto(tx.QueryRow("SELECT "+reviewPhotoColumns+" FROM review_photos WHERE id = ?", lastInsertID))
+		if err != nil {
+			fmt.Println("Error scanning review photo:", err)
+			return nil, err
+		}
+		created = append(created, stored)
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review photos:", err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *ReviewPhotoRepositoryImpl) GetById(id int64) (*models.ReviewPhoto, error) {
+	query := "SELECT " + reviewPhotoColumns + " FROM review_photos WHERE id = ? AND deleted_at IS NULL"
+	photo, err := scanReviewPhoto(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review photo:", err)
+		return nil, err
+	}
+	return photo, nil
+}
+
+func (r *ReviewPhotoRepositoryImpl) GetByReviewId(reviewId int64) ([]*models.ReviewPhoto, error) {
+	photos, err := r.GetByReviewIds([]int64{reviewId})
+	if err != nil {
+		return nil, err
+	}
+	return photos[reviewId], nil
+}
+
+// GetByReviewIds loads the live photos of several reviews in one query, keyed by review ID and in upload order.
+func (r *ReviewPhotoRepositoryImpl) GetByReviewIds(reviewIds []int64) (map[int64][]*models.ReviewPhoto, error) {
+	photos := map[int64][]*models.ReviewPhoto{}
+	if len(reviewIds) == 0 {
+		return photos, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(reviewIds)), ", ")
+	args := make([]any, len(reviewIds))
+	for i, id := range reviewIds {
+		args[i] = id
+	}
+
+	query := "SELECT " + reviewPhotoColumns + " FROM review_photos WHERE review_id IN (" + placeholders + ") AND deleted_at IS NULL ORDER BY id"
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		fmt.Println("Error fetching review photos:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanReviewPhoto(rows)
+		if err != nil {
+			fmt.Println("Error scanning review photo:", err)
+			return nil, err
+		}
+		photos[photo.ReviewId] = append(photos[photo.ReviewId], photo)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+func (r *ReviewPhotoRepositoryImpl) CountByReviewId(reviewId int64) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM review_photos WHERE review_id = ? AND deleted_at IS NULL"
+	if err := r.db.QueryRow(query, reviewId).Scan(&count); err != nil {
+		fmt.Println("Error counting review photos:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *ReviewPhotoRepositoryImpl) Delete(id int64) error {
+	query := "UPDATE review_photos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		fmt.Println("Error deleting review photo:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("review photo not found")
+	}
+	return nil
+}
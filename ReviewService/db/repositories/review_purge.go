@@ -0,0 +1,227 @@
+package db
+
+import (
+	"ReviewService/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// GetDeletedByID returns a soft-deleted review, or nil if the review does not exist or is not deleted.
+func (r *ReviewRepositoryImpl) GetDeletedByID(id int64) (*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE id = ? AND deleted_at IS NOT NULL"
+	review, err := ScanReview(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review:", err)
+		return nil, err
+	}
+	return review, nil
+}
+
+// Restore undoes a soft delete, publishing it as review.updated, and returns the review, or nil if it is not deleted.
+// It returns ErrDuplicateEntry when the booking has been reviewed again since.
+func (r *ReviewRepositoryImpl) Restore(id int64) (*models.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE reviews SET deleted_at = NULL, is_synced = FALSE WHERE id = ? AND deleted_at IS NOT NULL"
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, ErrDuplicateEntry
+		}
+		fmt.Println("Error restoring review:", err)
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+
+	if err := insertReviewEvent(tx, models.EventReviewUpdated, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review restore:", err)
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// GetPurgeableIds returns reviews soft-deleted more than olderThanDays days ago whose deletion has reached
+// HotelService, in id order after afterId.
+func (r *ReviewRepositoryImpl) GetPurgeableIds(olderThanDays int, afterId int64, limit int) ([]int64, error) {
+	query := "SELECT id FROM reviews WHERE deleted_at < NOW() - INTERVAL ? DAY AND is_synced = TRUE AND id > ? ORDER BY id LIMIT ?"
+	rows, err := r.db.Query(query, olderThanDays, afterId, limit)
+	if err != nil {
+		fmt.Println("Error fetching purgeable reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			fmt.Println("Error scanning review ID:", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// reviewChildTables are the tables whose rows belong to a review, deleted before the review itself.
+// Tables without a count are derived data that the report leaves out.
+var reviewChildTables = []struct {
+	table string
+	count func(report *models.PurgeReport) *int64
+}{
+	{"review_photos", func(report *models.PurgeReport) *int64 { return &report.Photos }},
+	{"review_votes", func(report *models.PurgeReport) *int64 { return &report.Votes }},
+	{"review_reports", func(report *models.PurgeReport) *int64 { return &report.Reports }},
+	{"review_responses", func(report *models.PurgeReport) *int64 { return &report.Responses }},
+	{"review_category_ratings", func(report *models.PurgeReport) *int64 { return &report.CategoryRatings }},
+	{"review_aspects", func(report *models.PurgeReport) *int64 { return &report.Aspects }},
+	{"review_revisions", func(report *models.PurgeReport) *int64 { return &report.Revisions }},
+	{"review_fingerprint_bands", nil},
+	{"review_fingerprints", nil},
+	{"review_fraud_checks", func(report *models.PurgeReport) *int64 { return &report.FraudChecks }},
+}
+
+// Purge permanently deletes the given reviews and every row that belongs to them. Only reviews that are
+// soft-deleted and synced are purged; the others are skipped. A dry run counts the same rows in a read-only
+// transaction instead, without locking or deleting anything. Blobs are left to the caller.
+func (r *ReviewRepositoryImpl) Purge(ids []int64, dryRun bool) (*models.PurgeReport, error) {
+	report := &models.PurgeReport{DryRun: dryRun, ReviewIds: []int64{}, BlobKeys: []string{}}
+	if len(ids) == 0 {
+		return report, nil
+	}
+
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: dryRun})
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the reviews that qualify so a concurrent restore cannot slip in between the checks and the deletes
+	lock := " FOR UPDATE"
+	if dryRun {
+		lock = ""
+	}
+	placeholders, args := inClause(ids)
+	rows, err := tx.Query("SELECT id FROM reviews WHERE id IN ("+placeholders+") AND deleted_at IS NOT NULL AND is_synced = TRUE"+lock, args...)
+	if err != nil {
+		fmt.Println("Error locking reviews for purge:", err)
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.ReviewIds = append(report.ReviewIds, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(report.ReviewIds) == 0 {
+		return report, nil
+	}
+
+	placeholders, args = inClause(report.ReviewIds)
+	rows, err = tx.Query("SELECT blob_key, thumbnail_key FROM review_photos WHERE review_id IN ("+placeholders+")", args...)
+	if err != nil {
+		fmt.Println("Error fetching photo blobs for purge:", err)
+		return nil, err
+	}
+	for rows.Next() {
+		var blobKey, thumbnailKey string
+		if err := rows.Scan(&blobKey, &thumbnailKey); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.BlobKeys = append(report.BlobKeys, blobKey, thumbnailKey)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		for _, child := range reviewChildTables {
+			if child.count == nil {
+				continue
+			}
+			err := tx.QueryRow("SELECT COUNT(*) FROM "+child.table+" WHERE review_id IN ("+placeholders+")", args...).Scan(child.count(report))
+			if err != nil {
+				fmt.Println("Error counting", child.table, "to purge:", err)
+				return nil, err
+			}
+		}
+		report.Reviews = int64(len(report.ReviewIds))
+		return report, nil
+	}
+
+	for _, child := range reviewChildTables {
+		deleted, err := execRowsAffected(tx, "DELETE FROM "+child.table+" WHERE review_id IN ("+placeholders+")", args...)
+		if err != nil {
+			fmt.Println("Error purging", child.table+":", err)
+			return nil, err
+		}
+		if child.count != nil {
+			*child.count(report) = deleted
+		}
+	}
+
+	report.Reviews, err = execRowsAffected(tx, "DELETE FROM reviews WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		fmt.Println("Error purging reviews:", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing purge:", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func inClause(ids []int64) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+func execRowsAffected(tx *sql.Tx, query string, args ...any) (int64, error) {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
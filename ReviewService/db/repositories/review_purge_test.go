@@ -0,0 +1,194 @@
+package db
+
+import (
+	"ReviewService/models"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// purgeDatabase is a database/sql driver that records statements and answers the ones Purge makes: the
+// qualifying reviews, their photo blobs, and childRows rows in every child table.
+type purgeDatabase struct {
+	mu         sync.Mutex
+	purgeable  []int64
+	blobs      [][]driver.Value
+	childRows  int64
+	statements []string
+	readOnly   bool
+	committed  bool
+}
+
+func (d *purgeDatabase) Connect(ctx context.Context) (driver.Conn, error) { return &purgeConn{d}, nil }
+func (d *purgeDatabase) Driver() driver.Driver                            { return nil }
+
+type purgeConn struct{ db *purgeDatabase }
+
+func (c *purgeConn) Prepare(query string) (driver.Stmt, error) { return &purgeStmt{c.db, query}, nil }
+func (c *purgeConn) Close() error                              { return nil }
+func (c *purgeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *purgeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.readOnly = opts.ReadOnly
+	return &purgeTx{c.db}, nil
+}
+
+type purgeTx struct{ db *purgeDatabase }
+
+func (t *purgeTx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.committed = true
+	return nil
+}
+
+func (t *purgeTx) Rollback() error { return nil }
+
+type purgeStmt struct {
+	db    *purgeDatabase
+	query string
+}
+
+func (s *purgeStmt) Close() error  { return nil }
+func (s *purgeStmt) NumInput() int { return -1 }
+
+func (s *purgeStmt) record() {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, s.query)
+}
+
+func (s *purgeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.record()
+	if strings.HasPrefix(s.query, "DELETE FROM reviews ") {
+		return driver.RowsAffected(len(s.db.purgeable)), nil
+	}
+	return driver.RowsAffected(s.db.childRows), nil
+}
+
+func (s *purgeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.record()
+	switch {
+	case strings.HasPrefix(s.query, "SELECT id FROM reviews"):
+		rows := &purgeRows{columns: []string{"id"}}
+		for _, id := range s.db.purgeable {
+			rows.values = append(rows.values, []driver.Value{id})
+		}
+		return rows, nil
+	case strings.HasPrefix(s.query, "SELECT blob_key"):
+		return &purgeRows{columns: []string{"blob_key", "thumbnail_key"}, values: s.db.blobs}, nil
+	default:
+		return &purgeRows{columns: []string{"COUNT(*)"}, values: [][]driver.Value{{s.db.childRows}}}, nil
+	}
+}
+
+type purgeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *purgeRows) Columns() []string { return r.columns }
+func (r *purgeRows) Close() error      { return nil }
+
+func (r *purgeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newPurgeRepository(t *testing.T, database *purgeDatabase) ReviewRepository {
+	t.Helper()
+	conn := sql.OpenDB(database)
+	t.Cleanup(func() { conn.Close() })
+	return NewReviewRepository(conn)
+}
+
+func (d *purgeDatabase) statementsStartingWith(prefix string) []string {
+	var matched []string
+	for _, statement := range d.statements {
+		if strings.HasPrefix(statement, prefix) {
+			matched = append(matched, statement)
+		}
+	}
+	return matched
+}
+
+func TestPurgeDeletesReviewsAndChildRows(t *testing.T) {
+	database := &purgeDatabase{purgeable: []int64{3, 5}, blobs: [][]driver.Value{{"photos/a.jpg", "photos/a_thumb.jpg"}}, childRows: 2}
+
+	report, err := newPurgeRepository(t, database).Purge([]int64{3, 4, 5}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !database.committed || database.readOnly {
+		t.Error("purge was not committed in a read-write transaction")
+	}
+	if locks := database.statementsStartingWith("SELECT id FROM reviews"); len(locks) != 1 || !strings.HasSuffix(locks[0], "FOR UPDATE") {
+		t.Errorf("lock query %v, want the qualifying reviews locked", locks)
+	}
+	if deletes := database.statementsStartingWith("DELETE FROM"); len(deletes) != len(reviewChildTables)+1 {
+		t.Errorf("%d deletes, want one per child table and one for the reviews", len(deletes))
+	}
+
+	want := &models.PurgeReport{
+		ReviewIds: []int64{3, 5}, Reviews: 2, Photos: 2, Votes: 2, Reports: 2, Responses: 2, CategoryRatings: 2,
+		Aspects: 2, Revisions: 2, FraudChecks: 2, BlobKeys: []string{"photos/a.jpg", "photos/a_thumb.jpg"},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("report %+v, want %+v", report, want)
+	}
+}
+
+func TestPurgeDryRunOnlyCounts(t *testing.T) {
+	database := &purgeDatabase{purgeable: []int64{3, 5}, blobs: [][]driver.Value{{"photos/a.jpg", "photos/a_thumb.jpg"}}, childRows: 4}
+
+	report, err := newPurgeRepository(t, database).Purge([]int64{3, 5}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if database.committed || !database.readOnly {
+		t.Error("dry run did not stay in an uncommitted read-only transaction")
+	}
+	for _, statement := range database.statements {
+		if !strings.HasPrefix(statement, "SELECT") || strings.Contains(statement, "FOR UPDATE") {
+			t.Errorf("dry run ran %q, want plain reads only", statement)
+		}
+	}
+	if counts := database.statementsStartingWith("SELECT COUNT(*)"); len(counts) == 0 {
+		t.Error("dry run did not count the child rows")
+	}
+
+	want := &models.PurgeReport{
+		DryRun: true, ReviewIds: []int64{3, 5}, Reviews: 2, Photos: 4, Votes: 4, Reports: 4, Responses: 4, CategoryRatings: 4,
+		Aspects: 4, Revisions: 4, FraudChecks: 4, BlobKeys: []string{"photos/a.jpg", "photos/a_thumb.jpg"},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("report %+v, want %+v", report, want)
+	}
+}
+
+func TestPurgeSkipsReviewsThatDoNotQualify(t *testing.T) {
+	database := &purgeDatabase{childRows: 2}
+
+	report, err := newPurgeRepository(t, database).Purge([]int64{3}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Reviews != 0 || len(report.ReviewIds) != 0 || len(database.statementsStartingWith("DELETE")) != 0 {
+		t.Errorf("report %+v after %v, want nothing purged", report, database.statements)
+	}
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+)
+
+type ReviewReportRepository interface {
+	GetReport(reviewId int64, reporterId int64) (*models.ReviewReport, error)
+	// Create stores the report and adds it to the review's open report totals. If the open weight reaches
+	// hideThreshold while the review is published, the review is hidden with hideReason, and Create
+	// returns true. A second report by the same user fails with ErrDuplicateEntry.
+	Create(report *models.ReviewReport, hideThreshold float64, hideReason string) (*models.ReviewReport, bool, error)
+	// GetOpenReasonCounts counts the open reports of several reviews by reason, keyed by review ID.
+	GetOpenReasonCounts(reviewIds []int64) (map[int64]map[string]int64, error)
+}
+
+const reviewReportColumns = "id, review_id, reporter_id, reason, details, weight, created_at, resolved_at, resolved_by"
+
+type ReviewReportRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReviewReportRepository(_db *sql.DB) ReviewReportRepository {
+	return &ReviewReportRepositoryImpl{
+		db: _db,
+	}
+}
+
+func scanReviewReport(row RowScanner) (*models.ReviewReport, error) {
+	report := &models.ReviewReport{}
+	if err := row.Scan(&report.Id, &report.ReviewId, &report.ReporterId, &report.Reason, &report.Details, &report.Weight, &report.CreatedAt, &report.ResolvedAt, &report.ResolvedBy); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *ReviewReportRepositoryImpl) GetReport(reviewId int64, reporterId int64) (*models.ReviewReport, error) {
+	query := "SELECT " + reviewReportColumns + " FROM review_reports WHERE review_id = ? AND reporter_id = ?"
+	report, err := scanReviewReport(r.db.QueryRow(query, reviewId, reporterId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review report:", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *ReviewReportRepositoryImpl) Create(report *models.ReviewReport, hideThreshold float64, hideReason string) (*models.ReviewReport, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Lock the review row first so concurrent reports on the same review serialize on it
+	var status string
+	var openWeight float64
+	err = tx.QueryRow("SELECT moderation_status, report_weight FROM reviews WHERE id = ? AND deleted_at IS NULL FOR UPDATE", report.ReviewId).Scan(&status, &openWeight)
+	if err != nil {
+		fmt.Println("Error locking review:", err)
+		return nil, false, err
+	}
+
+	query := "INSERT INTO review_reports (review_id, reporter_id, reason, details, weight) VALUES (?, ?, ?, ?, ?)"
+	result, err := tx.Exec(query, report.ReviewId, report.ReporterId, report.Reason, report.Details, report.Weight)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, false, ErrDuplicateEntry
+		}
+		fmt.Println("Error creating review report:", err)
+		return nil, false, err
+	}
+	reportId, err := result.LastInsertId()
+	if err != nil {
+		fmt.Println("Error getting last insert ID:", err)
+		return nil, false, err
+	}
+
+	// updated_at is assigned to itself so a report does not count as an edit of the review
+	_, err = tx.Exec("UPDATE reviews SET report_count = report_count + 1, report_weight = report_weight + ?, updated_at = updated_at WHERE id = ?", report.Weight, report.ReviewId)
+	if err != nil {
+		fmt.Println("Error updating review report totals:", err)
+		return nil, false, err
+	}
+
+	hidden := status == models.ModerationApproved && openWeight+report.Weight >= hideThreshold
+	if hidden {
+		// Taking the review down changes the hotel's rating, so the hotel is resynced
+		query := "UPDATE reviews SET moderation_status = ?, moderation_reason = ?, moderated_by = NULL, moderated_at = NULL, is_synced = FALSE WHERE id = ?"
+		if _, err := tx.Exec(query, models.ModerationHidden, hideReason, report.ReviewId); err != nil {
+			fmt.Println("Error hiding reported review:", err)
+			return nil, false, err
+		}
+		if err := insertReviewEvent(tx, models.EventReviewUpdated, report.ReviewId); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review report:", err)
+		return nil, false, err
+	}
+
+	created, err := scanReviewReport(r.db.QueryRow("SELECT "+reviewReportColumns+" FROM review_reports WHERE id = ?", reportId))
+	if err != nil {
+		fmt.Println("Error scanning review report:", err)
+		return nil, false, err
+	}
+	return created, hidden, nil
+}
+
+func (r *ReviewReportRepositoryImpl) GetOpenReasonCounts(reviewIds []int64) (map[int64]map[string]int64, error) {
+	counts := map[int64]map[string]int64{}
+	if len(reviewIds) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := inClause(reviewIds)
+	query := "SELECT review_id, reason, COUNT(*) FROM review_reports WHERE review_id IN (" + placeholders + ") AND resolved_at IS NULL GROUP BY review_id, reason"
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		fmt.Println("Error counting review reports:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewId, count int64
+		var reason string
+		if err := rows.Scan(&reviewId, &reason, &count); err != nil {
+			fmt.Println("Error scanning report count:", err)
+			return nil, err
+		}
+		if counts[reviewId] == nil {
+			counts[reviewId] = map[string]int64{}
+		}
+		counts[reviewId][reason] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type ReviewResponseRepository interface {
+	// Create stores a response. It returns ErrDuplicateEntry if the review already has a live response.
+	Create(response *models.ReviewResponse) (*models.ReviewResponse, error)
+	GetByReviewId(reviewId int64) (*models.ReviewResponse, error)
+	GetByReviewIds(reviewIds []int64) (map[int64]*models.ReviewResponse, error)
+	Update(id int64, body string) (*models.ReviewResponse, error)
+	Delete(id int64) error
+}
+
+const reviewResponseColumns = "id, review_id, responder_id, body, created_at, updated_at, deleted_at"
+
+func scanReviewResponse(row RowScanner) (*models.ReviewResponse, error) {
+	response := &models.ReviewResponse{}
+	err := row.Scan(&response.Id, &response.ReviewId, &response.ResponderId, &response.Body, &response.CreatedAt, &response.UpdatedAt, &response.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+type ReviewResponseRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReviewResponseRepository(_db *sql.DB) ReviewResponseRepository {
+	return &ReviewResponseRepositoryImpl{
+		db: _db,
+	}
+}
+
+func (r *ReviewResponseRepositoryImpl) Create(response *models.ReviewResponse) (*models.ReviewResponse, error) {
+	query := "INSERT INTO review_responses (review_id, responder_id, body) VALUES (?, ?, ?)"
+	result, err := r.db.Exec(query, response.ReviewId, response.ResponderId, response.Body)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, ErrDuplicateEntry
+		}
+		fmt.Println("Error creating review response:", err)
+		return nil, err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		fmt.Println("Error getting last insert ID:", err)
+		return nil, err
+	}
+
+	return r.getById(lastInsertID)
+}
+
+func (r *ReviewResponseRepositoryImpl) getById(id int64) (*models.ReviewResponse, error) {
+	query := "SELECT " + reviewResponseColumns + " FROM review_responses WHERE id = ? AND deleted_at IS NULL"
+	response, err := scanReviewResponse(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review response:", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (r *ReviewResponseRepositoryImpl) GetByReviewId(reviewId int64) (*models.ReviewResponse, error) {
+	query := "SELECT " + reviewResponseColumns + " FROM review_responses WHERE review_id = ? AND deleted_at IS NULL"
+	response, err := scanReviewResponse(r.db.QueryRow(query, reviewId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review response:", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetByReviewIds loads the live responses of several reviews in one query, keyed by review ID.
+func (r *ReviewResponseRepositoryImpl) GetByReviewIds(reviewIds []int64) (map[int64]*models.ReviewResponse, error) {
+	responses := map[int64]*models.ReviewResponse{}
+	if len(reviewIds) == 0 {
+		return responses, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(reviewIds)), ", ")
+	args := make([]any, len(reviewIds))
+	for i, id := range reviewIds {
+		args[i] = id
+	}
+
+	query := "SELECT " + reviewResponseColumns + " FROM review_responses WHERE review_id IN (" + placeholders + ") AND deleted_at IS NULL"
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		fmt.Println("Error fetching review responses:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		response, err := scanReviewResponse(rows)
+		if err != nil {
+			fmt.Println("Error scanning review response:", err)
+			return nil, err
+		}
+		responses[response.ReviewId] = response
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+func (r *ReviewResponseRepositoryImpl) Update(id int64, body string) (*models.ReviewResponse, error) {
+	query := "UPDATE review_responses SET body = ? WHERE id = ? AND deleted_at IS NULL"
+	result, err := r.db.Exec(query, body, id)
+	if err != nil {
+		fmt.Println("Error updating review response:", err)
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("review response not found")
+	}
+
+	return r.getById(id)
+}
+
+func (r *ReviewResponseRepositoryImpl) Delete(id int64) error {
+	query := "UPDATE review_responses SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		fmt.Println("Error deleting review response:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("review response not found")
+	}
+	return nil
+}
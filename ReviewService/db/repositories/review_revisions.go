@@ -0,0 +1,61 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// insertRevision snapshots the review's current comment, rating and category ratings inside tx as its next revision.
+// Callers have already written the review in tx, which holds the row lock that keeps revision numbers in order.
+func insertRevision(tx *sql.Tx, reviewId int64, editorId int64) error {
+	query := `INSERT INTO review_revisions (review_id, revision, editor_id, comment, rating, category_ratings)
+	SELECT r.id,
+		(SELECT COALESCE(MAX(v.revision), 0) + 1 FROM review_revisions v WHERE v.review_id = r.id),
+		?, r.comment, r.rating,
+		(SELECT JSON_OBJECTAGG(c.category, c.rating) FROM review_category_ratings c WHERE c.review_id = r.id)
+	FROM reviews r WHERE r.id = ?`
+	if _, err := tx.Exec(query, editorId, reviewId); err != nil {
+		fmt.Println("Error recording review revision:", err)
+		return err
+	}
+	return nil
+}
+
+// GetRevisions returns a review's revisions, oldest first, whether or not the review has since been deleted.
+func (r *ReviewRepositoryImpl) GetRevisions(reviewId int64) ([]*models.ReviewRevision, error) {
+	query := "SELECT id, review_id, revision, editor_id, comment, rating, category_ratings, created_at FROM review_revisions WHERE review_id = ? ORDER BY revision"
+	rows, err := r.db.Query(query, reviewId)
+	if err != nil {
+		fmt.Println("Error fetching review revisions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.ReviewRevision
+	for rows.Next() {
+		revision := &models.ReviewRevision{}
+		var categoryRatings []byte
+		if err := rows.Scan(&revision.Id, &revision.ReviewId, &revision.Revision, &revision.EditorId, &revision.Comment, &revision.Rating, &categoryRatings, &revision.CreatedAt); err != nil {
+			fmt.Println("Error scanning review revision:", err)
+			return nil, err
+		}
+
+		revision.CategoryRatings = map[string]int{}
+		if categoryRatings != nil {
+			if err := json.Unmarshal(categoryRatings, &revision.CategoryRatings); err != nil {
+				fmt.Println("Error decoding revision category ratings:", err)
+				return nil, err
+			}
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// GetActiveReviewIds finds the active reviews for the given booking and user pairs. Pairs without one are absent.
+func (r *ReviewRepositoryImpl) GetActiveReviewIds(keys []models.BookingUser) (map[models.BookingUser]int64, error) {
+	return getActiveReviewIds(r.db, keys)
+}
+
+func getActiveReviewIds(q queryer, keys []models.BookingUser) (map[models.BookingUser]int64, error) {
+	ids := map[models.BookingUser]int64{}
+	if len(keys) == 0 {
+		return ids, nil
+	}
+
+	args := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		args = append(args, key.BookingId, key.UserId)
+	}
+	pairs := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(keys)), ", ")
+
+	rows, err := q.Query("SELECT id, booking_id, user_id FROM reviews WHERE deleted_at IS NULL AND (booking_id, user_id) IN ("+pairs+")", args...)
+	if err != nil {
+		fmt.Println("Error fetching active reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var key models.BookingUser
+		if err := rows.Scan(&id, &key.BookingId, &key.UserId); err != nil {
+			fmt.Println("Error scanning active review:", err)
+			return nil, err
+		}
+		ids[key] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ImportBatch inserts reviews brought in from another system in one transaction: the reviews with multi-row inserts,
+// then their category ratings, aspects, first revisions and review.created events. A review keeps its CreatedAt when
+// set, and gets its ID filled in. On a dry run the same statements run and are rolled back.
+func (r *ReviewRepositoryImpl) ImportBatch(reviews []*models.Review, dryRun bool) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	// updated_at starts equal to created_at, so an imported review does not count as changed since it was written
+	var values []string
+	var args []any
+	keys := make([]models.BookingUser, len(reviews))
+	for i, review := range reviews {
+		var createdAt *string
+		if review.CreatedAt != "" {
+			createdAt = &review.CreatedAt
+		}
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))")
+		args = append(args, review.UserId, review.BookingId, review.HotelId, review.Comment, review.Rating, review.IsVerifiedStay,
+			review.ModerationStatus, review.ModerationReason, review.SentimentScore, review.SentimentVersion, review.Language, createdAt, createdAt)
+		keys[i] = models.BookingUser{BookingId: review.BookingId, UserId: review.UserId}
+	}
+	query := "INSERT INTO reviews (user_id, booking_id, hotel_id, comment, rating, is_verified_stay, moderation_status, moderation_reason, sentiment_score, sentiment_version, language, created_at, updated_at) VALUES " + strings.Join(values, ", ")
+	if _, err := tx.Exec(query, args...); err != nil {
+		if isDuplicateEntry(err) {
+			return ErrDuplicateEntry
+		}
+		fmt.Println("Error importing reviews:", err)
+		return err
+	}
+
+	// A booking and user have at most one active review, so the pairs identify the new rows
+	ids, err := getActiveReviewIds(tx, keys)
+	if err != nil {
+		return err
+	}
+	reviewIds := make([]int64, len(reviews))
+	for i, review := range reviews {
+		review.Id = ids[keys[i]]
+		reviewIds[i] = review.Id
+	}
+
+	values, args = nil, nil
+	for _, review := range reviews {
+		for _, category := range models.ReviewCategories {
+			if rating, ok := review.CategoryRatings[category]; ok {
+				values = append(values, "(?, ?, ?)")
+				args = append(args, review.Id, category, rating)
+			}
+		}
+	}
+	if len(values) > 0 {
+		if _, err := tx.Exec("INSERT INTO review_category_ratings (review_id, category, rating) VALUES "+strings.Join(values, ", "), args...); err != nil {
+			fmt.Println("Error importing category ratings:", err)
+			return err
+		}
+	}
+
+	values, args = nil, nil
+	for _, review := range reviews {
+		for aspect, sentiment := range review.Aspects {
+			values = append(values, "(?, ?, ?)")
+			args = append(args, review.Id, aspect, sentiment)
+		}
+	}
+	if len(values) > 0 {
+		if _, err := tx.Exec("INSERT INTO review_aspects (review_id, aspect, sentiment) VALUES "+strings.Join(values, ", "), args...); err != nil {
+			fmt.Println("Error importing review aspects:", err)
+			return err
+		}
+	}
+
+	if err := insertFingerprints(tx, reviews); err != nil {
+		return err
+	}
+
+	placeholders, idArgs := inClause(reviewIds)
+	revisions := `INSERT INTO review_revisions (review_id, revision, editor_id, comment, rating, category_ratings, created_at)
+	SELECT r.id, 1, r.user_id, r.comment, r.rating,
+		(SELECT JSON_OBJECTAGG(c.category, c.rating) FROM review_category_ratings c WHERE c.review_id = r.id),
+		r.created_at
+	FROM reviews r WHERE r.id IN (` + placeholders + `)`
+	if _, err := tx.Exec(revisions, idArgs...); err != nil {
+		fmt.Println("Error recording imported revisions:", err)
+		return err
+	}
+
+	if err := insertReviewEvents(tx, models.EventReviewCreated, reviewIds); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing import batch:", err)
+		return err
+	}
+	return nil
+}
+
+// exportCategoryColumns selects each category rating as a column, NULL when not rated.
+var exportCategoryColumns = func() string {
+	columns := make([]string, len(models.ReviewCategories))
+	for i, category := range models.ReviewCategories {
+		columns[i] = "(SELECT c.rating FROM review_category_ratings c WHERE c.review_id = reviews.id AND c.category = '" + category + "')"
+	}
+	return strings.Join(columns, ", ")
+}()
+
+// ListForExport returns the next reviews matching the filter in ID order after afterId, with their category ratings.
+// The filter's sort, cursor and limit are ignored.
+func (r *ReviewRepositoryImpl) ListForExport(filter *models.ReviewFilter, afterId int64, limit int) ([]*models.Review, error) {
+	where, args := reviewFilterWhere(filter)
+	query := "SELECT " + ReviewColumns + ", " + exportCategoryColumns + " FROM reviews WHERE " + where + " AND id > ? ORDER BY id LIMIT ?"
+	args = append(args, afterId, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		fmt.Println("Error exporting reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*models.Review
+	for rows.Next() {
+		ratings := make([]sql.NullInt64, len(models.ReviewCategories))
+		extra := make([]any, len(ratings))
+		for i := range ratings {
+			extra[i] = &ratings[i]
+		}
+
+		review, err := ScanReview(rows, extra...)
+		if err != nil {
+			fmt.Println("Error scanning review:", err)
+			return nil, err
+		}
+		review.CategoryRatings = map[string]int{}
+		for i, category := range models.ReviewCategories {
+			if ratings[i].Valid {
+				review.CategoryRatings[category] = int(ratings[i].Int64)
+			}
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
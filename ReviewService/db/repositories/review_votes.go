@@ -0,0 +1,124 @@
+package db
+
+import (
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+)
+
+type ReviewVoteRepository interface {
+	GetVote(reviewId int64, userId int64) (*models.ReviewVote, error)
+	// ToggleVote records the user's vote, switches it when it differs from their current one and removes it
+	// when it is the same. It returns the vote now in place, or nil if it was removed, and keeps the review's totals in step.
+	ToggleVote(reviewId int64, userId int64, helpful bool) (*models.ReviewVote, error)
+}
+
+const reviewVoteColumns = "id, review_id, user_id, helpful, created_at, updated_at"
+
+type ReviewVoteRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReviewVoteRepository(_db *sql.DB) ReviewVoteRepository {
+	return &ReviewVoteRepositoryImpl{
+		db: _db,
+	}
+}
+
+func scanReviewVote(row RowScanner) (*models.ReviewVote, error) {
+	vote := &models.ReviewVote{}
+	if err := row.Scan(&vote.Id, &vote.ReviewId, &vote.UserId, &vote.Helpful, &vote.CreatedAt, &vote.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+func (r *ReviewVoteRepositoryImpl) GetVote(reviewId int64, userId int64) (*models.ReviewVote, error) {
+	query := "SELECT " + reviewVoteColumns + " FROM review_votes WHERE review_id = ? AND user_id = ?"
+	vote, err := scanReviewVote(r.db.QueryRow(query, reviewId, userId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review vote:", err)
+		return nil, err
+	}
+	return vote, nil
+}
+
+func (r *ReviewVoteRepositoryImpl) ToggleVote(reviewId int64, userId int64, helpful bool) (*models.ReviewVote, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the review row first so concurrent votes on the same review serialize on it
+	var locked int64
+	if err := tx.QueryRow("SELECT id FROM reviews WHERE id = ? FOR UPDATE", reviewId).Scan(&locked); err != nil {
+		fmt.Println("Error locking review:", err)
+		return nil, err
+	}
+
+	query := "SELECT " + reviewVoteColumns + " FROM review_votes WHERE review_id = ? AND user_id = ? FOR UPDATE"
+	existing, err := scanReviewVote(tx.QueryRow(query, reviewId, userId))
+	if err != nil && err != sql.ErrNoRows {
+		fmt.Println("Error scanning review vote:", err)
+		return nil, err
+	}
+
+	helpfulDelta, notHelpfulDelta := 0, 0
+	adjust := func(helpful bool, delta int) {
+		if helpful {
+			helpfulDelta += delta
+		} else {
+			notHelpfulDelta += delta
+		}
+	}
+
+	switch {
+	case existing == nil:
+		if _, err := tx.Exec("INSERT INTO review_votes (review_id, user_id, helpful) VALUES (?, ?, ?)", reviewId, userId, helpful); err != nil {
+			fmt.Println("Error creating review vote:", err)
+			return nil, err
+		}
+		adjust(helpful, 1)
+	case existing.Helpful == helpful:
+		if _, err := tx.Exec("DELETE FROM review_votes WHERE id = ?", existing.Id); err != nil {
+			fmt.Println("Error deleting review vote:", err)
+			return nil, err
+		}
+		adjust(helpful, -1)
+	default:
+		if _, err := tx.Exec("UPDATE review_votes SET helpful = ? WHERE id = ?", helpful, existing.Id); err != nil {
+			fmt.Println("Error updating review vote:", err)
+			return nil, err
+		}
+		adjust(existing.Helpful, -1)
+		adjust(helpful, 1)
+	}
+
+	// Re-read the vote in place so its id and timestamps are the stored ones
+	current, err := scanReviewVote(tx.QueryRow(query, reviewId, userId))
+	if err == sql.ErrNoRows {
+		current = nil
+	} else if err != nil {
+		fmt.Println("Error scanning review vote:", err)
+		return nil, err
+	}
+
+	// updated_at is assigned to itself so a vote does not count as an edit of the review
+	_, err = tx.Exec("UPDATE reviews SET helpful_count = helpful_count + ?, not_helpful_count = not_helpful_count + ?, updated_at = updated_at WHERE id = ?", helpfulDelta, notHelpfulDelta, reviewId)
+	if err != nil {
+		fmt.Println("Error updating review vote totals:", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review vote:", err)
+		return nil, err
+	}
+
+	return current, nil
+}
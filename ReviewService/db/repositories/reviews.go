@@ -2,21 +2,78 @@ package db
 
 import (
 	"ReviewService/models"
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 )
 
 type ReviewRepository interface {
 	GetByID(id int64) (*models.Review, error)
-	Create(userId int64, bookingId int64, hotelId int64, comment string, rating int) (*models.Review, error)
-	Update(id int64, comment string, rating int) (*models.Review, error)
+	Create(review *models.Review) (*models.Review, error)
+	Update(review *models.Review, editorId int64) (*models.Review, error)
+	SetModerationStatus(id int64, status string, fromStatuses []string, reason *string, moderatorId int64) (*models.Review, error)
 	Delete(id int64) error
-	GetAll() ([]*models.Review, error)
-	GetByUserId(userId int64) ([]*models.Review, error)
-	GetByHotelId(hotelId int64) ([]*models.Review, error)
+	List(filter *models.ReviewFilter) ([]*models.Review, error)
+	Count(filter *models.ReviewFilter) (int64, error)
 	GetByBookingId(bookingId int64) ([]*models.Review, error)
+	GetActiveByBookingAndUser(bookingId int64, userId int64) (*models.Review, error)
+	GetUnsyncedHotelIds(limit int) ([]int64, error)
+	GetHotelRating(hotelId int64) (*models.HotelRating, error)
+	MarkHotelSynced(hotelId int64, reviews []models.ReviewVersion) (int64, error)
+	RecordHotelSyncFailure(hotelId int64, syncErr error, retryDelay time.Duration, maxRetryDelay time.Duration) error
+	GetHotelRatingSummary(hotelId int64) (*models.HotelRatingSummary, error)
+	GetCategoryRatings(reviewIds []int64) (map[int64]map[string]int, error)
+	GetRevisions(reviewId int64) ([]*models.ReviewRevision, error)
+	GetDeletedByID(id int64) (*models.Review, error)
+	Restore(id int64) (*models.Review, error)
+	GetPurgeableIds(olderThanDays int, afterId int64, limit int) ([]int64, error)
+	Purge(ids []int64, dryRun bool) (*models.PurgeReport, error)
+	GetAspects(reviewIds []int64) (map[int64]map[string]float64, error)
+	GetHotelAspectSummary(hotelId int64, neutralBoundary float64) (*models.HotelAspectSummary, error)
+	GetUnanalyzed(version int, afterId int64, limit int) ([]*models.Review, error)
+	SetSentiment(id int64, score float64, version int, aspects map[string]float64) (bool, error)
+	GetActiveReviewIds(keys []models.BookingUser) (map[models.BookingUser]int64, error)
+	ImportBatch(reviews []*models.Review, dryRun bool) error
+	ListForExport(filter *models.ReviewFilter, afterId int64, limit int) ([]*models.Review, error)
+	GetFraudChecks(reviewId int64) ([]*models.FraudCheck, error)
+	CountRecentByUser(userId int64, window time.Duration) (int, error)
+	GetUserRatingStats(userId int64) (*models.UserRatingStats, error)
+	FindFingerprintMatches(bands []int64, limit int) ([]*models.FingerprintMatch, error)
+	GetUnfingerprinted(afterId int64, limit int) ([]*models.Review, error)
+	SetFingerprint(review *models.Review) (bool, error)
+	GetUnidentified(afterId int64, limit int) ([]*models.Review, error)
+	SetLanguage(id int64, language string) (bool, error)
 }
 
+// ReviewColumns is the column list every review query selects, in the order ScanReview reads them.
+const ReviewColumns = "id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, edited_at, deleted_at, is_synced, is_verified_stay, moderation_status, moderation_reason, moderated_by, moderated_at, takedown_requeued, helpful_count, not_helpful_count, report_count, report_weight, sentiment_score, sentiment_version, language"
+
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// ScanReview reads a row selected with ReviewColumns. Extra destinations receive any columns selected after them.
+func ScanReview(row RowScanner, extra ...any) (*models.Review, error) {
+	review := &models.Review{}
+	dest := []any{&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.EditedAt, &review.DeletedAt, &review.IsSynced, &review.IsVerifiedStay, &review.ModerationStatus, &review.ModerationReason, &review.ModeratedBy, &review.ModeratedAt, &review.TakedownRequeued, &review.HelpfulCount, &review.NotHelpfulCount, &review.ReportCount, &review.ReportWeight, &review.SentimentScore, &review.SentimentVersion, &review.Language}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	review.IsEdited = review.EditedAt != nil
+	return review, nil
+}
+
+// markSyncedBatchSize caps the review versions matched by one UPDATE of MarkHotelSynced.
+const markSyncedBatchSize = 500
+
 type ReviewRepositoryImpl struct {
 	db *sql.DB
 }
@@ -27,25 +84,178 @@ func NewReviewRepository(_db *sql.DB) ReviewRepository {
 	}
 }
 
-func (r *ReviewRepositoryImpl) GetAll() ([]*models.Review, error) {
-	query := "SELECT id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, deleted_at, is_synced FROM reviews WHERE deleted_at IS NULL"
-	rows, err := r.db.Query(query)
+// wilsonLowerBound is the lower bound of the 95% Wilson score interval for the share of helpful votes,
+// so a review with 40 of 50 helpful votes outranks one with 1 of 1.
+const wilsonLowerBound = `IF(helpful_count + not_helpful_count = 0, 0,
+	((helpful_count + 1.9208) / (helpful_count + not_helpful_count)
+	 - 1.96 * SQRT(helpful_count * not_helpful_count / (helpful_count + not_helpful_count) + 0.9604) / (helpful_count + not_helpful_count))
+	/ (1 + 3.8416 / (helpful_count + not_helpful_count)))`
+
+// relevanceScore weighs helpfulness, recency (halving every 90 days), comment length (capped at 500 characters)
+// and verified stay. Every component is in [0, 1]. Its parameter is the time the recency is computed as of.
+// The score is cast to a decimal so a cursor can carry it exactly.
+const relevanceScore = "CAST(0.5 * " + wilsonLowerBound + `
+	+ 0.25 * POW(0.5, TIMESTAMPDIFF(DAY, created_at, ?) / 90)
+	+ 0.15 * LEAST(CHAR_LENGTH(TRIM(comment)), 500) / 500
+	+ 0.10 * is_verified_stay AS DECIMAL(11, 10))`
+
+// reviewSortOrders maps each sort to its ORDER BY and the keyset condition that resumes after a cursor.
+// A sort with a score selects it as sort_score, with filter.RelevanceAsOf as its parameter, and orders by it.
+var reviewSortOrders = map[models.ReviewSort]struct {
+	score   string
+	orderBy string
+	after   string
+	args    func(c *models.ReviewCursor) []any
+}{
+	models.ReviewSortNewest: {
+		orderBy: "created_at DESC, id DESC",
+		after:   "(created_at, id) < (?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.CreatedAt, c.Id} },
+	},
+	models.ReviewSortOldest: {
+		orderBy: "created_at ASC, id ASC",
+		after:   "(created_at, id) > (?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.CreatedAt, c.Id} },
+	},
+	models.ReviewSortHighest: {
+		orderBy: "rating DESC, created_at DESC, id DESC",
+		after:   "(rating, created_at, id) < (?, ?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.Rating, c.CreatedAt, c.Id} },
+	},
+	models.ReviewSortLowest: {
+		orderBy: "rating ASC, created_at ASC, id ASC",
+		after:   "(rating, created_at, id) > (?, ?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.Rating, c.CreatedAt, c.Id} },
+	},
+	models.ReviewSortRelevant: {
+		score:   relevanceScore,
+		orderBy: "sort_score DESC, id DESC",
+		after:   "(" + relevanceScore + ", id) < (?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.AsOf, c.Score, c.Id} },
+	},
+	models.ReviewSortMostReported: {
+		orderBy: "report_count DESC, report_weight DESC, id DESC",
+		after:   "(report_count, report_weight, id) < (?, ?, ?)",
+		args:    func(c *models.ReviewCursor) []any { return []any{c.ReportCount, c.ReportWeight, c.Id} },
+	},
+}
+
+// reviewFilterWhere builds the WHERE clause shared by List and Count, without the cursor condition.
+func reviewFilterWhere(filter *models.ReviewFilter) (string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+
+	if filter.HotelId != nil {
+		conditions = append(conditions, "hotel_id = ?")
+		args = append(args, *filter.HotelId)
+	}
+	if filter.ModerationStatus != "" {
+		conditions = append(conditions, "moderation_status = ?")
+		args = append(args, filter.ModerationStatus)
+	}
+	if filter.UserId != nil {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, *filter.UserId)
+	}
+	if filter.MinRating > 0 {
+		conditions = append(conditions, "rating >= ?")
+		args = append(args, filter.MinRating)
+	}
+	if filter.MaxRating > 0 {
+		conditions = append(conditions, "rating <= ?")
+		args = append(args, filter.MaxRating)
+	}
+	if filter.CreatedFrom != "" {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedFrom)
+	}
+	if filter.CreatedBefore != "" {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.CreatedBefore)
+	}
+	if filter.Reported {
+		conditions = append(conditions, "report_count > 0")
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, "language = ?")
+		args = append(args, filter.Language)
+	}
+	if filter.HasComment != nil {
+		if *filter.HasComment {
+			conditions = append(conditions, "TRIM(comment) <> ''")
+		} else {
+			conditions = append(conditions, "TRIM(comment) = ''")
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// List returns one page of reviews matching the filter, starting after filter.After.
+func (r *ReviewRepositoryImpl) List(filter *models.ReviewFilter) ([]*models.Review, error) {
+	order, ok := reviewSortOrders[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q", filter.Sort)
+	}
+
+	columns := ReviewColumns
+	var args []any
+	if order.score != "" {
+		columns += ", " + order.score + " AS sort_score"
+		args = append(args, filter.RelevanceAsOf)
+	}
+
+	where, whereArgs := reviewFilterWhere(filter)
+	args = append(args, whereArgs...)
+	orderBy := order.orderBy
+	var orderArgs []any
+
+	if len(filter.PreferredLanguages) == 0 {
+		if filter.After != nil {
+			where += " AND " + order.after
+			args = append(args, order.args(filter.After)...)
+		}
+	} else {
+		// Reviews in the preferred languages come first, so a cursor resumes within the last page's language rank
+		rank, rankArgs := languageRank(filter.PreferredLanguages)
+		orderBy = rank + " ASC, " + orderBy
+		orderArgs = rankArgs
+		if filter.After != nil {
+			where += " AND (" + rank + " > ? OR (" + rank + " = ? AND " + order.after + "))"
+			args = append(args, rankArgs...)
+			args = append(args, filter.After.LanguageRank)
+			args = append(args, rankArgs...)
+			args = append(args, filter.After.LanguageRank)
+			args = append(args, order.args(filter.After)...)
+		}
+	}
+
+	query := "SELECT " + columns + " FROM reviews WHERE " + where + " ORDER BY " + orderBy + " LIMIT ?"
+	args = append(args, orderArgs...)
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		fmt.Println("Error fetching reviews:", err)
+		fmt.Println("Error listing reviews:", err)
 		return nil, err
 	}
 	defer rows.Close()
 
+	if order.score == "" {
+		return r.scanReviews(rows)
+	}
+
 	var reviews []*models.Review
 	for rows.Next() {
-		review := &models.Review{}
-		if err := rows.Scan(&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.DeletedAt, &review.IsSynced); err != nil {
+		var score string
+		review, err := ScanReview(rows, &score)
+		if err != nil {
 			fmt.Println("Error scanning review:", err)
 			return nil, err
 		}
+		review.RelevanceScore = score
 		reviews = append(reviews, review)
 	}
-
 	if err := rows.Err(); err != nil {
 		fmt.Println("Error with rows:", err)
 		return nil, err
@@ -54,17 +264,29 @@ func (r *ReviewRepositoryImpl) GetAll() ([]*models.Review, error) {
 	return reviews, nil
 }
 
+// Count returns how many reviews match the filter, ignoring the cursor and limit.
+func (r *ReviewRepositoryImpl) Count(filter *models.ReviewFilter) (int64, error) {
+	where, args := reviewFilterWhere(filter)
+
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM reviews WHERE "+where, args...).Scan(&count); err != nil {
+		fmt.Println("Error counting reviews:", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (r *ReviewRepositoryImpl) GetByID(id int64) (*models.Review, error) {
-	query := "SELECT id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, deleted_at, is_synced FROM reviews WHERE id = ? AND deleted_at IS NULL"
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE id = ? AND deleted_at IS NULL"
 	row := r.db.QueryRow(query, id)
 
-	review := &models.Review{}
-	err := row.Scan(&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.DeletedAt, &review.IsSynced)
+	review, err := ScanReview(row)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			fmt.Println("No review found with the given ID")
-			return nil, err
+			return nil, nil
 		} else {
 			fmt.Println("Error scanning review:", err)
 			return nil, err
@@ -74,11 +296,22 @@ func (r *ReviewRepositoryImpl) GetByID(id int64) (*models.Review, error) {
 	return review, nil
 }
 
-func (r *ReviewRepositoryImpl) Create(userId int64, bookingId int64, hotelId int64, comment string, rating int) (*models.Review, error) {
-	query := "INSERT INTO reviews (user_id, booking_id, hotel_id, comment, rating) VALUES (?, ?, ?, ?, ?)"
-	result, err := r.db.Exec(query, userId, bookingId, hotelId, comment, rating)
+// Create stores the review, its category ratings and aspects, its first revision and a review.created event in one transaction.
+func (r *ReviewRepositoryImpl) Create(review *models.Review) (*models.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO reviews (user_id, booking_id, hotel_id, comment, rating, is_verified_stay, moderation_status, moderation_reason, sentiment_score, sentiment_version, language) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(query, review.UserId, review.BookingId, review.HotelId, review.Comment, review.Rating, review.IsVerifiedStay, review.ModerationStatus, review.ModerationReason, review.SentimentScore, review.SentimentVersion, review.Language)
 
 	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, ErrDuplicateEntry
+		}
 		fmt.Println("Error creating review:", err)
 		return nil, err
 	}
@@ -89,23 +322,55 @@ func (r *ReviewRepositoryImpl) Create(userId int64, bookingId int64, hotelId int
 		return nil, rowErr
 	}
 
-	review := &models.Review{
-		Id:        lastInsertID,
-		UserId:    userId,
-		BookingId: bookingId,
-		HotelId:   hotelId,
-		Comment:   comment,
-		Rating:    rating,
-		IsSynced:  false,
+	if err := replaceCategoryRatings(tx, lastInsertID, review.CategoryRatings); err != nil {
+		return nil, err
+	}
+	if err := replaceAspects(tx, lastInsertID, review.Aspects); err != nil {
+		return nil, err
+	}
+	review.Id = lastInsertID
+	if err := insertFingerprints(tx, []*models.Review{review}); err != nil {
+		return nil, err
+	}
+	if review.FraudCheck != nil {
+		if err := insertFraudCheck(tx, lastInsertID, review.FraudCheck); err != nil {
+			return nil, err
+		}
+	}
+	if err := insertRevision(tx, lastInsertID, review.UserId); err != nil {
+		return nil, err
+	}
+	if err := insertReviewEvent(tx, models.EventReviewCreated, lastInsertID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review:", err)
+		return nil, err
+	}
+
+	// Fetch the stored review so timestamps and defaults are filled in
+	created, err := r.GetByID(lastInsertID)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Println("Review created successfully:", review)
-	return review, nil
+	fmt.Println("Review created successfully:", created)
+	return created, nil
 }
 
-func (r *ReviewRepositoryImpl) Update(id int64, comment string, rating int) (*models.Review, error) {
-	query := "UPDATE reviews SET comment = ?, rating = ? WHERE id = ? AND deleted_at IS NULL"
-	result, err := r.db.Exec(query, comment, rating, id)
+// Update stores the review's comment, rating, sentiment and moderation fields and records the result as a new revision
+// by editorId. Category ratings are replaced when review.CategoryRatings is non-nil and left alone otherwise.
+// updated_at is set explicitly because a change to the category ratings alone leaves the reviews row as it was.
+func (r *ReviewRepositoryImpl) Update(review *models.Review, editorId int64) (*models.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE reviews SET comment = ?, rating = ?, sentiment_score = ?, sentiment_version = ?, language = ?, moderation_status = ?, moderation_reason = ?, moderated_by = ?, moderated_at = ?, takedown_requeued = ?, is_synced = FALSE, updated_at = CURRENT_TIMESTAMP, edited_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
+	result, err := tx.Exec(query, review.Comment, review.Rating, review.SentimentScore, review.SentimentVersion, review.Language, review.ModerationStatus, review.ModerationReason, review.ModeratedBy, review.ModeratedAt, review.TakedownRequeued, review.Id)
 
 	if err != nil {
 		fmt.Println("Error updating review:", err)
@@ -122,13 +387,93 @@ func (r *ReviewRepositoryImpl) Update(id int64, comment string, rating int) (*mo
 		return nil, fmt.Errorf("review not found")
 	}
 
+	if review.CategoryRatings != nil {
+		if err := replaceCategoryRatings(tx, review.Id, review.CategoryRatings); err != nil {
+			return nil, err
+		}
+	}
+	if err := replaceAspects(tx, review.Id, review.Aspects); err != nil {
+		return nil, err
+	}
+	if err := replaceFingerprint(tx, review); err != nil {
+		return nil, err
+	}
+	if err := insertRevision(tx, review.Id, editorId); err != nil {
+		return nil, err
+	}
+	if err := insertReviewEvent(tx, models.EventReviewUpdated, review.Id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review update:", err)
+		return nil, err
+	}
+
 	// Fetch the updated review
+	return r.GetByID(review.Id)
+}
+
+// SetModerationStatus records a moderator's decision. The hotel is resynced since only approved reviews count towards its rating.
+// The decision settles the review's open reports and is published as review.updated. It only applies while the review
+// is in one of fromStatuses, and returns ErrConcurrentChange when the review has moved on or was deleted since it was read.
+func (r *ReviewRepositoryImpl) SetModerationStatus(id int64, status string, fromStatuses []string, reason *string, moderatorId int64) (*models.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := []any{status, reason, moderatorId, id}
+	for _, from := range fromStatuses {
+		args = append(args, from)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(fromStatuses)), ", ")
+
+	query := "UPDATE reviews SET moderation_status = ?, moderation_reason = ?, moderated_by = ?, moderated_at = CURRENT_TIMESTAMP, takedown_requeued = FALSE, is_synced = FALSE, report_count = 0, report_weight = 0 WHERE id = ? AND deleted_at IS NULL AND moderation_status IN (" + placeholders + ")"
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		fmt.Println("Error setting moderation status:", err)
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("Error getting rows affected:", err)
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrConcurrentChange
+	}
+
+	_, err = tx.Exec("UPDATE review_reports SET resolved_at = CURRENT_TIMESTAMP, resolved_by = ? WHERE review_id = ? AND resolved_at IS NULL", moderatorId, id)
+	if err != nil {
+		fmt.Println("Error resolving review reports:", err)
+		return nil, err
+	}
+
+	if err := insertReviewEvent(tx, models.EventReviewUpdated, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing moderation decision:", err)
+		return nil, err
+	}
+
 	return r.GetByID(id)
 }
 
+// Delete soft-deletes the review and records a review.deleted event in the same transaction.
 func (r *ReviewRepositoryImpl) Delete(id int64) error {
-	query := "UPDATE reviews SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
-	result, err := r.db.Exec(query, id)
+	tx, err := r.db.Begin()
+	if err != nil {
+		fmt.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE reviews SET deleted_at = CURRENT_TIMESTAMP, is_synced = FALSE WHERE id = ? AND deleted_at IS NULL"
+	result, err := tx.Exec(query, id)
 
 	if err != nil {
 		fmt.Println("Error deleting review:", err)
@@ -144,27 +489,68 @@ func (r *ReviewRepositoryImpl) Delete(id int64) error {
 		fmt.Println("No rows were affected, review not found or already deleted")
 		return fmt.Errorf("review not found")
 	}
+
+	if err := insertReviewEvent(tx, models.EventReviewDeleted, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing review deletion:", err)
+		return err
+	}
 	fmt.Println("Review deleted successfully, rows affected:", rowsAffected)
 	return nil
 }
 
-func (r *ReviewRepositoryImpl) GetByUserId(userId int64) ([]*models.Review, error) {
-	query := "SELECT id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, deleted_at, is_synced FROM reviews WHERE user_id = ? AND deleted_at IS NULL"
-	rows, err := r.db.Query(query, userId)
+func (r *ReviewRepositoryImpl) GetByBookingId(bookingId int64) ([]*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE booking_id = ? AND deleted_at IS NULL"
+	rows, err := r.db.Query(query, bookingId)
 	if err != nil {
-		fmt.Println("Error fetching reviews by user ID:", err)
+		fmt.Println("Error fetching reviews by booking ID:", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reviews []*models.Review
+	return r.scanReviews(rows)
+}
+
+func (r *ReviewRepositoryImpl) GetActiveByBookingAndUser(bookingId int64, userId int64) (*models.Review, error) {
+	query := "SELECT " + ReviewColumns + " FROM reviews WHERE booking_id = ? AND user_id = ? AND deleted_at IS NULL"
+	row := r.db.QueryRow(query, bookingId, userId)
+
+	review, err := ScanReview(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		fmt.Println("Error scanning review:", err)
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// GetUnsyncedHotelIds returns hotels with created, updated or deleted reviews not yet pushed to HotelService, oldest change first.
+// Hotels whose last push failed are left out until their retry time, so they cannot hold up the others.
+func (r *ReviewRepositoryImpl) GetUnsyncedHotelIds(limit int) ([]int64, error) {
+	query := `SELECT r.hotel_id FROM reviews r
+	LEFT JOIN rating_sync_failures f ON f.hotel_id = r.hotel_id
+	WHERE r.is_synced = FALSE AND (f.retry_at IS NULL OR f.retry_at <= NOW())
+	GROUP BY r.hotel_id ORDER BY MIN(r.updated_at) LIMIT ?`
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		fmt.Println("Error fetching unsynced hotel IDs:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hotelIds []int64
 	for rows.Next() {
-		review := &models.Review{}
-		if err := rows.Scan(&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.DeletedAt, &review.IsSynced); err != nil {
-			fmt.Println("Error scanning review:", err)
+		var hotelId int64
+		if err := rows.Scan(&hotelId); err != nil {
+			fmt.Println("Error scanning hotel ID:", err)
 			return nil, err
 		}
-		reviews = append(reviews, review)
+		hotelIds = append(hotelIds, hotelId)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -172,49 +558,165 @@ func (r *ReviewRepositoryImpl) GetByUserId(userId int64) ([]*models.Review, erro
 		return nil, err
 	}
 
-	return reviews, nil
+	return hotelIds, nil
 }
 
-func (r *ReviewRepositoryImpl) GetByHotelId(hotelId int64) ([]*models.Review, error) {
-	query := "SELECT id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, deleted_at, is_synced FROM reviews WHERE hotel_id = ? AND deleted_at IS NULL"
-	rows, err := r.db.Query(query, hotelId)
+// GetHotelRating aggregates a hotel's active, approved reviews. The hotel's unsynced reviews are read from the same
+// snapshot, so MarkHotelSynced can mark exactly the review versions the aggregate covers.
+func (r *ReviewRepositoryImpl) GetHotelRating(hotelId int64) (*models.HotelRating, error) {
+	// Every read of a repeatable-read transaction sees the snapshot taken by its first read
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
 	if err != nil {
-		fmt.Println("Error fetching reviews by hotel ID:", err)
+		fmt.Println("Error starting transaction:", err)
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var reviews []*models.Review
+	rating := &models.HotelRating{HotelId: hotelId, Unsynced: []models.ReviewVersion{}}
+	rows, err := tx.Query("SELECT id, updated_at FROM reviews WHERE hotel_id = ? AND is_synced = FALSE", hotelId)
+	if err != nil {
+		fmt.Println("Error fetching unsynced reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
 	for rows.Next() {
-		review := &models.Review{}
-		if err := rows.Scan(&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.DeletedAt, &review.IsSynced); err != nil {
-			fmt.Println("Error scanning review:", err)
+		var version models.ReviewVersion
+		if err := rows.Scan(&version.Id, &version.UpdatedAt); err != nil {
+			fmt.Println("Error scanning unsynced review:", err)
 			return nil, err
 		}
-		reviews = append(reviews, review)
+		rating.Unsynced = append(rating.Unsynced, version)
 	}
-
 	if err := rows.Err(); err != nil {
 		fmt.Println("Error with rows:", err)
 		return nil, err
 	}
 
-	return reviews, nil
+	query := "SELECT COUNT(*), COALESCE(AVG(rating), 0) FROM reviews WHERE hotel_id = ? AND deleted_at IS NULL AND moderation_status = 'approved'"
+	if err := tx.QueryRow(query, hotelId).Scan(&rating.Count, &rating.Average); err != nil {
+		fmt.Println("Error aggregating hotel rating:", err)
+		return nil, err
+	}
+
+	rating.Categories, err = getHotelCategoryAggregates(tx, hotelId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing transaction:", err)
+		return nil, err
+	}
+	return rating, nil
 }
 
-func (r *ReviewRepositoryImpl) GetByBookingId(bookingId int64) ([]*models.Review, error) {
-	query := "SELECT id, user_id, booking_id, hotel_id, comment, rating, created_at, updated_at, deleted_at, is_synced FROM reviews WHERE booking_id = ? AND deleted_at IS NULL"
-	rows, err := r.db.Query(query, bookingId)
+// MarkHotelSynced flags the given review versions as synced and clears the hotel's failed push, if any.
+// A review changed since it was read no longer matches its version, so it stays unsynced for the next run.
+func (r *ReviewRepositoryImpl) MarkHotelSynced(hotelId int64, reviews []models.ReviewVersion) (int64, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		fmt.Println("Error fetching reviews by booking ID:", err)
+		fmt.Println("Error starting transaction:", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var marked int64
+	for start := 0; start < len(reviews); start += markSyncedBatchSize {
+		batch := reviews[start:min(start+markSyncedBatchSize, len(reviews))]
+		args := []any{hotelId}
+		for _, review := range batch {
+			args = append(args, review.Id, review.UpdatedAt)
+		}
+		pairs := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(batch)), ", ")
+
+		// updated_at is assigned to itself so flipping the flag does not count as an edit
+		query := "UPDATE reviews SET is_synced = TRUE, updated_at = updated_at WHERE hotel_id = ? AND is_synced = FALSE AND (id, updated_at) IN (" + pairs + ")"
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			fmt.Println("Error marking reviews as synced:", err)
+			return 0, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			fmt.Println("Error getting rows affected:", err)
+			return 0, err
+		}
+		marked += rowsAffected
+	}
+
+	if _, err := tx.Exec("DELETE FROM rating_sync_failures WHERE hotel_id = ?", hotelId); err != nil {
+		fmt.Println("Error clearing rating sync failure:", err)
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Error committing transaction:", err)
+		return 0, err
+	}
+	return marked, nil
+}
+
+// RecordHotelSyncFailure holds the hotel back for retryDelay, doubled for every consecutive failure up to maxRetryDelay.
+func (r *ReviewRepositoryImpl) RecordHotelSyncFailure(hotelId int64, syncErr error, retryDelay time.Duration, maxRetryDelay time.Duration) error {
+	message := syncErr.Error()
+	if len(message) > 1000 {
+		message = message[:1000]
+	}
+
+	// attempts already holds the new count when retry_at is computed, since assignments apply left to right
+	query := `INSERT INTO rating_sync_failures (hotel_id, attempts, last_error, retry_at) VALUES (?, 1, ?, NOW() + INTERVAL ? SECOND)
+	ON DUPLICATE KEY UPDATE attempts = attempts + 1, last_error = VALUES(last_error), retry_at = NOW() + INTERVAL LEAST(? * POW(2, attempts - 1), ?) SECOND`
+	delay, maxDelay := int64(retryDelay.Seconds()), int64(maxRetryDelay.Seconds())
+	if _, err := r.db.Exec(query, hotelId, message, min(delay, maxDelay), delay, maxDelay); err != nil {
+		fmt.Println("Error recording rating sync failure:", err)
+		return err
+	}
+	return nil
+}
+
+// GetHotelRatingSummary computes every figure of the rating summary in one pass over the hotel's rows on idx_hotel_id.
+func (r *ReviewRepositoryImpl) GetHotelRatingSummary(hotelId int64) (*models.HotelRatingSummary, error) {
+	query := `SELECT
+		COUNT(*),
+		COALESCE(SUM(rating), 0),
+		COALESCE(SUM(rating = 1), 0),
+		COALESCE(SUM(rating = 2), 0),
+		COALESCE(SUM(rating = 3), 0),
+		COALESCE(SUM(rating = 4), 0),
+		COALESCE(SUM(rating = 5), 0),
+		COUNT(CASE WHEN created_at >= NOW() - INTERVAL 30 DAY THEN 1 END),
+		COALESCE(SUM(CASE WHEN created_at >= NOW() - INTERVAL 30 DAY THEN rating END), 0),
+		COUNT(CASE WHEN created_at >= NOW() - INTERVAL 90 DAY THEN 1 END),
+		COALESCE(SUM(CASE WHEN created_at >= NOW() - INTERVAL 90 DAY THEN rating END), 0)
+	FROM reviews FORCE INDEX (idx_hotel_id)
+	WHERE hotel_id = ? AND deleted_at IS NULL AND moderation_status = 'approved'`
+	row := r.db.QueryRow(query, hotelId)
+
+	summary := &models.HotelRatingSummary{HotelId: hotelId}
+	h := &summary.Histogram
+	err := row.Scan(&summary.Count, &summary.Sum, &h[0], &h[1], &h[2], &h[3], &h[4], &summary.Count30d, &summary.Sum30d, &summary.Count90d, &summary.Sum90d)
+	if err != nil {
+		fmt.Println("Error aggregating hotel rating summary:", err)
 		return nil, err
 	}
-	defer rows.Close()
 
+	summary.Categories, err = getHotelCategoryAggregates(r.db, hotelId)
+	if err != nil {
+		return nil, err
+	}
+	summary.Languages, err = r.getHotelLanguageCounts(hotelId)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (r *ReviewRepositoryImpl) scanReviews(rows *sql.Rows) ([]*models.Review, error) {
 	var reviews []*models.Review
 	for rows.Next() {
-		review := &models.Review{}
-		if err := rows.Scan(&review.Id, &review.UserId, &review.BookingId, &review.HotelId, &review.Comment, &review.Rating, &review.CreatedAt, &review.UpdatedAt, &review.DeletedAt, &review.IsSynced); err != nil {
+		review, err := ScanReview(rows)
+		if err != nil {
 			fmt.Println("Error scanning review:", err)
 			return nil, err
 		}
@@ -0,0 +1,41 @@
+package dto
+
+type RatingSummaryResponseDTO struct {
+	HotelId       int64            `json:"hotel_id"`
+	Count         int64            `json:"count"`
+	Mean          *float64         `json:"mean"`
+	Histogram     map[string]int64 `json:"histogram"`
+	BayesianScore float64          `json:"bayesian_score"`
+	PriorMean     float64          `json:"prior_mean"`
+	PriorWeight   float64          `json:"prior_weight"`
+	Last30Days    RatingStatsDTO   `json:"last_30_days"`
+	Last90Days    RatingStatsDTO   `json:"last_90_days"`
+	// Categories has an entry for every category; Mean is null for categories nobody rated
+	Categories map[string]RatingStatsDTO `json:"categories"`
+	// Languages counts the reviews per language code ("und" when undetermined)
+	Languages map[string]int64 `json:"languages"`
+}
+
+type RatingStatsDTO struct {
+	Count int64    `json:"count"`
+	Mean  *float64 `json:"mean"`
+}
+
+type AspectSummaryResponseDTO struct {
+	HotelId int64 `json:"hotel_id"`
+	// AnalyzedCount is the number of reviews analyzed for sentiment, the base of every mention rate
+	AnalyzedCount int64              `json:"analyzed_count"`
+	Aspects       []AspectSummaryDTO `json:"aspects"`
+}
+
+type AspectSummaryDTO struct {
+	Aspect        string   `json:"aspect"`
+	Mentions      int64    `json:"mentions"`
+	MentionRate   float64  `json:"mention_rate"` // share of analyzed reviews mentioning the aspect, 0 to 1
+	Positive      int64    `json:"positive"`
+	Neutral       int64    `json:"neutral"`
+	Negative      int64    `json:"negative"`
+	MeanSentiment *float64 `json:"mean_sentiment"`
+	Tone          string   `json:"tone"`    // mostly positive, mostly negative, mostly neutral or mixed
+	Summary       string   `json:"summary"` // e.g. "mentioned noise in 12% of reviews, mostly negative"
+}
@@ -1,27 +1,158 @@
 package dto
 
+import "ReviewService/models"
+
+// CreateReviewRequestDTO carries no user ID: the author is always the authenticated caller.
 type CreateReviewRequestDTO struct {
-	UserId    int64  `json:"user_id" validate:"required"`
 	BookingId int64  `json:"booking_id" validate:"required"`
 	HotelId   int64  `json:"hotel_id" validate:"required"`
 	Comment   string `json:"comment" validate:"required,min=1,max=1000"`
 	Rating    int    `json:"rating" validate:"required,min=1,max=5"`
+	// CategoryRatings is optional, as is each category within it
+	CategoryRatings *CategoryRatingsDTO `json:"category_ratings"`
 }
 
+// UpdateReviewRequestDTO replaces the category ratings when category_ratings is present and keeps them when it is omitted.
 type UpdateReviewRequestDTO struct {
-	Comment string `json:"comment" validate:"required,min=1,max=1000"`
-	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment         string              `json:"comment" validate:"required,min=1,max=1000"`
+	Rating          int                 `json:"rating" validate:"required,min=1,max=5"`
+	CategoryRatings *CategoryRatingsDTO `json:"category_ratings"`
+}
+
+type CategoryRatingsDTO struct {
+	Cleanliness   *int `json:"cleanliness" validate:"omitempty,min=1,max=5"`
+	Accuracy      *int `json:"accuracy" validate:"omitempty,min=1,max=5"`
+	CheckIn       *int `json:"check_in" validate:"omitempty,min=1,max=5"`
+	Communication *int `json:"communication" validate:"omitempty,min=1,max=5"`
+	Location      *int `json:"location" validate:"omitempty,min=1,max=5"`
+	Value         *int `json:"value" validate:"omitempty,min=1,max=5"`
+}
+
+// ToMap keys the given ratings by category, leaving out the ones not rated. A nil DTO gives a nil map.
+func (c *CategoryRatingsDTO) ToMap() map[string]int {
+	if c == nil {
+		return nil
+	}
+
+	ratings := map[string]int{}
+	for category, rating := range map[string]*int{
+		models.CategoryCleanliness:   c.Cleanliness,
+		models.CategoryAccuracy:      c.Accuracy,
+		models.CategoryCheckIn:       c.CheckIn,
+		models.CategoryCommunication: c.Communication,
+		models.CategoryLocation:      c.Location,
+		models.CategoryValue:         c.Value,
+	} {
+		if rating != nil {
+			ratings[category] = *rating
+		}
+	}
+	return ratings
 }
 
 type ReviewResponseDTO struct {
-	Id        int64   `json:"id"`
-	UserId    int64   `json:"user_id"`
-	BookingId int64   `json:"booking_id"`
-	HotelId   int64   `json:"hotel_id"`
-	Comment   string  `json:"comment"`
-	Rating    int     `json:"rating"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-	DeletedAt *string `json:"deleted_at,omitempty"`
-	IsSynced  bool    `json:"is_synced"`
+	Id             int64   `json:"id"`
+	UserId         int64   `json:"user_id"`
+	BookingId      int64   `json:"booking_id"`
+	HotelId        int64   `json:"hotel_id"`
+	Comment        string  `json:"comment"`
+	Rating         int     `json:"rating"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+	DeletedAt      *string `json:"deleted_at,omitempty"`
+	IsSynced       bool    `json:"is_synced"`
+	IsVerifiedStay bool    `json:"is_verified_stay"`
+}
+
+// ReviewListQuery holds the pagination, sorting and filter parameters of a review listing.
+type ReviewListQuery struct {
+	Limit        int
+	Cursor       string
+	Sort         string
+	MinRating    int
+	MaxRating    int
+	From         string // YYYY-MM-DD or RFC 3339
+	To           string // YYYY-MM-DD (whole day included) or RFC 3339
+	HasComment   *bool
+	IncludeTotal bool
+	Lang         string // ISO 639-1 code, or "und"
+	// AcceptLanguage is the request's Accept-Language header; public listings show reviews in those languages first
+	AcceptLanguage string
+}
+
+type ReviewPageDTO struct {
+	Reviews    []*models.Review `json:"reviews"`
+	NextCursor *string          `json:"next_cursor"`
+	TotalCount *int64           `json:"total_count,omitempty"`
+}
+
+type ReviewSearchQuery struct {
+	Q       string
+	HotelId string
+	Limit   int
+}
+
+// ReviewSearchHitDTO is a search result. Snippet is HTML-escaped with matches wrapped in <mark> tags.
+type ReviewSearchHitDTO struct {
+	Review  *models.Review `json:"review"`
+	Score   float64        `json:"score"`
+	Snippet string         `json:"snippet"`
+}
+
+// ReviewRevisionDTO is one entry of a review's edit history. Changed lists the fields that differ from the
+// previous revision and is empty for the first one.
+type ReviewRevisionDTO struct {
+	Revision        int            `json:"revision"`
+	EditorId        int64          `json:"editor_id"`
+	Comment         string         `json:"comment"`
+	Rating          int            `json:"rating"`
+	CategoryRatings map[string]int `json:"category_ratings"`
+	Changed         []string       `json:"changed"`
+	CreatedAt       string         `json:"created_at"`
+}
+
+// FraudCheckDTO is the fraud score a new review was given and the signals behind it.
+type FraudCheckDTO struct {
+	Score     float64                        `json:"score"`
+	Decision  string                         `json:"decision"`
+	Signals   map[string]*models.FraudSignal `json:"signals"`
+	CreatedAt string                         `json:"created_at"`
+}
+
+// ModerateReviewRequestDTO carries the moderator's reason, which is required to reject or hide a review.
+type ModerateReviewRequestDTO struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type ReviewResponseRequestDTO struct {
+	Body string `json:"body" validate:"required,min=1,max=1000"`
+}
+
+type ReviewVoteRequestDTO struct {
+	Helpful *bool `json:"helpful" validate:"required"`
+}
+
+type ReviewReportRequestDTO struct {
+	Reason  string `json:"reason" validate:"required,oneof=spam offensive irrelevant conflict_of_interest"`
+	Details string `json:"details" validate:"max=500"`
+}
+
+// ReportedReviewDTO is a review in the reported list with its open reports counted by reason.
+type ReportedReviewDTO struct {
+	Review  *models.Review   `json:"review"`
+	Reasons map[string]int64 `json:"reasons"`
+}
+
+type ReportedReviewPageDTO struct {
+	Reviews    []*ReportedReviewDTO `json:"reviews"`
+	NextCursor *string              `json:"next_cursor"`
+	TotalCount *int64               `json:"total_count,omitempty"`
+}
+
+// ReviewVoteResponseDTO reports the caller's vote after the toggle; Vote is null when it was removed.
+type ReviewVoteResponseDTO struct {
+	ReviewId        int64 `json:"review_id"`
+	Vote            *bool `json:"vote"`
+	HelpfulCount    int   `json:"helpful_count"`
+	NotHelpfulCount int   `json:"not_helpful_count"`
 }
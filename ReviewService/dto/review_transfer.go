@@ -0,0 +1,57 @@
+package dto
+
+// ImportReviewRowDTO is one review of an import file. It is validated with the CreateReviewRequestDTO rules,
+// plus the author, which an import names explicitly.
+type ImportReviewRowDTO struct {
+	UserId int64 `json:"user_id" validate:"required"`
+	CreateReviewRequestDTO
+	CreatedAt    string `json:"created_at"` // optional; RFC 3339, YYYY-MM-DD HH:MM:SS (UTC) or YYYY-MM-DD
+	VerifiedStay bool   `json:"verified_stay"`
+}
+
+// ReviewImportReportDTO sums up an import. On a dry run, Imported counts the rows that would have been imported.
+type ReviewImportReportDTO struct {
+	DryRun          bool                 `json:"dry_run"`
+	Rows            int                  `json:"rows"`
+	Imported        int                  `json:"imported"`
+	Failed          int                  `json:"failed"`
+	Errors          []*ImportRowErrorDTO `json:"errors"`
+	ErrorsTruncated bool                 `json:"errors_truncated"`
+}
+
+// ImportRowErrorDTO is one problem with one row. Line is the row's line in the file, counting a CSV header.
+type ImportRowErrorDTO struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ReviewExportQuery selects the reviews to export. Empty fields do not filter.
+type ReviewExportQuery struct {
+	Format  string // csv or ndjson
+	HotelId string
+	UserId  string
+	Status  string
+	// List carries the date, rating and comment filters; its sort, cursor and limit are ignored
+	List *ReviewListQuery
+}
+
+// ReviewExportRowDTO is one exported review. CSV exports flatten CategoryRatings into one column per category.
+type ReviewExportRowDTO struct {
+	Id               int64          `json:"id"`
+	UserId           int64          `json:"user_id"`
+	BookingId        int64          `json:"booking_id"`
+	HotelId          int64          `json:"hotel_id"`
+	Rating           int            `json:"rating"`
+	Comment          string         `json:"comment"`
+	CategoryRatings  map[string]int `json:"category_ratings"`
+	ModerationStatus string         `json:"moderation_status"`
+	VerifiedStay     bool           `json:"verified_stay"`
+	SentimentScore   *float64       `json:"sentiment_score"`
+	Language         *string        `json:"language"`
+	HelpfulCount     int            `json:"helpful_count"`
+	NotHelpfulCount  int            `json:"not_helpful_count"`
+	CreatedAt        string         `json:"created_at"`
+	UpdatedAt        string         `json:"updated_at"`
+	EditedAt         *string        `json:"edited_at"`
+}
@@ -0,0 +1,17 @@
+package events
+
+import "context"
+
+// Message is an event as handed to a broker. Id is unique per event, so a broker can drop a message
+// the outbox relay delivers twice.
+type Message struct {
+	Id      string
+	Type    string
+	Payload []byte // JSON
+}
+
+// Broker publishes review events to other services. Delivery is at least once: the relay retries until
+// Publish succeeds, so implementations should use Message.Id to drop duplicates.
+type Broker interface {
+	Publish(ctx context.Context, message *Message) error
+}
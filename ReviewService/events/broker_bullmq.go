@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type BullMQConfig struct {
+	Addr     string // host:port
+	Password string
+	DB       int
+	Queue    string
+	Prefix   string // BullMQ key prefix, "bull" unless the consumers were configured otherwise
+	Attempts int    // how often a BullMQ worker may retry a failed job
+	Timeout  time.Duration
+}
+
+// NewRedisClient connects to the Redis that holds the BullMQ queues.
+func NewRedisClient(config BullMQConfig) *redis.Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Second
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:         config.Addr,
+		Password:     config.Password,
+		DB:           config.DB,
+		DialTimeout:  config.Timeout,
+		ReadTimeout:  config.Timeout,
+		WriteTimeout: config.Timeout,
+	})
+}
+
+// addJobScript stores a job the way BullMQ's Queue.add does for a plain (not delayed, not prioritised) job:
+// the job hash, its id on the wait list, the marker that wakes blocked workers, and a "waiting" event.
+// A job whose id already exists is left alone; that is how a redelivered message is dropped.
+//
+// KEYS: job hash, wait list, paused list, meta hash, marker, events stream
+// ARGV: job id, name, data, opts, timestamp
+var addJobScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+  return 0
+end
+redis.call("HSET", KEYS[1], "name", ARGV[2], "data", ARGV[3], "opts", ARGV[4], "timestamp", ARGV[5], "delay", "0", "priority", "0")
+if redis.call("HEXISTS", KEYS[4], "paused") == 1 then
+  redis.call("LPUSH", KEYS[3], ARGV[1])
+else
+  redis.call("LPUSH", KEYS[2], ARGV[1])
+  redis.call("ZADD", KEYS[5], 0, "0")
+end
+redis.call("XADD", KEYS[6], "MAXLEN", "~", "10000", "*", "event", "waiting", "jobId", ARGV[1], "name", ARGV[2])
+return 1
+`)
+
+// BullMQQueue adds jobs to one BullMQ queue, for consumption by a BullMQ (v5) Worker in the Node services.
+type BullMQQueue struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewBullMQQueue(client redis.Scripter, prefix string, queue string) *BullMQQueue {
+	if prefix == "" {
+		prefix = "bull"
+	}
+	return &BullMQQueue{client: client, prefix: prefix + ":" + queue + ":"}
+}
+
+// Add stores a job under jobId. It returns false when a job with that id already exists.
+func (q *BullMQQueue) Add(ctx context.Context, jobId string, name string, data []byte, opts map[string]any) (bool, error) {
+	withId := map[string]any{"jobId": jobId}
+	for k, v := range opts {
+		withId[k] = v
+	}
+	encodedOpts, err := json.Marshal(withId)
+	if err != nil {
+		return false, err
+	}
+
+	keys := []string{q.prefix + jobId, q.prefix + "wait", q.prefix + "paused", q.prefix + "meta", q.prefix + "marker", q.prefix + "events"}
+	added, err := addJobScript.Run(ctx, q.client, keys, jobId, name, string(data), string(encodedOpts), strconv.FormatInt(time.Now().UnixMilli(), 10)).Int()
+	if err != nil {
+		return false, err
+	}
+	return added == 1, nil
+}
+
+// BullMQBroker adds each message as a job to a BullMQ queue in Redis, named after the event type,
+// so the Node services can consume review events with a regular BullMQ Worker.
+type BullMQBroker struct {
+	client   *redis.Client
+	queue    *BullMQQueue
+	attempts int
+}
+
+func NewBullMQBroker(config BullMQConfig) *BullMQBroker {
+	return newBullMQBroker(NewRedisClient(config), config)
+}
+
+func newBullMQBroker(client *redis.Client, config BullMQConfig) *BullMQBroker {
+	if config.Attempts <= 0 {
+		config.Attempts = 3
+	}
+	return &BullMQBroker{
+		client:   client,
+		queue:    NewBullMQQueue(client, config.Prefix, config.Queue),
+		attempts: config.Attempts,
+	}
+}
+
+// Publish uses the message id as the job id, so a message delivered twice is added once.
+func (b *BullMQBroker) Publish(ctx context.Context, message *Message) error {
+	_, err := b.queue.Add(ctx, message.Id, message.Type, message.Payload, map[string]any{
+		"attempts": b.attempts,
+		"backoff":  map[string]any{"type": "exponential", "delay": 1000},
+	})
+	return err
+}
+
+func (b *BullMQBroker) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// workerJob is a job as a BullMQ v5 Worker reads it back in Job.fromJSON.
+type workerJob struct {
+	Id        string
+	Name      string
+	Data      map[string]any
+	Opts      map[string]any
+	Timestamp int64
+	Delay     int64
+	Priority  int64
+}
+
+func newTestBroker(t *testing.T) (*BullMQBroker, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	broker := newBullMQBroker(client, BullMQConfig{Queue: "queue-review-events", Attempts: 5})
+	t.Cleanup(func() { broker.Close() })
+	return broker, server
+}
+
+// takeJob takes the oldest job from the right of the wait list and decodes its hash, the way a Worker does.
+func takeJob(t *testing.T, server *miniredis.Miniredis, queue string) *workerJob {
+	t.Helper()
+	prefix := "bull:" + queue + ":"
+	id, err := server.Pop(prefix + "wait")
+	if err != nil {
+		t.Fatalf("no job waiting: %v", err)
+	}
+	fields, err := server.HKeys(prefix + id)
+	if err != nil {
+		t.Fatalf("job %s has no hash: %v", id, err)
+	}
+	raw := map[string]string{}
+	for _, field := range fields {
+		raw[field] = server.HGet(prefix+id, field)
+	}
+
+	job := &workerJob{Id: id, Name: raw["name"]}
+	if err := json.Unmarshal([]byte(raw["data"]), &job.Data); err != nil {
+		t.Fatalf("job data %q is not JSON: %v", raw["data"], err)
+	}
+	if err := json.Unmarshal([]byte(raw["opts"]), &job.Opts); err != nil {
+		t.Fatalf("job opts %q are not JSON: %v", raw["opts"], err)
+	}
+	for field, dest := range map[string]*int64{"timestamp": &job.Timestamp, "delay": &job.Delay, "priority": &job.Priority} {
+		if *dest, err = strconv.ParseInt(raw[field], 10, 64); err != nil {
+			t.Fatalf("job %s %q is not a number", field, raw[field])
+		}
+	}
+	return job
+}
+
+func TestBullMQBrokerAddsWorkerReadableJob(t *testing.T) {
+	broker, server := newTestBroker(t)
+
+	message := &Message{Id: "9b2f6c1e-event", Type: "review.created", Payload: []byte(`{"reviewId":7,"hotelId":3}`)}
+	if err := broker.Publish(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+
+	job := takeJob(t, server, "queue-review-events")
+	if job.Id != message.Id || job.Name != "review.created" {
+		t.Errorf("job %s named %s, want %s named review.created", job.Id, job.Name, message.Id)
+	}
+	if job.Data["reviewId"] != float64(7) || job.Data["hotelId"] != float64(3) {
+		t.Errorf("job data %v", job.Data)
+	}
+	if job.Opts["jobId"] != message.Id || job.Opts["attempts"] != float64(5) {
+		t.Errorf("job opts %v, want the job id and 5 attempts", job.Opts)
+	}
+	if backoff, _ := job.Opts["backoff"].(map[string]any); backoff["type"] != "exponential" {
+		t.Errorf("job backoff %v, want exponential", job.Opts["backoff"])
+	}
+	if job.Timestamp == 0 || job.Delay != 0 || job.Priority != 0 {
+		t.Errorf("job timestamp %d delay %d priority %d", job.Timestamp, job.Delay, job.Priority)
+	}
+
+	// Blocked workers are woken through the marker, and QueueEvents listeners through the stream
+	if members, err := server.ZMembers("bull:queue-review-events:marker"); err != nil || len(members) != 1 || members[0] != "0" {
+		t.Errorf("marker %v %v, want the non-delayed marker", members, err)
+	}
+	stream, err := server.Stream("bull:queue-review-events:events")
+	if err != nil || len(stream) != 1 || stream[0].Values[1] != "waiting" || stream[0].Values[3] != message.Id {
+		t.Errorf("events stream %v %v, want one waiting event for the job", stream, err)
+	}
+}
+
+func TestBullMQBrokerDropsRedeliveredMessage(t *testing.T) {
+	broker, server := newTestBroker(t)
+
+	message := &Message{Id: "event-1", Type: "review.updated", Payload: []byte(`{"reviewId":1}`)}
+	for i := 0; i < 3; i++ {
+		if err := broker.Publish(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if waiting, _ := server.List("bull:queue-review-events:wait"); len(waiting) != 1 {
+		t.Errorf("wait list %v, want the job once", waiting)
+	}
+}
+
+func TestBullMQBrokerRespectsPausedQueue(t *testing.T) {
+	broker, server := newTestBroker(t)
+	server.HSet("bull:queue-review-events:meta", "paused", "1")
+
+	if err := broker.Publish(context.Background(), &Message{Id: "event-1", Type: "review.deleted", Payload: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if paused, _ := server.List("bull:queue-review-events:paused"); len(paused) != 1 {
+		t.Errorf("paused list %v, want the job", paused)
+	}
+	if server.Exists("bull:queue-review-events:wait") || server.Exists("bull:queue-review-events:marker") {
+		t.Error("a job on a paused queue was made available to workers")
+	}
+}
+
+func TestBullMQBrokerReportsRedisFailure(t *testing.T) {
+	broker, server := newTestBroker(t)
+	server.Close()
+
+	if err := broker.Publish(context.Background(), &Message{Id: "event-1", Type: "review.created", Payload: []byte(`{}`)}); err == nil {
+		t.Error("expected an error with Redis down")
+	}
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryBroker keeps published messages in memory, for tests and local development.
+// A message whose Id was already published is dropped.
+type InMemoryBroker struct {
+	mu       sync.RWMutex
+	seen     map[string]bool
+	messages []*Message
+}
+
+func NewInMemoryBroker() *InMemoryBroker {
+	return &InMemoryBroker{
+		seen: map[string]bool{},
+	}
+}
+
+func (b *InMemoryBroker) Publish(ctx context.Context, message *Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.seen[message.Id] {
+		return nil
+	}
+	b.seen[message.Id] = true
+	copied := *message
+	b.messages = append(b.messages, &copied)
+	return nil
+}
+
+// Messages returns the distinct messages published so far, in publish order.
+func (b *InMemoryBroker) Messages() []*Message {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]*Message(nil), b.messages...)
+}
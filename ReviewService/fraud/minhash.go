@@ -0,0 +1,122 @@
+package fraud
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// Fingerprint parameters. Changing any of them makes stored fingerprints incomparable with new ones,
+// so the fingerprint tables must be emptied and backfilled again.
+const (
+	ShingleSize   = 3  // words per shingle
+	SignatureSize = 64 // MinHash functions
+	BandRows      = 4  // signature values per LSH band; 16 bands of 4 catch most pairs above 0.5 similarity
+	MinShingles   = 6  // shorter comments are too generic to tell a copy from a coincidence
+)
+
+// permutationSeeds derive the MinHash functions. They are fixed so signatures stay comparable across restarts.
+var permutationSeeds = func() [SignatureSize]uint64 {
+	var seeds [SignatureSize]uint64
+	for i := range seeds {
+		seeds[i] = mix64(uint64(i+1) * 0x9e3779b97f4a7c15)
+	}
+	return seeds
+}()
+
+// Signature computes the MinHash signature of a text's word shingles. It returns false when the text has fewer than
+// MinShingles distinct shingles. Case, punctuation and spacing are ignored.
+func Signature(text string) ([]uint32, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < ShingleSize {
+		return nil, false
+	}
+
+	shingles := map[uint64]bool{}
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+ShingleSize], " ")))
+		shingles[h.Sum64()] = true
+	}
+	if len(shingles) < MinShingles {
+		return nil, false
+	}
+
+	signature := make([]uint32, SignatureSize)
+	for i := range signature {
+		signature[i] = ^uint32(0)
+	}
+	for shingle := range shingles {
+		for i, seed := range permutationSeeds {
+			if v := uint32(mix64(shingle^seed) >> 32); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature, true
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind two signatures.
+func Similarity(a, b []uint32) float64 {
+	if len(a) != SignatureSize || len(b) != SignatureSize {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / SignatureSize
+}
+
+// Bands hashes each band of a signature into a key. Two signatures sharing any key are candidate near-duplicates.
+// The band index is part of the key, so equal values in different bands do not collide.
+func Bands(signature []uint32) []int64 {
+	keys := make([]int64, 0, SignatureSize/BandRows)
+	buf := make([]byte, 4)
+	for band := 0; band*BandRows < len(signature); band++ {
+		h := fnv.New64a()
+		h.Write([]byte{byte(band)})
+		for _, v := range signature[band*BandRows : (band+1)*BandRows] {
+			binary.BigEndian.PutUint32(buf, v)
+			h.Write(buf)
+		}
+		keys = append(keys, int64(h.Sum64()))
+	}
+	return keys
+}
+
+// Encode packs a signature for storage.
+func Encode(signature []uint32) []byte {
+	encoded := make([]byte, 4*len(signature))
+	for i, v := range signature {
+		binary.BigEndian.PutUint32(encoded[4*i:], v)
+	}
+	return encoded
+}
+
+// Decode unpacks a stored signature. It returns nil for data of the wrong length.
+func Decode(encoded []byte) []uint32 {
+	if len(encoded) != 4*SignatureSize {
+		return nil
+	}
+	signature := make([]uint32, SignatureSize)
+	for i := range signature {
+		signature[i] = binary.BigEndian.Uint32(encoded[4*i:])
+	}
+	return signature
+}
+
+// mix64 is the splitmix64 finalizer, used to turn one shingle hash into many independent ones.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
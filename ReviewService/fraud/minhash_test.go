@@ -0,0 +1,89 @@
+package fraud
+
+import (
+	"slices"
+	"testing"
+)
+
+const original = "The hotel was spotless and the staff at the front desk went out of their way to help us with our late check-in"
+
+func mustSignature(t *testing.T, text string) []uint32 {
+	t.Helper()
+	signature, ok := Signature(text)
+	if !ok {
+		t.Fatalf("no signature for %q", text)
+	}
+	return signature
+}
+
+func sharesBand(a, b []uint32) bool {
+	bands := Bands(b)
+	for _, key := range Bands(a) {
+		if slices.Contains(bands, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSignatureSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		other     string
+		min, max  float64
+		wantBands bool
+	}{
+		{"identical", original, 1, 1, true},
+		{"case, punctuation and spacing", "THE HOTEL WAS SPOTLESS, and the staff at the front desk went out of their way -- to help us with our late check in!", 1, 1, true},
+		{"near-duplicate", "The hotel was spotless and the staff at the reception went out of their way to help us with our late check-in", 0.5, 0.95, true},
+		{"unrelated", "Breakfast was cold every morning and the pool closed early without any notice to guests staying there", 0, 0.1, false},
+	}
+	signature := mustSignature(t, original)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := mustSignature(t, tt.other)
+			similarity := Similarity(signature, other)
+			if similarity < tt.min || similarity > tt.max {
+				t.Errorf("similarity %v, want between %v and %v", similarity, tt.min, tt.max)
+			}
+			if got := sharesBand(signature, other); got != tt.wantBands {
+				t.Errorf("share a band: %v, want %v", got, tt.wantBands)
+			}
+		})
+	}
+}
+
+func TestSignatureTooShort(t *testing.T) {
+	for _, text := range []string{"", "Great stay", "Great stay, would come back", "great great great great great great great great great"} {
+		if _, ok := Signature(text); ok {
+			t.Errorf("%q has a signature, want it too short to compare", text)
+		}
+	}
+}
+
+func TestBandsAreKeyedByPosition(t *testing.T) {
+	signature := mustSignature(t, original)
+	bands := Bands(signature)
+	if len(bands) != SignatureSize/BandRows {
+		t.Fatalf("%d bands, want %d", len(bands), SignatureSize/BandRows)
+	}
+
+	// The same values in another band hash to another key
+	shifted := slices.Concat(signature[BandRows:], signature[:BandRows])
+	if shiftedBands := Bands(shifted); shiftedBands[0] == bands[1] {
+		t.Error("band keys do not depend on the band index")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	signature := mustSignature(t, original)
+	if decoded := Decode(Encode(signature)); !slices.Equal(decoded, signature) {
+		t.Errorf("decoded %v, want %v", decoded, signature)
+	}
+	if Decode([]byte{1, 2, 3}) != nil {
+		t.Error("decoded data of the wrong length")
+	}
+	if Similarity(signature, nil) != 0 {
+		t.Error("a missing signature is similar")
+	}
+}
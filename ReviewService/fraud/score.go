@@ -0,0 +1,40 @@
+package fraud
+
+import "math"
+
+// Signal names.
+const (
+	SignalBurst         = "burst"          // several reviews from one user in a short window
+	SignalDuplicateText = "duplicate_text" // comment nearly identical to another review's
+	SignalRatingOutlier = "rating_outlier" // rating far from the user's usual ratings
+	SignalQuickReview   = "quick_review"   // review written very soon after the booking was made
+)
+
+// Signals lists every signal in display order.
+var Signals = []string{SignalBurst, SignalDuplicateText, SignalRatingOutlier, SignalQuickReview}
+
+// Combine merges signal scores in [0, 1] into one score in [0, 1]. Each signal is independent evidence that counts
+// for at most its weight: the result is 1 - Π(1 - weight × score). One strong signal with a high weight is enough
+// to reach a threshold, while weak signals add up without any one of them dominating.
+func Combine(scores map[string]float64, weights map[string]float64) float64 {
+	clean := 1.0
+	for signal, score := range scores {
+		clean *= 1 - clamp(weights[signal], 0, 1)*clamp(score, 0, 1)
+	}
+	return math.Round((1-clean)*1000) / 1000
+}
+
+// Ramp maps value to 0 at or below low, 1 at or above high and linearly in between.
+func Ramp(value, low, high float64) float64 {
+	if high <= low {
+		if value >= high {
+			return 1
+		}
+		return 0
+	}
+	return clamp((value-low)/(high-low), 0, 1)
+}
+
+func clamp(value, low, high float64) float64 {
+	return math.Max(low, math.Min(high, value))
+}
@@ -0,0 +1,51 @@
+package fraud
+
+import "testing"
+
+func TestCombine(t *testing.T) {
+	weights := map[string]float64{SignalBurst: 0.6, SignalDuplicateText: 0.8, SignalRatingOutlier: 0.35, SignalQuickReview: 0.35}
+
+	tests := []struct {
+		name   string
+		scores map[string]float64
+		want   float64
+	}{
+		{"no signals", map[string]float64{}, 0},
+		{"all clear", map[string]float64{SignalBurst: 0, SignalDuplicateText: 0}, 0},
+		{"one signal counts for its weight", map[string]float64{SignalDuplicateText: 1}, 0.8},
+		{"partial signal", map[string]float64{SignalBurst: 0.5}, 0.3},
+		{"weak signals add up", map[string]float64{SignalBurst: 0.5, SignalQuickReview: 1}, 0.545},
+		{"scores are clamped", map[string]float64{SignalBurst: 3, SignalQuickReview: -1}, 0.6},
+		{"unweighted signal", map[string]float64{"unknown": 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Combine(tt.scores, weights); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRamp(t *testing.T) {
+	tests := []struct {
+		name             string
+		value, low, high float64
+		want             float64
+	}{
+		{"below", 0.2, 0.5, 0.9, 0},
+		{"at low", 0.5, 0.5, 0.9, 0},
+		{"between", 0.7, 0.5, 0.9, 0.5},
+		{"at high", 0.9, 0.5, 0.9, 1},
+		{"above", 2, 0.5, 0.9, 1},
+		{"step below", 2, 3, 3, 0},
+		{"step at", 3, 3, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ramp(tt.value, tt.low, tt.high); got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
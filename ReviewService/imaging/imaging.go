@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+)
+
+var (
+	ErrUnsupportedType = errors.New("only JPEG and PNG images are supported")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+)
+
+// Processed is an uploaded image after sanitising: re-encoded without metadata, plus a thumbnail.
+type Processed struct {
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+	Thumbnail   []byte
+}
+
+// Sniff detects the content type from the data itself, ignoring whatever the client claimed.
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return "", ErrUnsupportedType
+	}
+	return contentType, nil
+}
+
+// Process decodes an image, applies its EXIF orientation and re-encodes it, which drops EXIF and
+// every other metadata block. maxPixels guards against decompression bombs and is checked before
+// the pixels are decoded. The thumbnail fits within thumbnailSize on its longest side.
+func Process(data []byte, maxPixels int, thumbnailSize int) (*Processed, error) {
+	contentType, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not read image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	// The orientation tag is lost on re-encoding, so bake it into the pixels first
+	if contentType == ContentTypeJPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	full, err := encode(img, contentType)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := encode(Fit(img, thumbnailSize), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Processed{
+		ContentType: contentType,
+		Data:        full,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Thumbnail:   thumbnail,
+	}, nil
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == ContentTypePNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 88})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// halves is a width x height image, red on the left half and blue on the right.
+func halves(width int, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, image.Rect(0, 0, width/2, height), &image.Uniform{color.NRGBA{R: 255, A: 255}}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(width/2, 0, width, height), &image.Uniform{color.NRGBA{B: 255, A: 255}}, image.Point{}, draw.Src)
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encoding PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encoding JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withExif inserts an APP1 Exif segment with the given orientation, followed by marker bytes that stand in for
+// metadata such as GPS coordinates, right after the JPEG's SOI marker.
+func withExif(data []byte, orientation uint16, marker string) []byte {
+	tiff := []byte("II\x2a\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // value padding and no next IFD
+	tiff = append(tiff, marker...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, halves(4, 4), nil); err != nil {
+		t.Fatalf("encoding GIF: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"JPEG", encodeJPEG(t, halves(4, 4)), ContentTypeJPEG},
+		{"PNG", encodePNG(t, halves(4, 4)), ContentTypePNG},
+		{"GIF", gifData.Bytes(), ""},
+		{"HTML", []byte("<html><script>alert(1)</script></html>"), ""},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sniff(tt.data)
+			if tt.want == "" {
+				if !errors.Is(err, ErrUnsupportedType) {
+					t.Errorf("got %q, %v, want ErrUnsupportedType", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessRejectsTooManyPixels(t *testing.T) {
+	data := encodePNG(t, halves(40, 20))
+	if _, err := Process(data, 40*20-1, 10); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("got %v, want ErrTooManyPixels", err)
+	}
+	if _, err := Process(data, 40*20, 10); err != nil {
+		t.Errorf("image at the limit rejected: %v", err)
+	}
+
+	// A header claiming huge dimensions is rejected without decoding the (tiny) pixel data
+	bomb := append([]byte{}, data...)
+	binary.BigEndian.PutUint32(bomb[16:20], 100000)
+	binary.BigEndian.PutUint32(bomb[20:24], 100000)
+	binary.BigEndian.PutUint32(bomb[29:33], crc32.ChecksumIEEE(bomb[12:29]))
+	if _, err := Process(bomb, 40_000_000, 10); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("got %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestProcessStripsExifAndAppliesOrientation(t *testing.T) {
+	data := withExif(encodeJPEG(t, halves(40, 20)), 6, "GPS 51.5007 -0.1246")
+	if jpegOrientation(data) != 6 {
+		t.Fatalf("test image has orientation %d, want 6", jpegOrientation(data))
+	}
+
+	processed, err := Process(data, 1_000_000, 320)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if bytes.Contains(processed.Data, []byte("Exif")) || bytes.Contains(processed.Data, []byte("GPS")) {
+		t.Error("metadata survived re-encoding")
+	}
+	if bytes.Contains(processed.Thumbnail, []byte("Exif")) {
+		t.Error("thumbnail carries metadata")
+	}
+
+	// Rotating 90 degrees clockwise turns the left (red) half into the top half
+	if processed.Width != 20 || processed.Height != 40 {
+		t.Fatalf("got %dx%d, want 20x40", processed.Width, processed.Height)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(processed.Data))
+	if err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Error("top of the rotated image is not red")
+	}
+	if r, _, b, _ := img.At(10, 35).RGBA(); b < r {
+		t.Error("bottom of the rotated image is not blue")
+	}
+}
+
+func TestOrientationIsOnlyReadFromJPEG(t *testing.T) {
+	if got := jpegOrientation(encodePNG(t, halves(4, 4))); got != 1 {
+		t.Errorf("PNG orientation %d, want 1", got)
+	}
+	if got := jpegOrientation(withExif(encodeJPEG(t, halves(4, 4)), 9, "")); got != 1 {
+		t.Errorf("invalid orientation read as %d, want 1", got)
+	}
+	if got := jpegOrientation(encodeJPEG(t, halves(4, 4))); got != 1 {
+		t.Errorf("JPEG without EXIF orientation %d, want 1", got)
+	}
+}
+
+func TestProcessThumbnail(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		thumbWidth    int
+		thumbHeight   int
+	}{
+		{"landscape", 400, 200, 100, 50},
+		{"portrait", 200, 400, 50, 100},
+		{"already small", 60, 30, 60, 30},
+		{"thin", 400, 2, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed, err := Process(encodePNG(t, halves(tt.width, tt.height)), 1_000_000, 100)
+			if err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			if processed.ContentType != ContentTypePNG {
+				t.Errorf("content type %q, want %q", processed.ContentType, ContentTypePNG)
+			}
+
+			thumbnail, err := png.DecodeConfig(bytes.NewReader(processed.Thumbnail))
+			if err != nil {
+				t.Fatalf("decoding thumbnail: %v", err)
+			}
+			if thumbnail.Width != tt.thumbWidth || thumbnail.Height != tt.thumbHeight {
+				t.Errorf("thumbnail is %dx%d, want %dx%d", thumbnail.Width, thumbnail.Height, tt.thumbWidth, tt.thumbHeight)
+			}
+		})
+	}
+}
+
+func TestFitKeepsColours(t *testing.T) {
+	thumbnail := Fit(halves(400, 200), 100)
+	if got := color.NRGBAModel.Convert(thumbnail.At(10, 25)).(color.NRGBA); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("left of thumbnail is %v, want red", got)
+	}
+	if got := color.NRGBAModel.Convert(thumbnail.At(90, 25)).(color.NRGBA); got != (color.NRGBA{B: 255, A: 255}) {
+		t.Errorf("right of thumbnail is %v, want blue", got)
+	}
+}
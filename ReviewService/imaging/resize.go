@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Fit scales img down so that neither side exceeds size, keeping the aspect ratio.
+// Images that already fit are returned unchanged.
+func Fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if size <= 0 || (width <= size && height <= size) {
+		return img
+	}
+
+	dstWidth, dstHeight := size, size
+	if width > height {
+		dstHeight = max(1, height*size/width)
+	} else {
+		dstWidth = max(1, width*size/height)
+	}
+	return resize(img, dstWidth, dstHeight)
+}
+
+// resize downsamples by averaging each destination pixel's source area (a box filter),
+// which is enough for thumbnails and avoids the aliasing of nearest-neighbour sampling.
+func resize(img image.Image, dstWidth int, dstHeight int) *image.NRGBA {
+	src := image.NewNRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0 := y * srcHeight / dstHeight
+		y1 := max(y0+1, (y+1)*srcHeight/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			x0 := x * srcWidth / dstWidth
+			x1 := max(x0+1, (x+1)*srcWidth/dstWidth)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					pixel := src.Pix[offset : offset+4]
+					// Weight colour by alpha so transparent pixels do not darken edges
+					alpha := uint64(pixel[3])
+					r += uint64(pixel[0]) * alpha
+					g += uint64(pixel[1]) * alpha
+					b += uint64(pixel[2]) * alpha
+					a += alpha
+					n++
+					offset += 4
+				}
+			}
+
+			if a == 0 {
+				dst.SetNRGBA(x, y, color.NRGBA{})
+				continue
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / a),
+				G: uint8(g / a),
+				B: uint8(b / a),
+				A: uint8(a / n),
+			})
+		}
+	}
+	return dst
+}
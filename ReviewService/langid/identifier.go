@@ -0,0 +1,269 @@
+package langid
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Language codes, ISO 639-1.
+const (
+	English    = "en"
+	Spanish    = "es"
+	French     = "fr"
+	German     = "de"
+	Italian    = "it"
+	Portuguese = "pt"
+	Dutch      = "nl"
+	Polish     = "pl"
+	Turkish    = "tr"
+	Russian    = "ru"
+	Ukrainian  = "uk"
+	Greek      = "el"
+	Arabic     = "ar"
+	Persian    = "fa"
+	Hebrew     = "he"
+	Hindi      = "hi"
+	Thai       = "th"
+	Korean     = "ko"
+	Japanese   = "ja"
+	Chinese    = "zh"
+)
+
+// Undetermined (ISO 639-2 "und") is returned for text that is too short or too ambiguous to identify.
+const Undetermined = "und"
+
+// Languages lists every code Identify can return besides Undetermined.
+var Languages = []string{English, Spanish, French, German, Italian, Portuguese, Dutch, Polish, Turkish,
+	Russian, Ukrainian, Greek, Arabic, Persian, Hebrew, Hindi, Thai, Korean, Japanese, Chinese}
+
+const (
+	minLetters = 10  // texts with fewer letters are undetermined
+	maxOrder   = 3   // longest character n-gram
+	smoothing  = 0.5 // added to every n-gram count, so unseen n-grams are unlikely rather than impossible
+	// minMargin is how much more likely per n-gram, in log terms, the best language must be than the runner-up
+	minMargin = 0.05
+)
+
+// Identifier tells which language a text is written in. Scripts used by a single language (Greek, Hangul, ...)
+// decide directly; Latin and Cyrillic text is told apart by its letters, and Latin-script languages by a naive
+// Bayes model of character n-grams trained on built-in samples. It is safe for concurrent use.
+type Identifier struct {
+	models map[string]*ngramModel
+}
+
+type ngramModel struct {
+	logProb map[string]float64
+	unseen  float64
+}
+
+func NewIdentifier() *Identifier {
+	counts := map[string]map[string]int{}
+	vocabulary := map[string]bool{}
+	for language, sample := range samples {
+		counts[language] = map[string]int{}
+		for _, gram := range ngrams(sample) {
+			counts[language][gram]++
+			vocabulary[gram] = true
+		}
+	}
+
+	id := &Identifier{models: map[string]*ngramModel{}}
+	for language, grams := range counts {
+		total := 0
+		for _, count := range grams {
+			total += count
+		}
+		denominator := float64(total) + smoothing*float64(len(vocabulary)+1)
+
+		model := &ngramModel{logProb: map[string]float64{}, unseen: math.Log(smoothing / denominator)}
+		for gram, count := range grams {
+			model.logProb[gram] = math.Log((float64(count) + smoothing) / denominator)
+		}
+		id.models[language] = model
+	}
+	return id
+}
+
+// Identify returns the language code of text, or Undetermined.
+func (id *Identifier) Identify(text string) string {
+	letters := 0
+	scripts := map[*unicode.RangeTable]int{}
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, script := range scriptTables {
+			if unicode.Is(script, r) {
+				scripts[script]++
+				break
+			}
+		}
+	}
+	if letters < minLetters {
+		return Undetermined
+	}
+
+	var dominant *unicode.RangeTable
+	for _, script := range scriptTables {
+		if dominant == nil || scripts[script] > scripts[dominant] {
+			dominant = script
+		}
+	}
+	if scripts[dominant] == 0 {
+		return Undetermined
+	}
+
+	switch dominant {
+	case unicode.Latin:
+		return id.identifyLatin(text)
+	case unicode.Cyrillic:
+		if strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			return Ukrainian
+		}
+		return Russian
+	case unicode.Arabic:
+		if strings.ContainsAny(text, "پچژگیک") {
+			return Persian
+		}
+		return Arabic
+	case unicode.Han:
+		// Japanese mixes kanji with kana; Chinese has no kana
+		if scripts[unicode.Hiragana]+scripts[unicode.Katakana] > 0 {
+			return Japanese
+		}
+		return Chinese
+	default:
+		return scriptLanguages[dominant]
+	}
+}
+
+// scriptTables are the scripts Identify recognizes, in the order ties are broken.
+var scriptTables = []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic, unicode.Greek, unicode.Arabic, unicode.Hebrew,
+	unicode.Devanagari, unicode.Thai, unicode.Hangul, unicode.Hiragana, unicode.Katakana, unicode.Han}
+
+// scriptLanguages maps the scripts written in a single language to it.
+var scriptLanguages = map[*unicode.RangeTable]string{
+	unicode.Greek:      Greek,
+	unicode.Hebrew:     Hebrew,
+	unicode.Devanagari: Hindi,
+	unicode.Thai:       Thai,
+	unicode.Hangul:     Korean,
+	unicode.Hiragana:   Japanese,
+	unicode.Katakana:   Japanese,
+}
+
+// identifyLatin picks the Latin-script language whose model makes the text's n-grams most likely.
+func (id *Identifier) identifyLatin(text string) string {
+	grams := ngrams(text)
+	if len(grams) == 0 {
+		return Undetermined
+	}
+
+	best, bestScore, runnerUp := Undetermined, math.Inf(-1), math.Inf(-1)
+	for language, model := range id.models {
+		score := 0.0
+		for _, gram := range grams {
+			if p, ok := model.logProb[gram]; ok {
+				score += p
+			} else {
+				score += model.unseen
+			}
+		}
+		if score > bestScore {
+			best, bestScore, runnerUp = language, score, bestScore
+		} else if score > runnerUp {
+			runnerUp = score
+		}
+	}
+
+	if (bestScore-runnerUp)/float64(len(grams)) < minMargin {
+		return Undetermined
+	}
+	return best
+}
+
+// ngrams returns the character n-grams, up to maxOrder long, of the lowercased Latin words of text.
+// Words are padded with a space on each side so n-grams at word edges are told from those inside words.
+func ngrams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.Is(unicode.Latin, r)
+	})
+
+	var grams []string
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= maxOrder; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if n == 1 && runes[i] == ' ' {
+					continue
+				}
+				grams = append(grams, string(runes[i:i+n]))
+			}
+		}
+	}
+	return grams
+}
+
+// Supported reports whether code is a language Identify can return, or Undetermined.
+func Supported(code string) bool {
+	if code == Undetermined {
+		return true
+	}
+	for _, language := range Languages {
+		if language == code {
+			return true
+		}
+	}
+	return false
+}
+
+// maxPreferences caps the languages taken from an Accept-Language header.
+const maxPreferences = 5
+
+// ParseAcceptLanguage returns the supported languages of an Accept-Language header, most preferred first.
+// Regional variants count as their language ("fr-CH" is "fr"), and languages with q=0 or malformed entries are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	var preferences []preference
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !Supported(language) || language == Undetermined || seen[language] {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		seen[language] = true
+		preferences = append(preferences, preference{language, quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	languages := []string{}
+	for _, p := range preferences {
+		if len(languages) == maxPreferences {
+			break
+		}
+		languages = append(languages, p.language)
+	}
+	return languages
+}
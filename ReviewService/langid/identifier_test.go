@@ -0,0 +1,86 @@
+package langid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		want string
+		text string
+	}{
+		{English, "Lovely little hotel with a friendly owner, our room overlooked the harbour and the beds were comfy."},
+		{Spanish, "Un hotel pequeño pero encantador, el dueño fue muy simpático y la cama era comodísima."},
+		{French, "Petit hôtel charmant, le propriétaire était adorable et notre chambre donnait sur le port."},
+		{German, "Kleines, gemütliches Hotel mit einem sehr freundlichen Besitzer, das Bett war bequem."},
+		{Italian, "Albergo piccolo ma delizioso, il proprietario è stato gentilissimo e il letto era comodo."},
+		{Portuguese, "Hotel pequeno mas encantador, o dono foi muito simpático e a cama era confortável."},
+		{Dutch, "Klein maar gezellig hotel, de eigenaar was erg vriendelijk en het bed sliep heerlijk."},
+		{Polish, "Mały, przytulny hotel, właściciel był bardzo miły, a łóżko wygodne."},
+		{Turkish, "Küçük ama şirin bir otel, sahibi çok güler yüzlüydü ve yatak rahattı."},
+		{Russian, "Уютная маленькая гостиница, хозяин очень приветливый, кровать удобная."},
+		{Ukrainian, "Затишний маленький готель, господар дуже привітний, ліжко зручне."},
+		{Greek, "Μικρό αλλά υπέροχο ξενοδοχείο, ο ιδιοκτήτης ήταν πολύ φιλικός."},
+		{Arabic, "فندق صغير وجميل، وكان المالك ودودا جدا والسرير مريحا."},
+		{Persian, "هتل کوچک و دلپذیری بود، صاحب هتل خیلی مهربان بود و تخت راحت بود."},
+		{Hebrew, "מלון קטן ומקסים, הבעלים היה נחמד מאוד והמיטה הייתה נוחה."},
+		{Hindi, "छोटा लेकिन बहुत सुंदर होटल, मालिक बहुत मिलनसार थे और बिस्तर आरामदायक था।"},
+		{Thai, "โรงแรมเล็กแต่น่ารักมาก เจ้าของใจดีและเตียงนอนสบาย"},
+		{Korean, "작지만 아늑한 호텔이었고 주인이 매우 친절했으며 침대도 편안했습니다."},
+		{Japanese, "小さいけれど素敵なホテルで、オーナーはとても親切でした。"},
+		{Chinese, "酒店虽小但很温馨，老板非常友好，床也很舒服。"},
+	}
+	identifier := NewIdentifier()
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := identifier.Identify(tt.text); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyUndetermined(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"too few letters", "Great!"},
+		{"no letters", "10/10 !!! 5*****"},
+		{"emoji only", "👍👍👍👍👍👍👍👍👍👍👍👍"},
+		{"unsupported script", "სასტუმრო ძალიან კარგია"},
+		// Names shared by every language do not favor any one of them enough
+		{"no clear winner", "Hotel Central Plaza Wifi Parking"},
+	}
+	identifier := NewIdentifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := identifier.Identify(tt.text); got != Undetermined {
+				t.Errorf("got %s, want %s", got, Undetermined)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr", "en", "de"}},
+		{"en;q=0.2, es", []string{"es", "en"}},
+		{"xx, en;q=0, de;q=abc, it", []string{"it"}},
+		{"und, pt-BR", []string{"pt"}},
+		{"en, es, fr, de, it, pt, nl", []string{"en", "es", "fr", "de", "it"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := ParseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
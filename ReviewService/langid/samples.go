@@ -0,0 +1,92 @@
+package langid
+
+// samples is the training text of the Latin-script languages. It mixes everyday language with the vocabulary of
+// hotel reviews, which is what the identifier sees. Adding text improves accuracy; keep the languages balanced.
+var samples = map[string]string{
+	English: `The room was clean and comfortable, and the staff were very friendly and helpful. We stayed for three nights
+and would definitely come back. The location is great, close to the beach and the old town, with plenty of restaurants
+nearby. Breakfast was included but there was not much choice, and the coffee was cold. The bathroom was small and the
+shower did not work properly. It was a bit noisy at night because of the street, so bring earplugs if you are a light
+sleeper. Check in was quick and the receptionist gave us a map of the city. Overall it is good value for the money and
+I would recommend this hotel to anyone who wants a quiet place to stay. What they said about the view from the
+balcony is true, it was wonderful. There is also free parking and the wifi worked well in our room. They should have
+told us that the pool would be closed during our stay, which was the only thing we did not like.`,
+
+	Spanish: `La habitación estaba limpia y era muy cómoda, y el personal fue muy amable y atento. Nos quedamos tres
+noches y sin duda volveremos. La ubicación es excelente, cerca de la playa y del casco antiguo, con muchos restaurantes
+alrededor. El desayuno estaba incluido pero no había mucha variedad y el café estaba frío. El baño era pequeño y la
+ducha no funcionaba bien. Por la noche había bastante ruido de la calle, así que si tienes el sueño ligero lleva
+tapones. El registro fue rápido y la recepcionista nos dio un mapa de la ciudad. En general tiene una buena relación
+calidad precio y recomendaría este hotel a quien busque un lugar tranquilo para alojarse. Lo que dicen de las vistas
+desde el balcón es verdad, eran preciosas. También hay aparcamiento gratuito y el wifi funcionaba bien en nuestra
+habitación. Deberían habernos avisado de que la piscina estaría cerrada durante nuestra estancia, fue lo único que no
+nos gustó.`,
+
+	French: `La chambre était propre et très confortable, et le personnel était aimable et serviable. Nous sommes restés
+trois nuits et nous reviendrons sans hésiter. L'emplacement est idéal, près de la plage et de la vieille ville, avec
+beaucoup de restaurants à proximité. Le petit déjeuner était compris mais il n'y avait pas beaucoup de choix, et le
+café était froid. La salle de bain était petite et la douche ne fonctionnait pas bien. C'était un peu bruyant la nuit
+à cause de la rue, donc prévoyez des bouchons d'oreilles si vous avez le sommeil léger. L'enregistrement a été rapide
+et la réceptionniste nous a donné un plan de la ville. Dans l'ensemble, c'est un bon rapport qualité prix et je
+recommande cet hôtel à tous ceux qui cherchent un endroit calme où séjourner. Ce qu'on dit de la vue depuis le balcon
+est vrai, elle était magnifique. Il y a aussi un parking gratuit et le wifi marchait bien dans notre chambre. Ils
+auraient dû nous prévenir que la piscine serait fermée pendant notre séjour, c'est la seule chose qui ne nous a pas plu.`,
+
+	German: `Das Zimmer war sauber und sehr gemütlich, und das Personal war ausgesprochen freundlich und hilfsbereit. Wir
+waren drei Nächte dort und kommen auf jeden Fall wieder. Die Lage ist super, nah am Strand und an der Altstadt, mit
+vielen Restaurants in der Nähe. Das Frühstück war inklusive, aber die Auswahl war klein und der Kaffee war kalt. Das
+Badezimmer war klein und die Dusche funktionierte nicht richtig. Nachts war es wegen der Straße ziemlich laut, also
+nehmt Ohrstöpsel mit, wenn ihr einen leichten Schlaf habt. Der Check-in ging schnell und die Dame an der Rezeption hat
+uns einen Stadtplan gegeben. Insgesamt ist das Preis-Leistungs-Verhältnis gut und ich würde dieses Hotel jedem
+empfehlen, der eine ruhige Unterkunft sucht. Was über die Aussicht vom Balkon gesagt wird, stimmt, sie war wunderschön.
+Es gibt außerdem kostenlose Parkplätze und das WLAN hat in unserem Zimmer gut funktioniert. Man hätte uns sagen sollen,
+dass der Pool während unseres Aufenthalts geschlossen ist, das war das Einzige, was uns nicht gefallen hat.`,
+
+	Italian: `La camera era pulita e molto comoda, e il personale è stato gentilissimo e disponibile. Siamo rimasti tre
+notti e torneremo sicuramente. La posizione è ottima, vicino alla spiaggia e al centro storico, con tanti ristoranti
+nei dintorni. La colazione era inclusa ma non c'era molta scelta e il caffè era freddo. Il bagno era piccolo e la
+doccia non funzionava bene. Di notte c'era un po' di rumore per via della strada, quindi portate i tappi per le
+orecchie se avete il sonno leggero. Il check in è stato veloce e la ragazza alla reception ci ha dato una cartina
+della città. Nel complesso il rapporto qualità prezzo è buono e consiglierei questo albergo a chi cerca un posto
+tranquillo dove soggiornare. Quello che dicono della vista dal balcone è vero, era bellissima. C'è anche il parcheggio
+gratuito e il wifi funzionava bene nella nostra camera. Avrebbero dovuto avvisarci che la piscina sarebbe stata chiusa
+durante il nostro soggiorno, è l'unica cosa che non ci è piaciuta.`,
+
+	Portuguese: `O quarto estava limpo e era muito confortável, e os funcionários foram muito simpáticos e prestativos.
+Ficamos três noites e com certeza vamos voltar. A localização é ótima, perto da praia e do centro histórico, com
+muitos restaurantes por perto. O café da manhã estava incluído, mas não havia muita opção e o café estava frio. O
+banheiro era pequeno e o chuveiro não funcionava direito. À noite havia bastante barulho da rua, então levem
+protetores de ouvido se vocês têm o sono leve. O check in foi rápido e a recepcionista nos deu um mapa da cidade. No
+geral tem um bom custo benefício e eu recomendaria este hotel para quem procura um lugar tranquilo para se hospedar.
+O que dizem sobre a vista da varanda é verdade, era linda. Também tem estacionamento gratuito e o wifi funcionou bem
+no nosso quarto. Deveriam ter nos avisado que a piscina ficaria fechada durante a nossa estadia, foi a única coisa de
+que não gostamos.`,
+
+	Dutch: `De kamer was schoon en erg comfortabel, en het personeel was heel vriendelijk en behulpzaam. We zijn drie
+nachten gebleven en komen zeker terug. De ligging is geweldig, dicht bij het strand en de oude binnenstad, met veel
+restaurants in de buurt. Het ontbijt was inbegrepen maar er was niet veel keuze en de koffie was koud. De badkamer was
+klein en de douche werkte niet goed. Het was 's nachts nogal lawaaierig door de straat, dus neem oordopjes mee als je
+licht slaapt. Het inchecken ging snel en de receptioniste gaf ons een plattegrond van de stad. Al met al is de prijs
+kwaliteitverhouding goed en ik zou dit hotel aanraden aan iedereen die een rustige plek zoekt om te verblijven. Wat
+ze zeggen over het uitzicht vanaf het balkon klopt, het was prachtig. Er is ook gratis parkeren en de wifi werkte goed
+op onze kamer. Ze hadden ons moeten vertellen dat het zwembad tijdens ons verblijf gesloten zou zijn, dat was het enige
+wat we niet leuk vonden.`,
+
+	Polish: `Pokój był czysty i bardzo wygodny, a obsługa była niezwykle miła i pomocna. Zostaliśmy na trzy noce i na
+pewno wrócimy. Lokalizacja jest świetna, blisko plaży i starego miasta, w okolicy jest mnóstwo restauracji. Śniadanie
+było wliczone w cenę, ale wybór był niewielki, a kawa była zimna. Łazienka była mała i prysznic nie działał jak
+należy. W nocy było dość głośno z powodu ulicy, więc jeśli macie lekki sen, weźcie zatyczki do uszu. Zameldowanie
+przebiegło szybko, a recepcjonistka dała nam mapę miasta. Ogólnie stosunek jakości do ceny jest dobry i polecam ten
+hotel każdemu, kto szuka spokojnego miejsca na nocleg. To, co mówią o widoku z balkonu, jest prawdą, był przepiękny.
+Jest też bezpłatny parking, a wifi działało dobrze w naszym pokoju. Powinni byli nas uprzedzić, że basen będzie
+zamknięty podczas naszego pobytu, to była jedyna rzecz, która nam się nie podobała.`,
+
+	Turkish: `Oda temiz ve çok rahattı, personel de son derece güler yüzlü ve yardımseverdi. Üç gece kaldık ve kesinlikle
+tekrar geleceğiz. Konumu harika, plaja ve eski şehre yakın, çevrede bir sürü restoran var. Kahvaltı fiyata dahildi ama
+pek çeşit yoktu ve kahve soğuktu. Banyo küçüktü ve duş düzgün çalışmıyordu. Gece sokak yüzünden biraz gürültülüydü, bu
+yüzden uykunuz hafifse kulak tıkacı getirin. Giriş işlemleri hızlıydı ve resepsiyondaki görevli bize bir şehir
+haritası verdi. Genel olarak fiyatına göre iyi ve sakin bir yerde kalmak isteyen herkese bu oteli tavsiye ederim.
+Balkondan manzara hakkında söylenenler doğru, muhteşemdi. Ayrıca ücretsiz otopark var ve odamızda internet iyi
+çalışıyordu. Kaldığımız süre boyunca havuzun kapalı olacağını bize söylemeleri gerekirdi, hoşumuza gitmeyen tek şey
+buydu.`,
+}
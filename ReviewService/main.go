@@ -3,6 +3,8 @@ package main
 import (
 	"ReviewService/app"
 	config "ReviewService/config/env"
+	"fmt"
+	"os"
 )
 
 func main() {
@@ -12,5 +14,27 @@ func main() {
 	cfg := app.NewConfig() // Set the server to listen on port 8081
 	app := app.NewApplication(cfg)
 
+	// One-off commands run instead of the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "backfill-sentiment":
+			if err := app.RunSentimentBackfill(); err != nil {
+				os.Exit(1)
+			}
+		case "backfill-fingerprints":
+			if err := app.RunFingerprintBackfill(); err != nil {
+				os.Exit(1)
+			}
+		case "backfill-languages":
+			if err := app.RunLanguageBackfill(); err != nil {
+				os.Exit(1)
+			}
+		default:
+			fmt.Println("Unknown command:", os.Args[1])
+			os.Exit(2)
+		}
+		return
+	}
+
 	app.Run()
 }
@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"ReviewService/models"
+	"ReviewService/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewJWTAuthMiddleware verifies the bearer token with the given verifier and puts the caller into the context.
+func NewJWTAuthMiddleware(verifier utils.TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+
+			if authHeader == "" {
+				utils.WriteJsonErrorResponse(w, http.StatusUnauthorized, "Authorization header is required", fmt.Errorf("missing authorization header"))
+				return
+			}
+
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				utils.WriteJsonErrorResponse(w, http.StatusUnauthorized, "Authorization header must start with Bearer", fmt.Errorf("invalid authorization header"))
+				return
+			}
+
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if token == "" {
+				utils.WriteJsonErrorResponse(w, http.StatusUnauthorized, "Token is required", fmt.Errorf("missing token"))
+				return
+			}
+
+			user, err := verifier.Verify(token)
+			if err != nil {
+				utils.WriteJsonErrorResponse(w, http.StatusUnauthorized, "Invalid token", err)
+				return
+			}
+
+			fmt.Println("Authenticated user ID:", user.Id)
+
+			ctx := context.WithValue(r.Context(), "authUser", user)
+			ctx = context.WithValue(ctx, "userID", strconv.FormatInt(user.Id, 10))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePermission rejects callers whose token lacks the permission. It must run after the JWT auth middleware.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("authUser").(*models.AuthUser)
+			if !ok || !user.HasPermission(permission) {
+				utils.WriteJsonErrorResponse(w, http.StatusForbidden, "Forbidden", fmt.Errorf("missing permission %s", permission))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
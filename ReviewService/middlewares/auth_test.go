@@ -0,0 +1,127 @@
+package middlewares
+
+import (
+	"ReviewService/models"
+	"ReviewService/utils"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("review-service-test-secret")
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+func claims(permissions ...string) jwt.MapClaims {
+	return jwt.MapClaims{"id": 7, "email": "guest@example.com", "permissions": permissions, "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+// serve runs the request through the chain and reports the status and the caller the handler saw.
+func serve(t *testing.T, authHeader string, chain ...func(http.Handler) http.Handler) (int, *models.AuthUser, string) {
+	t.Helper()
+	var user *models.AuthUser
+	var userId string
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = r.Context().Value("authUser").(*models.AuthUser)
+		userId, _ = r.Context().Value("userID").(string)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, user, userId
+}
+
+func TestJWTAuthMiddlewarePutsCallerIntoContext(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier utils.TokenVerifier
+		token    string
+	}{
+		{"HMAC", utils.NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, claims())},
+		{"RSA", utils.NewRSAVerifier(&key.PublicKey), signToken(t, jwt.SigningMethodRS256, key, claims())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user, userId := serve(t, "Bearer "+tt.token, NewJWTAuthMiddleware(tt.verifier))
+			if status != http.StatusNoContent {
+				t.Fatalf("got status %d, want %d", status, http.StatusNoContent)
+			}
+			if user == nil || user.Id != 7 || userId != "7" {
+				t.Errorf("got user %+v and user ID %q, want 7", user, userId)
+			}
+		})
+	}
+}
+
+func TestJWTAuthMiddlewareRejectsBadCredentials(t *testing.T) {
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	missingClaims := jwt.MapClaims{"roles": []string{"user"}}
+
+	tests := []struct {
+		name       string
+		authHeader string
+	}{
+		{"no header", ""},
+		{"not bearer", "Basic Z3Vlc3Q6c2VjcmV0"},
+		{"empty token", "Bearer "},
+		{"expired", "Bearer " + signToken(t, jwt.SigningMethodHS256, testSecret, expired)},
+		{"wrong algorithm", "Bearer " + signToken(t, jwt.SigningMethodHS512, []byte("other-secret"), claims())},
+		{"missing claims", "Bearer " + signToken(t, jwt.SigningMethodHS256, testSecret, missingClaims)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user, _ := serve(t, tt.authHeader, NewJWTAuthMiddleware(utils.NewHMACVerifier(testSecret)))
+			if status != http.StatusUnauthorized {
+				t.Errorf("got status %d, want %d", status, http.StatusUnauthorized)
+			}
+			if user != nil {
+				t.Error("handler ran for a rejected request")
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	auth := NewJWTAuthMiddleware(utils.NewHMACVerifier(testSecret))
+	moderator := "Bearer " + signToken(t, jwt.SigningMethodHS256, testSecret, claims("review:moderate"))
+	guest := "Bearer " + signToken(t, jwt.SigningMethodHS256, testSecret, claims())
+
+	if status, _, _ := serve(t, moderator, auth, RequirePermission("review:moderate")); status != http.StatusNoContent {
+		t.Errorf("moderator got status %d, want %d", status, http.StatusNoContent)
+	}
+	if status, _, _ := serve(t, guest, auth, RequirePermission("review:moderate")); status != http.StatusForbidden {
+		t.Errorf("guest got status %d, want %d", status, http.StatusForbidden)
+	}
+	// Without the auth middleware in front there is no caller to check
+	if status, _, _ := serve(t, moderator, RequirePermission("review:moderate")); status != http.StatusForbidden {
+		t.Errorf("unauthenticated request got status %d, want %d", status, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,157 @@
+package middlewares
+
+import (
+	repo "ReviewService/db/repositories"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// NewIdempotencyMiddleware replays the stored response when a caller retries a request with the same Idempotency-Key.
+// It must run after the auth middleware, since keys are scoped to the authenticated user. Requests without the header pass through.
+// A key still in progress after staleAfter, left by a crash or a failed write, is taken over by the next retry.
+// A finished key expires ttl after its request, and can then be used for a new request.
+func NewIdempotencyMiddleware(idempotencyKeyRepository repo.IdempotencyKeyRepository, staleAfter time.Duration, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idemKey := r.Header.Get(IdempotencyKeyHeader)
+			if idemKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idemKey) > maxIdempotencyKeyLength {
+				utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Idempotency-Key is too long", fmt.Errorf("idempotency key must be at most %d characters", maxIdempotencyKeyLength))
+				return
+			}
+
+			author := r.Context().Value("authUser").(*models.AuthUser)
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					utils.WriteJsonErrorResponse(w, http.StatusRequestEntityTooLarge, "Request body is too large", fmt.Errorf("request body must be at most %d bytes", maxIdempotentRequestBytes))
+					return
+				}
+				utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Failed to read request body", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			sum := sha256.Sum256(body)
+			requestHash := hex.EncodeToString(sum[:])
+
+			key, err := idempotencyKeyRepository.Create(idemKey, author.Id, requestHash)
+			if errors.Is(err, repo.ErrDuplicateEntry) {
+				key = replayIdempotentResponse(w, idempotencyKeyRepository, idemKey, author.Id, requestHash, staleAfter, ttl)
+				if key == nil {
+					return
+				}
+			} else if err != nil {
+				utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to store idempotency key", err)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// Server errors are not stored so the caller can retry with the same key
+			// A key left unfinalized here is taken over once it is stale
+			if recorder.status >= http.StatusInternalServerError {
+				if err := idempotencyKeyRepository.Delete(key.Id); err != nil {
+					fmt.Println("Error releasing idempotency key", idemKey, "for user", author.Id, ":", err)
+				}
+				return
+			}
+			if err := idempotencyKeyRepository.Finalize(key.Id, recorder.status, recorder.body.String()); err != nil {
+				fmt.Println("Error storing response for idempotency key", idemKey, "for user", author.Id, ":", err)
+			}
+		})
+	}
+}
+
+// replayIdempotentResponse answers a retry of a request whose key is already stored. It returns the key when the
+// earlier request went stale or expired and this one took it over, so the caller should run the request itself.
+func replayIdempotentResponse(w http.ResponseWriter, idempotencyKeyRepository repo.IdempotencyKeyRepository, idemKey string, userId int64, requestHash string, staleAfter time.Duration, ttl time.Duration) *models.IdempotencyKey {
+	key, err := idempotencyKeyRepository.GetByKey(idemKey, userId)
+	if err != nil {
+		utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to fetch idempotency key", err)
+		return nil
+	}
+	if key == nil {
+		// The original request failed and released the key in the meantime
+		utils.WriteJsonErrorResponse(w, http.StatusConflict, "Request with this Idempotency-Key was not completed, retry it", fmt.Errorf("idempotency key %s was released", idemKey))
+		return nil
+	}
+	if key.Finalized {
+		// An expired key is free for a new request, whatever its body
+		renewed, err := idempotencyKeyRepository.Renew(key.Id, requestHash, ttl)
+		if err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to renew idempotency key", err)
+			return nil
+		}
+		if renewed {
+			key.RequestHash = requestHash
+			return key
+		}
+	}
+	if key.RequestHash != requestHash {
+		utils.WriteJsonErrorResponse(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request body", fmt.Errorf("idempotency key %s reused with a different payload", idemKey))
+		return nil
+	}
+	if !key.Finalized || key.StatusCode == nil {
+		claimed, err := idempotencyKeyRepository.TakeOver(key.Id, staleAfter)
+		if err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusInternalServerError, "Failed to take over idempotency key", err)
+			return nil
+		}
+		if claimed {
+			fmt.Println("Taking over stale idempotency key", idemKey, "for user", userId)
+			return key
+		}
+		utils.WriteJsonErrorResponse(w, http.StatusConflict, "Request with this Idempotency-Key is still in progress", fmt.Errorf("idempotency key %s is in progress", idemKey))
+		return nil
+	}
+
+	body := ""
+	if key.ResponseBody != nil {
+		body = *key.ResponseBody
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(*key.StatusCode)
+	io.WriteString(w, body)
+	return nil
+}
+
+// responseRecorder captures the status and body written by the wrapped handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,208 @@
+package middlewares
+
+import (
+	repo "ReviewService/db/repositories"
+	"ReviewService/models"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryIdempotencyKeys is an IdempotencyKeyRepository on a map. Keys count as stale once stale is set, and as
+// expired once expired is set.
+type memoryIdempotencyKeys struct {
+	mu          sync.Mutex
+	keys        map[string]*models.IdempotencyKey
+	nextId      int64
+	stale       bool
+	expired     bool
+	finalizeErr error
+}
+
+func newMemoryIdempotencyKeys() *memoryIdempotencyKeys {
+	return &memoryIdempotencyKeys{keys: map[string]*models.IdempotencyKey{}}
+}
+
+func (m *memoryIdempotencyKeys) Create(idemKey string, userId int64, requestHash string) (*models.IdempotencyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.keys[idemKey]; ok {
+		return nil, repo.ErrDuplicateEntry
+	}
+	m.nextId++
+	key := &models.IdempotencyKey{Id: m.nextId, IdemKey: idemKey, UserId: userId, RequestHash: requestHash}
+	m.keys[idemKey] = key
+	copied := *key
+	return &copied, nil
+}
+
+func (m *memoryIdempotencyKeys) GetByKey(idemKey string, userId int64) (*models.IdempotencyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[idemKey]
+	if !ok {
+		return nil, nil
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (m *memoryIdempotencyKeys) Finalize(id int64, statusCode int, responseBody string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.finalizeErr != nil {
+		return m.finalizeErr
+	}
+	for _, key := range m.keys {
+		if key.Id == id {
+			key.Finalized = true
+			key.StatusCode = &statusCode
+			key.ResponseBody = &responseBody
+		}
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyKeys) Delete(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, key := range m.keys {
+		if key.Id == id {
+			delete(m.keys, name)
+		}
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyKeys) TakeOver(id int64, staleAfter time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		if key.Id == id && !key.Finalized && m.stale {
+			m.stale = false
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryIdempotencyKeys) Renew(id int64, requestHash string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		if key.Id == id && key.Finalized && m.expired {
+			m.expired = false
+			key.Finalized, key.StatusCode, key.ResponseBody, key.RequestHash = false, nil, nil, requestHash
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryIdempotencyKeys) DeleteExpired(ttl time.Duration, limit int) (int64, error) {
+	return 0, nil
+}
+
+// postReview sends a request through the idempotency middleware and counts how often the handler ran.
+func postReview(t *testing.T, keys repo.IdempotencyKeyRepository, idemKey string, body string, runs *int) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := NewIdempotencyMiddleware(keys, time.Minute, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*runs++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, idemKey)
+	req = req.WithContext(context.WithValue(req.Context(), "authUser", &models.AuthUser{Id: 7}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	runs := 0
+
+	first := postReview(t, keys, "key-1", `{"rating":5}`, &runs)
+	second := postReview(t, keys, "key-1", `{"rating":5}`, &runs)
+
+	if runs != 1 {
+		t.Fatalf("handler ran %d times, want 1", runs)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay got %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("replay is not marked as replayed")
+	}
+
+	if rec := postReview(t, keys, "key-1", `{"rating":1}`, &runs); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another body got %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyTakesOverStaleKey(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	keys.finalizeErr = errors.New("connection lost")
+	runs := 0
+
+	// The response could not be stored, so the key stays in progress
+	postReview(t, keys, "key-1", `{"rating":5}`, &runs)
+	if rec := postReview(t, keys, "key-1", `{"rating":5}`, &runs); rec.Code != http.StatusConflict {
+		t.Fatalf("retry of a fresh in-progress key got %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	keys.finalizeErr = nil
+	keys.stale = true
+	if rec := postReview(t, keys, "key-1", `{"rating":5}`, &runs); rec.Code != http.StatusCreated {
+		t.Fatalf("retry of a stale key got %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if runs != 2 {
+		t.Errorf("handler ran %d times, want 2", runs)
+	}
+
+	if rec := postReview(t, keys, "key-1", `{"rating":5}`, &runs); rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("response of the takeover was not stored")
+	}
+}
+
+func TestIdempotencyForgetsExpiredKey(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	runs := 0
+
+	postReview(t, keys, "key-1", `{"rating":5}`, &runs)
+
+	keys.expired = true
+	if rec := postReview(t, keys, "key-1", `{"rating":1}`, &runs); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("expired key with a new body got %d, want a fresh run", rec.Code)
+	}
+	if runs != 2 {
+		t.Errorf("handler ran %d times, want 2", runs)
+	}
+
+	if rec := postReview(t, keys, "key-1", `{"rating":1}`, &runs); rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("response of the renewed key was not stored")
+	}
+	if rec := postReview(t, keys, "key-1", `{"rating":5}`, &runs); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("renewed key with the old body got %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyRejectsLargeBody(t *testing.T) {
+	keys := newMemoryIdempotencyKeys()
+	runs := 0
+
+	rec := postReview(t, keys, "key-1", strings.Repeat("a", maxIdempotentRequestBytes+1), &runs)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if runs != 0 || len(keys.keys) != 0 {
+		t.Error("an oversized request was run or stored")
+	}
+}
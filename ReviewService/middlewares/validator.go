@@ -49,3 +49,79 @@ func ReviewUpdateRequestValidator(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func ReviewModerateRequestValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload dto.ModerateReviewRequestDTO
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload", err)
+			return
+		}
+
+		if err := validate.Struct(payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Validation failed", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "payload", payload)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func ReviewResponseRequestValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload dto.ReviewResponseRequestDTO
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload", err)
+			return
+		}
+
+		if err := validate.Struct(payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Validation failed", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "payload", payload)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func ReviewVoteRequestValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload dto.ReviewVoteRequestDTO
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload", err)
+			return
+		}
+
+		if err := validate.Struct(payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Validation failed", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "payload", payload)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func ReviewReportRequestValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload dto.ReviewReportRequestDTO
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload", err)
+			return
+		}
+
+		if err := validate.Struct(payload); err != nil {
+			utils.WriteJsonErrorResponse(w, http.StatusBadRequest, "Validation failed", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "payload", payload)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
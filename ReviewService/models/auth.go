@@ -0,0 +1,33 @@
+package models
+
+// AuthUser is the caller identity taken from a verified AuthInGo token.
+type AuthUser struct {
+	Id          int64
+	Email       string
+	Roles       []string
+	Permissions []string
+}
+
+func (u *AuthUser) HasPermission(permission string) bool {
+	if u == nil {
+		return false
+	}
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *AuthUser) HasRole(role string) bool {
+	if u == nil {
+		return false
+	}
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
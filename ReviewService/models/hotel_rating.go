@@ -0,0 +1,16 @@
+package models
+
+// HotelRating is the aggregate of a hotel's active reviews at a point in time.
+type HotelRating struct {
+	HotelId    int64
+	Average    float64
+	Count      int64
+	Categories map[string]RatingAggregate
+	Unsynced   []ReviewVersion // the hotel's unsynced reviews as they were when the aggregate was taken
+}
+
+// ReviewVersion identifies one state of a review by its id and the time of its last change.
+type ReviewVersion struct {
+	Id        int64
+	UpdatedAt string
+}
@@ -0,0 +1,15 @@
+package models
+
+// HotelRatingSummary holds the raw aggregates of a hotel's active reviews.
+type HotelRatingSummary struct {
+	HotelId    int64
+	Count      int64
+	Sum        int64
+	Histogram  [5]int64 // Histogram[i] counts reviews rated i+1 stars
+	Count30d   int64
+	Sum30d     int64
+	Count90d   int64
+	Sum90d     int64
+	Categories map[string]RatingAggregate
+	Languages  map[string]int64 // reviews per language code; reviews not identified yet are left out
+}
@@ -0,0 +1,13 @@
+package models
+
+type IdempotencyKey struct {
+	Id           int64
+	IdemKey      string
+	UserId       int64
+	RequestHash  string
+	Finalized    bool
+	StatusCode   *int
+	ResponseBody *string
+	CreatedAt    string
+	UpdatedAt    string
+}
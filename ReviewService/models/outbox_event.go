@@ -0,0 +1,28 @@
+package models
+
+const (
+	EventReviewCreated = "review.created"
+	EventReviewUpdated = "review.updated"
+	EventReviewDeleted = "review.deleted"
+)
+
+// OutboxEvent is a domain event stored with the change that caused it, waiting for the relay to publish it.
+type OutboxEvent struct {
+	Id          int64
+	EventId     string // unique per event; brokers use it to drop redeliveries
+	EventType   string
+	AggregateId int64 // the review ID
+	Payload     []byte
+	CreatedAt   string
+	PublishedAt *string
+	Attempts    int
+	LastError   *string
+}
+
+// ReviewEventPayload is the JSON body of a review event. Review is the full state after the change.
+type ReviewEventPayload struct {
+	EventId    string  `json:"eventId"`
+	Type       string  `json:"type"`
+	OccurredAt string  `json:"occurredAt"`
+	Review     *Review `json:"review"`
+}
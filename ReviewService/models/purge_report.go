@@ -0,0 +1,32 @@
+package models
+
+// PurgeReport lists what a purge removed, or on a dry run what it would remove.
+type PurgeReport struct {
+	DryRun          bool
+	ReviewIds       []int64
+	Reviews         int64
+	Photos          int64
+	Votes           int64
+	Reports         int64
+	Responses       int64
+	CategoryRatings int64
+	Aspects         int64
+	Revisions       int64
+	FraudChecks     int64
+	BlobKeys        []string // photo and thumbnail blobs of the purged photos
+}
+
+// Add folds another batch's report into r.
+func (r *PurgeReport) Add(other *PurgeReport) {
+	r.ReviewIds = append(r.ReviewIds, other.ReviewIds...)
+	r.Reviews += other.Reviews
+	r.Photos += other.Photos
+	r.Votes += other.Votes
+	r.Reports += other.Reports
+	r.Responses += other.Responses
+	r.CategoryRatings += other.CategoryRatings
+	r.Aspects += other.Aspects
+	r.Revisions += other.Revisions
+	r.FraudChecks += other.FraudChecks
+	r.BlobKeys = append(r.BlobKeys, other.BlobKeys...)
+}
@@ -1,14 +1,43 @@
 package models
 
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+	ModerationHidden   = "hidden"
+)
+
 type Review struct {
-	Id        int64
-	UserId    int64
-	BookingId int64
-	HotelId   int64
-	Comment   string
-	Rating    int
-	CreatedAt string
-	UpdatedAt string
-	DeletedAt *string
-	IsSynced  bool
+	Id               int64
+	UserId           int64
+	BookingId        int64
+	HotelId          int64
+	Comment          string
+	Rating           int
+	CreatedAt        string
+	UpdatedAt        string
+	EditedAt         *string // last time the author or a moderator changed the content
+	IsEdited         bool    // not a column; true once EditedAt is set
+	DeletedAt        *string
+	IsSynced         bool
+	IsVerifiedStay   bool
+	ModerationStatus string
+	ModerationReason *string
+	ModeratedBy      *int64
+	ModeratedAt      *string
+	TakedownRequeued bool // edited after a takedown, so it stays pending until a moderator decides
+	HelpfulCount     int
+	NotHelpfulCount  int
+	ReportCount      int                // open reports
+	ReportWeight     float64            // total weight of the open reports
+	SentimentScore   *float64           // in [-1, 1]; nil until the comment is analyzed
+	SentimentVersion *int               // analyzer version that produced SentimentScore
+	Language         *string            // ISO 639-1 code of the comment, "und" when undetermined; nil until identified
+	Aspects          map[string]float64 // not a column; stored in review_aspects, aspect -> sentiment
+	CategoryRatings  map[string]int     // not a column; stored in review_category_ratings, keyed by category
+	Response         *ReviewResponse    // not a column; attached from review_responses on public reads
+	Photos           []*ReviewPhoto     // not a column; attached from review_photos on public reads
+	Fingerprint      *TextFingerprint   `json:"-"` // not a column; written to review_fingerprints with the comment
+	FraudCheck       *FraudCheck        `json:"-"` // not a column; written to review_fraud_checks with a new review
+	RelevanceScore   string             `json:"-"` // not a column; the score List ranked by for the relevant sort
 }
@@ -0,0 +1,18 @@
+package models
+
+// AspectAggregate counts the reviews mentioning one aspect, split by the sentiment of the mention.
+type AspectAggregate struct {
+	Mentions     int64
+	Positive     int64
+	Neutral      int64
+	Negative     int64
+	SentimentSum float64
+}
+
+// HotelAspectSummary aggregates the aspects mentioned in a hotel's active, approved reviews.
+// Only analyzed reviews are counted, so AnalyzedCount is the base for mention rates.
+type HotelAspectSummary struct {
+	HotelId       int64
+	AnalyzedCount int64
+	Aspects       map[string]AspectAggregate // aspects nobody mentioned are absent
+}
@@ -0,0 +1,20 @@
+package models
+
+// Categories a guest can rate alongside the overall rating.
+const (
+	CategoryCleanliness   = "cleanliness"
+	CategoryAccuracy      = "accuracy"
+	CategoryCheckIn       = "check_in"
+	CategoryCommunication = "communication"
+	CategoryLocation      = "location"
+	CategoryValue         = "value"
+)
+
+// ReviewCategories lists every category in display order.
+var ReviewCategories = []string{CategoryCleanliness, CategoryAccuracy, CategoryCheckIn, CategoryCommunication, CategoryLocation, CategoryValue}
+
+// RatingAggregate is the count and sum of the ratings given in one category.
+type RatingAggregate struct {
+	Count int64
+	Sum   int64
+}
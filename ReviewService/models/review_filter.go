@@ -0,0 +1,63 @@
+package models
+
+type ReviewSort string
+
+const (
+	ReviewSortNewest  ReviewSort = "newest"
+	ReviewSortOldest  ReviewSort = "oldest"
+	ReviewSortHighest ReviewSort = "highest"
+	ReviewSortLowest  ReviewSort = "lowest"
+	// ReviewSortRelevant ranks by helpfulness, recency, comment length and verified stay
+	ReviewSortRelevant ReviewSort = "relevant"
+	// ReviewSortMostReported ranks by open report count, then report weight; moderators only
+	ReviewSortMostReported ReviewSort = "most_reported"
+)
+
+// ReviewCursor is the position of the last review of a page in the order of Sort.
+// Relevance decays with age, so it is computed as of AsOf, the time the first page was read, on every page.
+type ReviewCursor struct {
+	Sort      ReviewSort `json:"s"`
+	Rating    int        `json:"r,omitempty"`
+	CreatedAt string     `json:"c,omitempty"`
+	Id        int64      `json:"i,omitempty"`
+	AsOf      string     `json:"t,omitempty"`  // relevant: "YYYY-MM-DD HH:MM:SS" UTC
+	Score     string     `json:"sc,omitempty"` // relevant: the relevance score as a decimal
+	// ReportCount and ReportWeight position most_reported pages
+	ReportCount  int     `json:"rc,omitempty"`
+	ReportWeight float64 `json:"rw,omitempty"`
+	// Preferred and LanguageRank carry the "your language first" order, so later pages keep the first page's order
+	Preferred    []string `json:"p,omitempty"`
+	LanguageRank int      `json:"l,omitempty"`
+}
+
+// ReviewFilter selects a page of active (not deleted) reviews. Nil and zero fields do not filter.
+type ReviewFilter struct {
+	HotelId            *int64
+	UserId             *int64
+	ModerationStatus   string
+	MinRating          int
+	MaxRating          int
+	CreatedFrom        string // inclusive, "YYYY-MM-DD HH:MM:SS" UTC
+	CreatedBefore      string // exclusive, "YYYY-MM-DD HH:MM:SS" UTC
+	HasComment         *bool
+	Reported           bool     // only reviews with open reports
+	Language           string   // ISO 639-1 code, or "und"
+	PreferredLanguages []string // reviews in these languages come first, in this order, ahead of the sort
+	Sort               ReviewSort
+	RelevanceAsOf      string // "YYYY-MM-DD HH:MM:SS" UTC the relevance of the relevant sort is computed as of
+	After              *ReviewCursor
+	Limit              int
+}
+
+// LanguageRank is the position of the review's language among PreferredLanguages, from 1, or one past the last
+// for other languages. It matches the rank List orders by.
+func (f *ReviewFilter) LanguageRank(review *Review) int {
+	if review.Language != nil {
+		for i, language := range f.PreferredLanguages {
+			if language == *review.Language {
+				return i + 1
+			}
+		}
+	}
+	return len(f.PreferredLanguages) + 1
+}
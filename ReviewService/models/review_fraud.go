@@ -0,0 +1,44 @@
+package models
+
+const (
+	FraudDecisionPass = "pass"
+	FraudDecisionHold = "hold" // the review was sent to moderation instead of being published
+)
+
+// FraudSignal is one signal of a fraud check. Score is in [0, 1]; Weight is the most it can add to the check's score.
+type FraudSignal struct {
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// FraudCheck records the fraud score of a new review and the breakdown it was decided on.
+type FraudCheck struct {
+	Id        int64
+	ReviewId  int64
+	Score     float64
+	Decision  string
+	Signals   map[string]*FraudSignal // stored as JSON
+	CreatedAt string
+}
+
+// TextFingerprint is the MinHash signature of a review's comment and its LSH band keys.
+// Signature is nil for comments too short to compare, which are stored without bands.
+type TextFingerprint struct {
+	Signature []byte
+	Bands     []int64
+}
+
+// FingerprintMatch is a review whose fingerprint shares a band with the one looked up.
+type FingerprintMatch struct {
+	ReviewId  int64
+	UserId    int64
+	Signature []byte
+}
+
+// UserRatingStats summarizes the ratings of a user's active reviews.
+type UserRatingStats struct {
+	Count  int
+	Mean   float64
+	StdDev float64
+}
@@ -0,0 +1,7 @@
+package models
+
+// BookingUser identifies the single active review a user can have for a booking.
+type BookingUser struct {
+	BookingId int64
+	UserId    int64
+}
@@ -0,0 +1,18 @@
+package models
+
+// ReviewPhoto is an image attached to a review. The image bytes live in the blob store under BlobKey and ThumbnailKey.
+type ReviewPhoto struct {
+	Id           int64
+	ReviewId     int64
+	UserId       int64
+	BlobKey      string
+	ThumbnailKey string
+	ContentType  string
+	SizeBytes    int
+	Width        int
+	Height       int
+	CreatedAt    string
+	DeletedAt    *string
+	Url          string // not a column; where the service serves the image
+	ThumbnailUrl string // not a column; where the service serves the thumbnail
+}
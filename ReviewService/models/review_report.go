@@ -0,0 +1,25 @@
+package models
+
+// Reasons a review can be reported for.
+const (
+	ReportSpam               = "spam"
+	ReportOffensive          = "offensive"
+	ReportIrrelevant         = "irrelevant"
+	ReportConflictOfInterest = "conflict_of_interest"
+)
+
+// ReportReasons lists every report reason in display order.
+var ReportReasons = []string{ReportSpam, ReportOffensive, ReportIrrelevant, ReportConflictOfInterest}
+
+// ReviewReport is one user's report of a review. A report is open until a moderator decides on the review.
+type ReviewReport struct {
+	Id         int64
+	ReviewId   int64
+	ReporterId int64
+	Reason     string
+	Details    *string
+	Weight     float64 // how much the report counts towards hiding the review
+	CreatedAt  string
+	ResolvedAt *string
+	ResolvedBy *int64
+}
@@ -0,0 +1,12 @@
+package models
+
+// ReviewResponse is a host's public answer to a review.
+type ReviewResponse struct {
+	Id          int64
+	ReviewId    int64
+	ResponderId int64
+	Body        string
+	CreatedAt   string
+	UpdatedAt   string
+	DeletedAt   *string
+}
@@ -0,0 +1,13 @@
+package models
+
+// ReviewRevision is a snapshot of a review's content after a change, with who made it.
+type ReviewRevision struct {
+	Id              int64
+	ReviewId        int64
+	Revision        int // 1 for the review as first written
+	EditorId        int64
+	Comment         string
+	Rating          int
+	CategoryRatings map[string]int
+	CreatedAt       string
+}
@@ -0,0 +1,10 @@
+package models
+
+type ReviewVote struct {
+	Id        int64
+	ReviewId  int64
+	UserId    int64
+	Helpful   bool
+	CreatedAt string
+	UpdatedAt string
+}
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"ReviewService/clients"
+	"ReviewService/events"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	MailerQueue = "queue-mailer"
+
+	// mailerJobName is the only job name NotificationService's mailer worker accepts
+	mailerJobName = "payload:mail"
+)
+
+// mailJob mirrors NotificationService's NotificationDto.
+type mailJob struct {
+	To         string         `json:"to"`
+	Subject    string         `json:"subject"`
+	TemplateId string         `json:"templateId"`
+	Params     map[string]any `json:"params"`
+}
+
+// MailerNotifier emails notifications by adding a job to NotificationService's mailer queue,
+// looking up the user's address in AuthInGo.
+type MailerNotifier struct {
+	users    clients.UserClient
+	queue    *events.BullMQQueue
+	attempts int
+}
+
+func NewMailerNotifier(_users clients.UserClient, _queue *events.BullMQQueue, attempts int) Notifier {
+	if attempts <= 0 {
+		attempts = 3
+	}
+	return &MailerNotifier{
+		users:    _users,
+		queue:    _queue,
+		attempts: attempts,
+	}
+}
+
+// Notify uses the notification key as the job id, so a notification handed over twice is emailed once.
+func (m *MailerNotifier) Notify(notification *Notification) error {
+	if notification.Key == "" {
+		return fmt.Errorf("notification %q has no key", notification.TemplateId)
+	}
+
+	user, err := m.users.GetUser(notification.UserId)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Email == "" {
+		return fmt.Errorf("no email address for user %d", notification.UserId)
+	}
+
+	data, err := json.Marshal(&mailJob{
+		To:         user.Email,
+		Subject:    notification.Subject,
+		TemplateId: notification.TemplateId,
+		Params:     notification.Params,
+	})
+	if err != nil {
+		return err
+	}
+
+	added, err := m.queue.Add(context.Background(), "notification:"+notification.Key, mailerJobName, data, map[string]any{
+		"attempts": m.attempts,
+		"backoff":  map[string]any{"type": "exponential", "delay": 1000},
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Println("Notification", notification.Key, "was already queued")
+	}
+	return nil
+}
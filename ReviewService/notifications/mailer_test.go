@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"ReviewService/clients"
+	"ReviewService/events"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestMailer(t *testing.T) (Notifier, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	users := clients.NewInMemoryUserClient(&clients.User{Id: 7, Email: "guest@example.com"})
+	return NewMailerNotifier(users, events.NewBullMQQueue(client, "", MailerQueue), 0), server
+}
+
+func responseNotification(userId int64) *Notification {
+	return &Notification{
+		Key:        "review-response-3",
+		UserId:     userId,
+		Subject:    "The hotel responded to your review",
+		TemplateId: TemplateReviewResponse,
+		Params:     map[string]any{"reviewId": 12, "response": "Thank you!"},
+	}
+}
+
+func TestMailerNotifierQueuesMailJob(t *testing.T) {
+	mailer, server := newTestMailer(t)
+
+	// Notifying twice, as a retried request would, queues one email
+	for i := 0; i < 2; i++ {
+		if err := mailer.Notify(responseNotification(7)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waiting, _ := server.List("bull:queue-mailer:wait")
+	if len(waiting) != 1 || waiting[0] != "notification:review-response-3" {
+		t.Fatalf("wait list %v, want the notification once", waiting)
+	}
+	job := "bull:queue-mailer:" + waiting[0]
+	if name := server.HGet(job, "name"); name != "payload:mail" {
+		t.Errorf("job name %q, want the name the mailer worker accepts", name)
+	}
+
+	var data mailJob
+	if err := json.Unmarshal([]byte(server.HGet(job, "data")), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.To != "guest@example.com" || data.TemplateId != "review_response" || data.Subject == "" {
+		t.Errorf("job data %+v", data)
+	}
+	if data.Params["reviewId"] != float64(12) || data.Params["response"] != "Thank you!" {
+		t.Errorf("template params %v", data.Params)
+	}
+}
+
+func TestMailerNotifierRejectsUnknownUser(t *testing.T) {
+	mailer, server := newTestMailer(t)
+
+	if err := mailer.Notify(responseNotification(8)); err == nil {
+		t.Error("expected an error for a user without an email address")
+	}
+	if server.Exists("bull:queue-mailer:wait") {
+		t.Error("a job was queued without a recipient")
+	}
+}
@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	TemplateReviewResponse = "review_response"
+)
+
+// Notification is addressed to a user rather than an email address; the notifier resolves how to reach them.
+// TemplateId and Params follow NotificationService's mailer templates. Key identifies the notification, so a
+// notifier can send it once however often it is handed over.
+type Notification struct {
+	Key        string
+	UserId     int64
+	Subject    string
+	TemplateId string
+	Params     map[string]any
+}
+
+// Notifier delivers notifications to users. Implementations must not block the request for long.
+type Notifier interface {
+	Notify(notification *Notification) error
+}
+
+// LogNotifier prints notifications instead of delivering them, for local development.
+type LogNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return &LogNotifier{}
+}
+
+func (l *LogNotifier) Notify(notification *Notification) error {
+	fmt.Println("Notification for user", notification.UserId, "-", notification.Subject, "template:", notification.TemplateId)
+	return nil
+}
+
+// InMemoryNotifier records notifications, for tests.
+type InMemoryNotifier struct {
+	mu   sync.Mutex
+	sent []*Notification
+}
+
+func NewInMemoryNotifier() *InMemoryNotifier {
+	return &InMemoryNotifier{}
+}
+
+func (m *InMemoryNotifier) Notify(notification *Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, notification)
+	return nil
+}
+
+func (m *InMemoryNotifier) Sent() []*Notification {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Notification{}, m.sent...)
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"ReviewService/controllers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HotelRouter struct {
+	hotelController *controllers.HotelController
+}
+
+func NewHotelRouter(_hotelController *controllers.HotelController) Router {
+	return &HotelRouter{
+		hotelController: _hotelController,
+	}
+}
+
+func (hr *HotelRouter) Register(r chi.Router) {
+	r.Get("/hotels/{id}/rating-summary", hr.hotelController.GetRatingSummary)
+	r.Get("/hotels/{id}/aspects", hr.hotelController.GetAspectSummary)
+}
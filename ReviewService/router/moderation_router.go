@@ -0,0 +1,36 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"ReviewService/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ModerationRouter struct {
+	moderationController *controllers.ModerationController
+	authMiddleware       func(http.Handler) http.Handler
+}
+
+func NewModerationRouter(_moderationController *controllers.ModerationController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ModerationRouter{
+		moderationController: _moderationController,
+		authMiddleware:       _authMiddleware,
+	}
+}
+
+func (mr *ModerationRouter) Register(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(mr.authMiddleware, middlewares.RequirePermission(services.ModeratorPermission))
+
+		r.Get("/moderation/reviews", mr.moderationController.ListReviews)
+		r.Get("/moderation/reported-reviews", mr.moderationController.ListReportedReviews)
+		r.With(middlewares.ReviewModerateRequestValidator).Post("/moderation/reviews/{id}/approve", mr.moderationController.ApproveReview)
+		r.With(middlewares.ReviewModerateRequestValidator).Post("/moderation/reviews/{id}/reject", mr.moderationController.RejectReview)
+		r.With(middlewares.ReviewModerateRequestValidator).Post("/moderation/reviews/{id}/hide", mr.moderationController.HideReview)
+		r.Get("/reviews/{id}/history", mr.moderationController.GetReviewHistory)
+		r.Get("/moderation/reviews/{id}/fraud-checks", mr.moderationController.GetFraudChecks)
+	})
+}
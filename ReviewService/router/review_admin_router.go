@@ -0,0 +1,32 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"ReviewService/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewAdminRouter struct {
+	reviewAdminController *controllers.ReviewAdminController
+	authMiddleware        func(http.Handler) http.Handler
+}
+
+func NewReviewAdminRouter(_reviewAdminController *controllers.ReviewAdminController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewAdminRouter{
+		reviewAdminController: _reviewAdminController,
+		authMiddleware:        _authMiddleware,
+	}
+}
+
+func (ar *ReviewAdminRouter) Register(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(ar.authMiddleware, middlewares.RequirePermission(services.AdminPermission))
+
+		r.Post("/admin/reviews/{id}/restore", ar.reviewAdminController.RestoreReview)
+		r.Delete("/admin/reviews/{id}", ar.reviewAdminController.PurgeReview)
+		r.Post("/admin/retention/purge", ar.reviewAdminController.PurgeExpired)
+	})
+}
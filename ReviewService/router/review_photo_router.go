@@ -0,0 +1,28 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewPhotoRouter struct {
+	reviewPhotoController *controllers.ReviewPhotoController
+	authMiddleware        func(http.Handler) http.Handler
+}
+
+func NewReviewPhotoRouter(_reviewPhotoController *controllers.ReviewPhotoController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewPhotoRouter{
+		reviewPhotoController: _reviewPhotoController,
+		authMiddleware:        _authMiddleware,
+	}
+}
+
+func (pr *ReviewPhotoRouter) Register(r chi.Router) {
+	r.With(pr.authMiddleware).Post("/reviews/{id}/photos", pr.reviewPhotoController.UploadPhotos)
+	r.Get("/reviews/{id}/photos", pr.reviewPhotoController.GetPhotos)
+	r.Get("/reviews/{id}/photos/{photoId}", pr.reviewPhotoController.GetPhoto)
+	r.Get("/reviews/{id}/photos/{photoId}/thumbnail", pr.reviewPhotoController.GetThumbnail)
+	r.With(pr.authMiddleware).Delete("/reviews/{id}/photos/{photoId}", pr.reviewPhotoController.DeletePhoto)
+}
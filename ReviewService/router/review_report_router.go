@@ -0,0 +1,25 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewReportRouter struct {
+	reviewReportController *controllers.ReviewReportController
+	authMiddleware         func(http.Handler) http.Handler
+}
+
+func NewReviewReportRouter(_reviewReportController *controllers.ReviewReportController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewReportRouter{
+		reviewReportController: _reviewReportController,
+		authMiddleware:         _authMiddleware,
+	}
+}
+
+func (rr *ReviewReportRouter) Register(r chi.Router) {
+	r.With(rr.authMiddleware, middlewares.ReviewReportRequestValidator).Post("/reviews/{id}/reports", rr.reviewReportController.Report)
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewResponseRouter struct {
+	reviewResponseController *controllers.ReviewResponseController
+	authMiddleware           func(http.Handler) http.Handler
+}
+
+func NewReviewResponseRouter(_reviewResponseController *controllers.ReviewResponseController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewResponseRouter{
+		reviewResponseController: _reviewResponseController,
+		authMiddleware:           _authMiddleware,
+	}
+}
+
+func (rr *ReviewResponseRouter) Register(r chi.Router) {
+	r.With(rr.authMiddleware, middlewares.ReviewResponseRequestValidator).Post("/reviews/{id}/response", rr.reviewResponseController.CreateResponse)
+	r.With(rr.authMiddleware, middlewares.ReviewResponseRequestValidator).Put("/reviews/{id}/response", rr.reviewResponseController.UpdateResponse)
+	r.With(rr.authMiddleware).Delete("/reviews/{id}/response", rr.reviewResponseController.DeleteResponse)
+}
@@ -3,30 +3,38 @@ package router
 import (
 	"ReviewService/controllers"
 	"ReviewService/middlewares"
+	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type ReviewRouter struct {
-	reviewController *controllers.ReviewController
+	reviewController      *controllers.ReviewController
+	authMiddleware        func(http.Handler) http.Handler
+	idempotencyMiddleware func(http.Handler) http.Handler
 }
 
-func NewReviewRouter(_reviewController *controllers.ReviewController) Router {
+func NewReviewRouter(_reviewController *controllers.ReviewController, _authMiddleware func(http.Handler) http.Handler, _idempotencyMiddleware func(http.Handler) http.Handler) Router {
 	return &ReviewRouter{
-		reviewController: _reviewController,
+		reviewController:      _reviewController,
+		authMiddleware:        _authMiddleware,
+		idempotencyMiddleware: _idempotencyMiddleware,
 	}
 }
 
 func (rr *ReviewRouter) Register(r chi.Router) {
 	// CRUD operations
-	r.With(middlewares.ReviewCreateRequestValidator).Post("/reviews", rr.reviewController.CreateReview)
+	r.With(rr.authMiddleware, rr.idempotencyMiddleware, middlewares.ReviewCreateRequestValidator).Post("/reviews", rr.reviewController.CreateReview)
 	r.Get("/reviews", rr.reviewController.GetAllReviews)
 	r.Get("/reviews/{id}", rr.reviewController.GetReviewById)
-	r.With(middlewares.ReviewUpdateRequestValidator).Put("/reviews/{id}", rr.reviewController.UpdateReview)
-	r.Delete("/reviews/{id}", rr.reviewController.DeleteReview)
+	r.With(rr.authMiddleware, middlewares.ReviewUpdateRequestValidator).Put("/reviews/{id}", rr.reviewController.UpdateReview)
+	r.With(rr.authMiddleware).Delete("/reviews/{id}", rr.reviewController.DeleteReview)
 
 	// Filter operations
 	r.Get("/reviews/user", rr.reviewController.GetReviewsByUserId)
 	r.Get("/reviews/hotel", rr.reviewController.GetReviewsByHotelId)
 	r.Get("/reviews/booking", rr.reviewController.GetReviewsByBookingId)
+
+	// Search
+	r.Get("/reviews/search", rr.reviewController.SearchReviews)
 }
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"ReviewService/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewTransferRouter struct {
+	reviewTransferController *controllers.ReviewTransferController
+	authMiddleware           func(http.Handler) http.Handler
+}
+
+func NewReviewTransferRouter(_reviewTransferController *controllers.ReviewTransferController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewTransferRouter{
+		reviewTransferController: _reviewTransferController,
+		authMiddleware:           _authMiddleware,
+	}
+}
+
+func (tr *ReviewTransferRouter) Register(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(tr.authMiddleware, middlewares.RequirePermission(services.AdminPermission))
+
+		r.Post("/admin/reviews/import", tr.reviewTransferController.ImportReviews)
+		r.Get("/admin/reviews/export", tr.reviewTransferController.ExportReviews)
+	})
+}
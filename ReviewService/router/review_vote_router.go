@@ -0,0 +1,25 @@
+package router
+
+import (
+	"ReviewService/controllers"
+	"ReviewService/middlewares"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewVoteRouter struct {
+	reviewVoteController *controllers.ReviewVoteController
+	authMiddleware       func(http.Handler) http.Handler
+}
+
+func NewReviewVoteRouter(_reviewVoteController *controllers.ReviewVoteController, _authMiddleware func(http.Handler) http.Handler) Router {
+	return &ReviewVoteRouter{
+		reviewVoteController: _reviewVoteController,
+		authMiddleware:       _authMiddleware,
+	}
+}
+
+func (vr *ReviewVoteRouter) Register(r chi.Router) {
+	r.With(vr.authMiddleware, middlewares.ReviewVoteRequestValidator).Put("/reviews/{id}/vote", vr.reviewVoteController.Vote)
+}
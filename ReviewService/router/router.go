@@ -11,7 +11,7 @@ type Router interface {
 	Register(r chi.Router)
 }
 
-func SetupRouter(ReviewRouter Router) *chi.Mux {
+func SetupRouter(routers ...Router) *chi.Mux {
 
 	chiRouter := chi.NewRouter()
 
@@ -22,7 +22,9 @@ func SetupRouter(ReviewRouter Router) *chi.Mux {
 
 	chiRouter.Get("/ping", controllers.PingHandler)
 
-	ReviewRouter.Register(chiRouter)
+	for _, router := range routers {
+		router.Register(chiRouter)
+	}
 
 	return chiRouter
 
@@ -0,0 +1,26 @@
+package search
+
+import "ReviewService/models"
+
+const DefaultSearchLimit = 20
+
+// SearchQuery selects reviews whose comment matches Text, optionally within one hotel.
+type SearchQuery struct {
+	Text    string
+	HotelId *int64
+	Limit   int
+}
+
+// SearchHit is a matching review with its relevance score. Scores are only comparable within one search.
+type SearchHit struct {
+	Review *models.Review
+	Score  float64
+}
+
+// ReviewSearchIndex finds active, approved reviews by the words in their comments, most relevant first.
+// Index and Remove are called after every create, update and delete so the index never serves stale reviews.
+type ReviewSearchIndex interface {
+	Index(review *models.Review) error
+	Remove(reviewId int64) error
+	Search(query SearchQuery) ([]*SearchHit, error)
+}
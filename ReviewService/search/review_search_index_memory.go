@@ -0,0 +1,139 @@
+package search
+
+import (
+	"ReviewService/models"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// InMemoryReviewSearchIndex is an in-process inverted index ranked with BM25, for tests and local development.
+type InMemoryReviewSearchIndex struct {
+	mu          sync.RWMutex
+	reviews     map[int64]*models.Review
+	lengths     map[int64]int
+	postings    map[string]map[int64]int // term -> review id -> term frequency
+	totalLength int
+}
+
+func NewInMemoryReviewSearchIndex() *InMemoryReviewSearchIndex {
+	return &InMemoryReviewSearchIndex{
+		reviews:  map[int64]*models.Review{},
+		lengths:  map[int64]int{},
+		postings: map[string]map[int64]int{},
+	}
+}
+
+func (m *InMemoryReviewSearchIndex) Index(review *models.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(review.Id)
+	if review.DeletedAt != nil || review.ModerationStatus != models.ModerationApproved {
+		return nil
+	}
+
+	terms := Tokenize(review.Comment)
+	for _, term := range terms {
+		if m.postings[term] == nil {
+			m.postings[term] = map[int64]int{}
+		}
+		m.postings[term][review.Id]++
+	}
+
+	copied := *review
+	m.reviews[review.Id] = &copied
+	m.lengths[review.Id] = len(terms)
+	m.totalLength += len(terms)
+	return nil
+}
+
+func (m *InMemoryReviewSearchIndex) Remove(reviewId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(reviewId)
+	return nil
+}
+
+func (m *InMemoryReviewSearchIndex) remove(reviewId int64) {
+	review, ok := m.reviews[reviewId]
+	if !ok {
+		return
+	}
+
+	for _, term := range Tokenize(review.Comment) {
+		delete(m.postings[term], reviewId)
+		if len(m.postings[term]) == 0 {
+			delete(m.postings, term)
+		}
+	}
+
+	m.totalLength -= m.lengths[reviewId]
+	delete(m.lengths, reviewId)
+	delete(m.reviews, reviewId)
+}
+
+func (m *InMemoryReviewSearchIndex) Search(query SearchQuery) ([]*SearchHit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.reviews) == 0 {
+		return nil, nil
+	}
+
+	docCount := float64(len(m.reviews))
+	avgLength := float64(m.totalLength) / docCount
+
+	scores := map[int64]float64{}
+	for _, term := range uniqueTerms(Tokenize(query.Text)) {
+		postings := m.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + (docCount-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+		for reviewId, tf := range postings {
+			if query.HotelId != nil && m.reviews[reviewId].HotelId != *query.HotelId {
+				continue
+			}
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(m.lengths[reviewId])/avgLength)
+			scores[reviewId] += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + norm)
+		}
+	}
+
+	hits := make([]*SearchHit, 0, len(scores))
+	for reviewId, score := range scores {
+		copied := *m.reviews[reviewId]
+		hits = append(hits, &SearchHit{Review: &copied, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Review.Id > hits[j].Review.Id
+	})
+
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			out = append(out, term)
+		}
+	}
+	return out
+}
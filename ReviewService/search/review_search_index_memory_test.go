@@ -0,0 +1,119 @@
+package search
+
+import (
+	"ReviewService/models"
+	"testing"
+)
+
+func approvedReview(id int64, hotelId int64, comment string) *models.Review {
+	return &models.Review{Id: id, HotelId: hotelId, Comment: comment, ModerationStatus: models.ModerationApproved}
+}
+
+func hitIds(t *testing.T, index *InMemoryReviewSearchIndex, query SearchQuery) []int64 {
+	t.Helper()
+	hits, err := index.Search(query)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	ids := make([]int64, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Review.Id)
+	}
+	return ids
+}
+
+func assertIds(t *testing.T, got []int64, want ...int64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got reviews %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got reviews %v, want %v", got, want)
+		}
+	}
+}
+
+func TestInMemorySearchRanksWithBM25(t *testing.T) {
+	index := NewInMemoryReviewSearchIndex()
+	index.Index(approvedReview(1, 100, "The pool was nice"))
+	index.Index(approvedReview(2, 100, "Pool, pool and more pool. Best pool in town"))
+	index.Index(approvedReview(3, 100, "Breakfast was cold but the staff fixed it quickly and apologised for the wait"))
+	index.Index(approvedReview(4, 100, "Quiet rooms, friendly staff"))
+	index.Index(approvedReview(5, 200, "Another hotel with a pool"))
+
+	// More occurrences of the term rank higher
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "pool", HotelId: ptr(int64(100))}), 2, 1)
+
+	// With the same term frequency, the shorter comment ranks higher
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "staff"}), 4, 3)
+
+	// A rarer term counts for more than a common one
+	hits, err := index.Search(SearchQuery{Text: "pool breakfast"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	scores := map[int64]float64{}
+	for _, hit := range hits {
+		scores[hit.Review.Id] = hit.Score
+	}
+	if scores[3] <= scores[1] {
+		t.Errorf("breakfast (1 review) scored %.3f, not above pool (3 reviews) at %.3f", scores[3], scores[1])
+	}
+
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "pool", HotelId: ptr(int64(200))}), 5)
+	if got := hitIds(t, index, SearchQuery{Text: "pool", Limit: 1}); len(got) != 1 {
+		t.Errorf("limit 1 returned %d hits", len(got))
+	}
+	if got := hitIds(t, index, SearchQuery{Text: "the and"}); len(got) != 0 {
+		t.Errorf("a query of stop words matched %v", got)
+	}
+}
+
+func TestInMemorySearchFollowsUpdatesAndDeletes(t *testing.T) {
+	index := NewInMemoryReviewSearchIndex()
+	index.Index(approvedReview(1, 100, "Noisy street at night"))
+	index.Index(approvedReview(2, 100, "Noisy air conditioning"))
+
+	// Editing the comment replaces its terms
+	index.Index(approvedReview(1, 100, "Quiet room facing the garden"))
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "noisy"}), 2)
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "garden"}), 1)
+
+	index.Remove(2)
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "noisy"}))
+
+	// A soft-deleted review is dropped when re-indexed
+	deleted := approvedReview(1, 100, "Quiet room facing the garden")
+	deletedAt := "2025-09-01 10:00:00"
+	deleted.DeletedAt = &deletedAt
+	index.Index(deleted)
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "garden"}))
+
+	if index.totalLength != 0 || len(index.postings) != 0 {
+		t.Errorf("empty index keeps %d terms and length %d", len(index.postings), index.totalLength)
+	}
+}
+
+func TestInMemorySearchHidesUnapprovedReviews(t *testing.T) {
+	index := NewInMemoryReviewSearchIndex()
+	for id, status := range map[int64]string{
+		1: models.ModerationApproved,
+		2: models.ModerationPending,
+		3: models.ModerationRejected,
+		4: models.ModerationHidden,
+	} {
+		index.Index(&models.Review{Id: id, HotelId: 100, Comment: "Lovely balcony view", ModerationStatus: status})
+	}
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "balcony"}), 1)
+
+	// Taking down an approved review removes it, approving it again brings it back
+	index.Index(&models.Review{Id: 1, HotelId: 100, Comment: "Lovely balcony view", ModerationStatus: models.ModerationHidden})
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "balcony"}))
+	index.Index(approvedReview(2, 100, "Lovely balcony view"))
+	assertIds(t, hitIds(t, index, SearchQuery{Text: "balcony"}), 2)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"database/sql"
+	"fmt"
+)
+
+// MySQLReviewSearchIndex searches the ft_reviews_comment FULLTEXT index. MySQL maintains the index
+// itself as rows change, so Index and Remove have nothing to do.
+type MySQLReviewSearchIndex struct {
+	db *sql.DB
+}
+
+func NewMySQLReviewSearchIndex(_db *sql.DB) ReviewSearchIndex {
+	return &MySQLReviewSearchIndex{
+		db: _db,
+	}
+}
+
+func (m *MySQLReviewSearchIndex) Index(review *models.Review) error {
+	return nil
+}
+
+func (m *MySQLReviewSearchIndex) Remove(reviewId int64) error {
+	return nil
+}
+
+func (m *MySQLReviewSearchIndex) Search(query SearchQuery) ([]*SearchHit, error) {
+	sqlQuery := "SELECT " + db.ReviewColumns + `, MATCH(comment) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+	FROM reviews
+	WHERE MATCH(comment) AGAINST (? IN NATURAL LANGUAGE MODE) AND deleted_at IS NULL AND moderation_status = 'approved'`
+	args := []any{query.Text, query.Text}
+
+	if query.HotelId != nil {
+		sqlQuery += " AND hotel_id = ?"
+		args = append(args, *query.HotelId)
+	}
+	sqlQuery += " ORDER BY score DESC, id DESC LIMIT ?"
+	args = append(args, query.Limit)
+
+	rows, err := m.db.Query(sqlQuery, args...)
+	if err != nil {
+		fmt.Println("Error searching reviews:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*SearchHit
+	for rows.Next() {
+		hit := &SearchHit{}
+		review, err := db.ScanReview(rows, &hit.Score)
+		if err != nil {
+			fmt.Println("Error scanning search hit:", err)
+			return nil, err
+		}
+		hit.Review = review
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error with rows:", err)
+		return nil, err
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+	snippetRadius  = 80 // characters of context kept on each side of the first match
+)
+
+// stopWords are skipped when indexing and searching, like MySQL's default InnoDB stopword list.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+// Tokenize lowercases text and splits it into words, dropping stop words.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// Snippet returns the part of text around the first occurrence of a query term, with every term occurrence highlighted.
+// Text that matches no term is returned from the start. The text is HTML-escaped so the snippet is safe to render.
+func Snippet(text string, query string) string {
+	terms := map[string]bool{}
+	for _, term := range Tokenize(query) {
+		terms[term] = true
+	}
+
+	runes := []rune(text)
+	words := wordSpans(runes)
+
+	start := 0
+	for _, w := range words {
+		if terms[strings.ToLower(string(runes[w[0]:w[1]]))] {
+			start = max(0, w[0]-snippetRadius)
+			break
+		}
+	}
+	end := min(len(runes), start+2*snippetRadius)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, w := range words {
+		if w[0] < start || w[1] > end || !terms[strings.ToLower(string(runes[w[0]:w[1]]))] {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w[0]])))
+		b.WriteString(HighlightStart)
+		b.WriteString(html.EscapeString(string(runes[w[0]:w[1]])))
+		b.WriteString(HighlightEnd)
+		pos = w[1]
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// wordSpans returns the [start, end) rune offsets of every word in text, split the same way as Tokenize.
+func wordSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range runes {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
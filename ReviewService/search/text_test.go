@@ -0,0 +1,55 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := strings.Join(Tokenize("The Wi-Fi was GREAT, room 101 is fine!"), " ")
+	if want := "wi fi great room 101 fine"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"highlights every match", "Clean room, clean bathroom", "clean", "<mark>Clean</mark> room, <mark>clean</mark> bathroom"},
+		{"whole words only", "Cleanliness was fine", "clean", "Cleanliness was fine"},
+		{"escapes the text", "<b>Great</b> pool & spa", "pool", "&lt;b&gt;Great&lt;/b&gt; <mark>pool</mark> &amp; spa"},
+		{"ignores stop words", "The bed and the view", "the view", "The bed and the <mark>view</mark>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(tt.text, tt.query); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippetCentersOnFirstMatch(t *testing.T) {
+	text := strings.Repeat("filler ", 40) + "the breakfast was superb " + strings.Repeat("filler ", 40)
+
+	got := Snippet(text, "breakfast")
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet in the middle of a long text is not elided on both sides: %q", got)
+	}
+	if !strings.Contains(got, "<mark>breakfast</mark>") {
+		t.Errorf("snippet misses the match: %q", got)
+	}
+	if n := len([]rune(strings.NewReplacer(HighlightStart, "", HighlightEnd, "", "…", "").Replace(got))); n > 2*snippetRadius {
+		t.Errorf("snippet has %d characters, want at most %d", n, 2*snippetRadius)
+	}
+
+	// Without a match the snippet starts at the beginning
+	if got := Snippet(text, "pool"); !strings.HasPrefix(got, "filler") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet without a match: %q", got)
+	}
+}
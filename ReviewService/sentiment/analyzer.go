@@ -0,0 +1,214 @@
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Version identifies the lexicon and rules. Bump it whenever either changes so stored analyses are redone by the backfill.
+const Version = 2
+
+const (
+	negationScalar = -0.74 // a negated word keeps some of its strength with the opposite sign ("not great" is mildly negative)
+	boosterStep    = 0.293 // added to or taken from a word's strength by a preceding intensifier or dampener
+	negationWindow = 3     // words before a sentiment word that are checked for a negator
+	normalizeAlpha = 15    // controls how quickly the summed valence approaches ±1
+)
+
+// NeutralBoundary is how far from zero a score must be to count as positive or negative.
+const NeutralBoundary = 0.05
+
+const (
+	LabelPositive = "positive"
+	LabelNeutral  = "neutral"
+	LabelNegative = "negative"
+)
+
+// Analysis is the result of analyzing one comment. Scores are in [-1, 1].
+type Analysis struct {
+	Score float64
+	// Aspects maps each aspect the comment mentions to the sentiment of the clauses that mention it
+	Aspects map[string]float64
+}
+
+// Analyzer scores text with a word lexicon, adjusting for negation, intensifiers and contrast ("but"),
+// and tags the aspects the text talks about. It needs no external service and is safe for concurrent use.
+type Analyzer struct {
+	lexicon  map[string]float64
+	aspects  map[string][][]string // aspect -> keyword token sequences
+	negators map[string]bool
+	boosters map[string]float64
+}
+
+func NewAnalyzer() *Analyzer {
+	aspects := map[string][][]string{}
+	for _, aspect := range Aspects {
+		for _, keyword := range aspectKeywords[aspect] {
+			aspects[aspect] = append(aspects[aspect], tokenize(keyword))
+		}
+	}
+	return &Analyzer{
+		lexicon:  lexicon,
+		aspects:  aspects,
+		negators: negators,
+		boosters: boosters,
+	}
+}
+
+// Analyze scores the text and the aspects it mentions. Empty text scores 0 with no aspects.
+func (a *Analyzer) Analyze(text string) *Analysis {
+	analysis := &Analysis{Aspects: map[string]float64{}}
+	aspectSums := map[string]float64{}
+
+	total := 0.0
+	for _, sentence := range splitSentences(text) {
+		for _, clause := range splitClauses(tokenize(sentence)) {
+			sum := a.valence(clause.tokens)
+			total += sum * clause.weight
+			for aspect := range a.mentions(clause.tokens) {
+				aspectSums[aspect] += sum
+			}
+		}
+	}
+
+	analysis.Score = normalize(total)
+	for aspect, sum := range aspectSums {
+		analysis.Aspects[aspect] = normalize(sum)
+	}
+	return analysis
+}
+
+// Label names the polarity of a score.
+func Label(score float64) string {
+	switch {
+	case score >= NeutralBoundary:
+		return LabelPositive
+	case score <= -NeutralBoundary:
+		return LabelNegative
+	default:
+		return LabelNeutral
+	}
+}
+
+// valence sums the strength of every sentiment word in a clause.
+func (a *Analyzer) valence(tokens []string) float64 {
+	sum := 0.0
+	for i, token := range tokens {
+		value, ok := a.lexicon[token]
+		if !ok {
+			continue
+		}
+
+		if i > 0 {
+			if step, ok := a.boosters[tokens[i-1]]; ok {
+				// The step moves the word away from zero, or towards it for a dampener, whatever the word's sign
+				value += step * math.Copysign(1, value)
+			}
+		}
+		for j := max(0, i-negationWindow); j < i; j++ {
+			if a.negators[tokens[j]] {
+				value *= negationScalar
+				break
+			}
+		}
+		sum += value
+	}
+	return sum
+}
+
+// mentions returns the aspects whose keywords appear in the clause.
+func (a *Analyzer) mentions(tokens []string) map[string]bool {
+	found := map[string]bool{}
+	for aspect, keywords := range a.aspects {
+		for _, keyword := range keywords {
+			if containsSequence(tokens, keyword) {
+				found[aspect] = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+type clause struct {
+	tokens []string
+	weight float64
+}
+
+// contrastWords split a sentence into clauses. What follows the contrast usually carries the writer's view,
+// so it counts more towards the overall score than what precedes it.
+var contrastWords = map[string]bool{"but": true, "however": true, "although": true, "though": true, "yet": true}
+
+func splitClauses(tokens []string) []clause {
+	var clauses []clause
+	start := 0
+	for i, token := range tokens {
+		if contrastWords[token] {
+			clauses = append(clauses, clause{tokens: tokens[start:i], weight: 1})
+			start = i + 1
+		}
+	}
+	clauses = append(clauses, clause{tokens: tokens[start:], weight: 1})
+
+	if len(clauses) > 1 {
+		for i := range clauses[:len(clauses)-1] {
+			clauses[i].weight = 0.5
+		}
+		clauses[len(clauses)-1].weight = 1.5
+	}
+	return clauses
+}
+
+func splitSentences(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == ';' || r == '\n'
+	})
+}
+
+// tokenize lowercases text and splits it into words. Apostrophes and hyphens inside a word are dropped rather
+// than splitting it, so "didn't" becomes "didnt" and "wi-fi" becomes "wifi".
+func tokenize(text string) []string {
+	var tokens []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			tokens = append(tokens, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '’' || r == '-':
+			// joined to the surrounding word
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func containsSequence(tokens []string, sequence []string) bool {
+	for i := 0; i+len(sequence) <= len(tokens); i++ {
+		match := true
+		for j, word := range sequence {
+			if tokens[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize maps an unbounded valence sum into [-1, 1], rounded to three decimals.
+func normalize(sum float64) float64 {
+	score := sum / math.Sqrt(sum*sum+normalizeAlpha)
+	return math.Round(score*1000) / 1000
+}
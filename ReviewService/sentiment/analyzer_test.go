@@ -0,0 +1,118 @@
+package sentiment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeScore(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", LabelNeutral},
+		{"no sentiment words", "We stayed two nights in March", LabelNeutral},
+		{"positive word", "The room was good", LabelPositive},
+		{"negative word", "The room was bad", LabelNegative},
+		{"negated positive", "The room was not good", LabelNegative},
+		{"negated negative", "The room was not bad", LabelPositive},
+		{"contraction negator", "We didn't enjoy it", LabelNegative},
+		{"negator within the window", "Nothing was pleasant", LabelNegative},
+		{"negator outside the window", "No towels on arrival and then a lovely dinner", LabelPositive},
+		{"later clause outweighs the contrast", "The room was great but the staff were rude", LabelNegative},
+		{"contrast the other way", "The staff were rude but the room was great", LabelPositive},
+		{"sentences add up", "Awful check-in. Great room. Great breakfast. Great view.", LabelPositive},
+	}
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := analyzer.Analyze(tt.text).Score
+			if score < -1 || score > 1 {
+				t.Fatalf("score %v is outside [-1, 1]", score)
+			}
+			if got := Label(score); got != tt.want {
+				t.Errorf("score %v is %s, want %s", score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBoostersAndDampeners(t *testing.T) {
+	analyzer := NewAnalyzer()
+	score := func(text string) float64 { return analyzer.Analyze(text).Score }
+
+	tests := []struct {
+		name     string
+		stronger string
+		weaker   string
+	}{
+		{"booster strengthens a positive word", "very good", "good"},
+		{"booster strengthens a negative word", "bad", "very bad"},
+		{"dampener softens a positive word", "good", "slightly good"},
+		{"dampener softens a negative word", "slightly bad", "bad"},
+		{"half dampener softens less", "quite good", "slightly good"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if score(tt.stronger) <= score(tt.weaker) {
+				t.Errorf("%q scores %v, want more than %q at %v", tt.stronger, score(tt.stronger), tt.weaker, score(tt.weaker))
+			}
+		})
+	}
+
+	// A dampened word keeps its polarity
+	if score("slightly good") <= 0 || score("slightly bad") >= 0 {
+		t.Errorf("slightly good %v, slightly bad %v, want the polarity kept", score("slightly good"), score("slightly bad"))
+	}
+	// A negated word keeps only part of its strength
+	if score("not good") >= 0 || score("not good") <= score("bad") {
+		t.Errorf("not good %v, want mildly negative, above bad at %v", score("not good"), score("bad"))
+	}
+}
+
+func TestAnalyzeAspects(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]string // aspect -> label
+	}{
+		{"no aspects", "Lovely stay", map[string]string{}},
+		{"single aspect", "The wifi was terrible.", map[string]string{AspectWifi: LabelNegative}},
+		{"multi-word keyword", "The front desk was so helpful", map[string]string{AspectStaff: LabelPositive}},
+		{"hyphenated keyword", "Wi-Fi kept dropping, annoying", map[string]string{AspectWifi: LabelNegative}},
+		{"clauses are tagged separately", "Breakfast was delicious but the parking was expensive",
+			map[string]string{AspectBreakfast: LabelPositive, AspectParking: LabelNegative, AspectValue: LabelNegative}},
+		{"sentences are tagged separately", "Spotless bathroom. The bed was uncomfortable.",
+			map[string]string{AspectBathroom: LabelPositive, AspectCleanliness: LabelPositive, AspectComfort: LabelNegative}},
+		{"mentions across sentences add up", "The staff were rude. The staff were rude again.", map[string]string{AspectStaff: LabelNegative}},
+	}
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for aspect, score := range analyzer.Analyze(tt.text).Aspects {
+				got[aspect] = Label(score)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeAspectSumsLikeScore(t *testing.T) {
+	analysis := NewAnalyzer().Analyze("The staff were rude. The staff were rude again.")
+	once := NewAnalyzer().Analyze("The staff were rude.")
+	if analysis.Aspects[AspectStaff] >= once.Aspects[AspectStaff] {
+		t.Errorf("two rude mentions score %v, want below one at %v", analysis.Aspects[AspectStaff], once.Aspects[AspectStaff])
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("Didn't love the Wi-Fi… but GREAT view!")
+	want := []string{"didnt", "love", "the", "wifi", "but", "great", "view"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
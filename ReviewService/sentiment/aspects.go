@@ -0,0 +1,43 @@
+package sentiment
+
+const (
+	AspectCleanliness = "cleanliness"
+	AspectNoise       = "noise"
+	AspectWifi        = "wifi"
+	AspectStaff       = "staff"
+	AspectLocation    = "location"
+	AspectBreakfast   = "breakfast"
+	AspectComfort     = "comfort"
+	AspectBathroom    = "bathroom"
+	AspectValue       = "value"
+	AspectParking     = "parking"
+	AspectClimate     = "climate"
+)
+
+// Aspects lists every aspect the analyzer tags, in display order.
+var Aspects = []string{
+	AspectCleanliness, AspectNoise, AspectWifi, AspectStaff, AspectLocation, AspectBreakfast,
+	AspectComfort, AspectBathroom, AspectValue, AspectParking, AspectClimate,
+}
+
+// aspectKeywords are the words and phrases that mark a clause as being about an aspect.
+var aspectKeywords = map[string][]string{
+	AspectCleanliness: {"clean", "cleaner", "cleanliness", "cleaning", "dirty", "spotless", "filthy", "dust", "dusty",
+		"stain", "stains", "stained", "hygiene", "tidy", "messy", "mold", "mould", "moldy", "mouldy", "smelly", "smelled",
+		"musty", "cockroach", "cockroaches", "bugs", "bedbugs"},
+	AspectNoise: {"noise", "noisy", "loud", "quiet", "quieter", "soundproof", "soundproofed", "soundproofing",
+		"peaceful", "thin walls", "traffic"},
+	AspectWifi: {"wifi", "internet", "wireless", "connection", "signal", "wi fi"},
+	AspectStaff: {"staff", "host", "hosts", "reception", "receptionist", "front desk", "service", "employees",
+		"manager", "concierge", "housekeeping"},
+	AspectLocation: {"location", "located", "neighborhood", "neighbourhood", "area", "walking distance", "central",
+		"downtown", "beach"},
+	AspectBreakfast: {"breakfast", "buffet", "coffee", "restaurant", "food"},
+	AspectComfort: {"bed", "beds", "mattress", "pillow", "pillows", "comfortable", "uncomfortable", "comfy",
+		"sheets", "cramped", "spacious"},
+	AspectBathroom: {"bathroom", "shower", "toilet", "towels", "sink", "bathtub"},
+	AspectValue: {"price", "prices", "value", "expensive", "cheap", "overpriced", "affordable", "bargain", "money",
+		"worth", "ripoff"},
+	AspectParking: {"parking", "garage", "car park"},
+	AspectClimate: {"air conditioning", "aircon", "ac", "heating", "heater", "temperature", "freezing", "stuffy"},
+}
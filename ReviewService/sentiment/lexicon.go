@@ -0,0 +1,51 @@
+package sentiment
+
+// lexicon holds the strength of sentiment words on a -4 (most negative) to +4 (most positive) scale,
+// in the spirit of VADER, with the vocabulary of hotel reviews. Words are stored as tokenize produces them.
+var lexicon = map[string]float64{
+	// positive
+	"amazing": 2.8, "awesome": 3.1, "beautiful": 2.9, "best": 3.2, "brilliant": 2.8, "clean": 1.9, "cleaner": 1.6,
+	"comfortable": 2.0, "comfy": 1.9, "convenient": 1.7, "cosy": 1.9, "cozy": 1.9, "courteous": 2.0, "delicious": 2.7,
+	"delightful": 2.9, "enjoy": 2.2, "enjoyed": 2.3, "excellent": 3.2, "exceptional": 3.0, "fabulous": 3.1,
+	"fantastic": 3.0, "fast": 1.2, "fine": 0.8, "flawless": 2.8, "fresh": 1.3, "friendly": 2.2, "generous": 2.3,
+	"good": 1.9, "gorgeous": 3.0, "great": 3.1, "happy": 2.7, "helpful": 1.9, "hospitable": 2.2, "impeccable": 3.0,
+	"impressed": 2.1, "kind": 2.0, "liked": 1.8, "lovely": 2.8, "love": 3.2, "loved": 2.9, "modern": 1.0,
+	"nice": 1.8, "peaceful": 2.2, "perfect": 2.7, "pleasant": 2.3, "polite": 1.8, "professional": 1.5, "quiet": 1.2,
+	"quick": 1.1, "recommend": 1.9, "recommended": 1.8, "relaxing": 2.2, "reliable": 1.8, "responsive": 1.6,
+	"satisfied": 1.9, "smooth": 1.3, "spacious": 1.8, "spotless": 2.6, "stunning": 3.0, "superb": 3.1,
+	"tasty": 2.1, "thank": 1.5, "thanks": 1.9, "tidy": 1.6, "welcoming": 2.1, "wonderful": 2.7, "worth": 1.5,
+	"affordable": 1.5, "bargain": 1.6, "charming": 2.4, "efficient": 1.6, "attentive": 2.0, "accommodating": 1.9,
+	"cheap": 0.4, "central": 1.0, "soundproof": 1.2, "soundproofed": 1.2, "free": 1.0, "strong": 0.8,
+
+	// negative
+	"annoying": -1.9, "appalling": -3.1, "awful": -3.0, "bad": -2.5, "broken": -1.9, "complain": -1.8,
+	"complained": -1.9, "cramped": -1.6, "creaky": -1.2, "dated": -1.0, "dirty": -2.0, "disappointed": -2.3,
+	"disappointing": -2.2, "disgusting": -3.0, "dreadful": -2.9, "dusty": -1.5, "expensive": -1.2, "filthy": -2.8,
+	"gross": -2.1, "horrible": -2.9, "hostile": -2.5, "loud": -1.6, "mediocre": -1.3, "mess": -1.7,
+	"messy": -1.5, "mold": -2.0, "mould": -2.0, "moldy": -2.3, "mouldy": -2.3, "musty": -1.6, "nasty": -2.6,
+	"noisy": -1.7, "overpriced": -2.1, "poor": -2.1, "problem": -1.7, "problems": -1.7, "rude": -2.4,
+	"shabby": -1.8, "slow": -1.3, "smelly": -2.0, "smelled": -1.2, "stained": -1.7, "stains": -1.6, "terrible": -2.9,
+	"tiny": -0.8, "uncomfortable": -1.9, "unfriendly": -2.1, "unhelpful": -2.0, "unreliable": -1.9, "unsafe": -2.4,
+	"weak": -1.3, "worst": -3.1, "worse": -2.1, "hate": -2.7, "hated": -3.2, "ignored": -1.8, "avoid": -1.8,
+	"cockroach": -2.6, "cockroaches": -2.6, "bugs": -2.0, "bedbugs": -3.0, "leak": -1.5, "leaking": -1.6,
+	"unusable": -2.3, "useless": -2.2, "lacking": -1.3, "ripoff": -2.8, "scam": -3.0, "unprofessional": -2.2,
+	"freezing": -1.4, "stuffy": -1.4, "thin": -0.6, "disconnected": -1.5, "drops": -0.9, "dropped": -1.0,
+}
+
+// negators flip the sign of a sentiment word that follows within negationWindow words.
+var negators = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "nothing": true, "nobody": true, "nowhere": true,
+	"neither": true, "nor": true, "without": true, "hardly": true, "barely": true, "cannot": true,
+	"isnt": true, "wasnt": true, "arent": true, "werent": true, "dont": true, "doesnt": true, "didnt": true,
+	"cant": true, "couldnt": true, "wont": true, "wouldnt": true, "shouldnt": true, "aint": true,
+	"hasnt": true, "havent": true, "hadnt": true,
+}
+
+// boosters strengthen (positive step) or soften (negative step) the sentiment word right after them.
+var boosters = map[string]float64{
+	"very": boosterStep, "really": boosterStep, "extremely": boosterStep, "incredibly": boosterStep,
+	"absolutely": boosterStep, "totally": boosterStep, "so": boosterStep, "super": boosterStep,
+	"exceptionally": boosterStep, "truly": boosterStep, "completely": boosterStep, "utterly": boosterStep,
+	"slightly": -boosterStep, "somewhat": -boosterStep, "fairly": -boosterStep, "quite": -boosterStep / 2,
+	"bit": -boosterStep, "little": -boosterStep, "kinda": -boosterStep, "rather": -boosterStep / 2,
+}
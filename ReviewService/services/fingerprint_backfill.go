@@ -0,0 +1,31 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+)
+
+// FingerprintBackfillServiceImpl fingerprints reviews stored before near-duplicate detection existed, so new
+// reviews are compared with them too.
+type FingerprintBackfillServiceImpl struct {
+	reviewRepository db.ReviewRepository
+}
+
+func NewFingerprintBackfillService(_reviewRepository db.ReviewRepository) BackfillService {
+	return &FingerprintBackfillServiceImpl{
+		reviewRepository: _reviewRepository,
+	}
+}
+
+func (s *FingerprintBackfillServiceImpl) Backfill(batchSize int) (*BackfillReport, error) {
+	backfill := &reviewBackfill{
+		name:  "Fingerprint",
+		done:  "fingerprinted",
+		fetch: s.reviewRepository.GetUnfingerprinted,
+		apply: func(review *models.Review) (bool, error) {
+			applyFingerprint(review)
+			return s.reviewRepository.SetFingerprint(review)
+		},
+	}
+	return backfill.run(batchSize)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"ReviewService/models"
+	"fmt"
+)
+
+// HotelResponderPermission lets a caller respond to reviews of any hotel. AuthInGo grants it to admins only.
+// Hotel staff instead get "hotel:<id>:respond" for each hotel they are assigned to, issued at login.
+const HotelResponderPermission = "hotel:respond"
+
+// HotelAuthorizer decides who may act on behalf of a hotel.
+type HotelAuthorizer interface {
+	CanRespond(user *models.AuthUser, hotelId int64) bool
+}
+
+// ClaimsHotelAuthorizer authorizes from the permissions carried in the caller's token.
+type ClaimsHotelAuthorizer struct{}
+
+func NewClaimsHotelAuthorizer() HotelAuthorizer {
+	return &ClaimsHotelAuthorizer{}
+}
+
+func (c *ClaimsHotelAuthorizer) CanRespond(user *models.AuthUser, hotelId int64) bool {
+	return user.HasPermission(HotelResponderPermission) || user.HasPermission(fmt.Sprintf("hotel:%d:respond", hotelId))
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"ReviewService/models"
+	"testing"
+)
+
+func TestClaimsHotelAuthorizerCanRespond(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		want        bool
+	}{
+		{"admin", []string{HotelResponderPermission}, true},
+		{"staff of the hotel", []string{"hotel:12:respond"}, true},
+		{"staff of another hotel", []string{"hotel:13:respond"}, false},
+		{"hotel ID prefix", []string{"hotel:1:respond"}, false},
+		{"guest", []string{"review:moderate"}, false},
+		{"no permissions", nil, false},
+	}
+
+	authorizer := NewClaimsHotelAuthorizer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.AuthUser{Id: 7, Permissions: tt.permissions}
+			if got := authorizer.CanRespond(user, 12); got != tt.want {
+				t.Errorf("CanRespond = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if authorizer.CanRespond(nil, 12) {
+		t.Error("CanRespond allowed an unauthenticated caller")
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/sentiment"
+	"ReviewService/utils"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// dominantShare is the share of an aspect's mentions one polarity needs before the aspect is called mostly that.
+const dominantShare = 0.6
+
+// RatingPrior is the belief a hotel's score starts from before it has reviews.
+// Weight is the number of reviews the prior counts as, so hotels with few reviews stay close to Mean.
+type RatingPrior struct {
+	Mean   float64
+	Weight float64
+}
+
+type HotelRatingService interface {
+	GetRatingSummary(hotelId string) (*dto.RatingSummaryResponseDTO, error)
+	GetAspectSummary(hotelId string) (*dto.AspectSummaryResponseDTO, error)
+}
+
+type HotelRatingServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	prior            RatingPrior
+}
+
+func NewHotelRatingService(_reviewRepository db.ReviewRepository, _prior RatingPrior) HotelRatingService {
+	return &HotelRatingServiceImpl{
+		reviewRepository: _reviewRepository,
+		prior:            _prior,
+	}
+}
+
+func (h *HotelRatingServiceImpl) GetRatingSummary(hotelId string) (*dto.RatingSummaryResponseDTO, error) {
+	fmt.Println("Fetching rating summary in HotelRatingService")
+
+	hotelIdInt, err := strconv.ParseInt(hotelId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing hotel ID:", err)
+		return nil, utils.NewBadRequestError("invalid hotel ID")
+	}
+
+	summary, err := h.reviewRepository.GetHotelRatingSummary(hotelIdInt)
+	if err != nil {
+		fmt.Println("Error fetching rating summary:", err)
+		return nil, err
+	}
+
+	histogram := map[string]int64{}
+	for i, count := range summary.Histogram {
+		histogram[strconv.Itoa(i+1)] = count
+	}
+
+	categories := map[string]dto.RatingStatsDTO{}
+	for _, category := range models.ReviewCategories {
+		aggregate := summary.Categories[category]
+		categories[category] = dto.RatingStatsDTO{Count: aggregate.Count, Mean: mean(aggregate.Sum, aggregate.Count)}
+	}
+
+	bayesian := (h.prior.Mean*h.prior.Weight + float64(summary.Sum)) / (h.prior.Weight + float64(summary.Count))
+	if h.prior.Weight+float64(summary.Count) == 0 {
+		bayesian = 0
+	}
+
+	return &dto.RatingSummaryResponseDTO{
+		HotelId:       summary.HotelId,
+		Count:         summary.Count,
+		Mean:          mean(summary.Sum, summary.Count),
+		Histogram:     histogram,
+		BayesianScore: roundRating(bayesian),
+		PriorMean:     h.prior.Mean,
+		PriorWeight:   h.prior.Weight,
+		Last30Days:    dto.RatingStatsDTO{Count: summary.Count30d, Mean: mean(summary.Sum30d, summary.Count30d)},
+		Last90Days:    dto.RatingStatsDTO{Count: summary.Count90d, Mean: mean(summary.Sum90d, summary.Count90d)},
+		Categories:    categories,
+		Languages:     summary.Languages,
+	}, nil
+}
+
+// GetAspectSummary reports how often each aspect comes up in the hotel's reviews and how it is talked about,
+// most mentioned first. Aspects nobody mentioned are left out.
+func (h *HotelRatingServiceImpl) GetAspectSummary(hotelId string) (*dto.AspectSummaryResponseDTO, error) {
+	fmt.Println("Fetching aspect summary in HotelRatingService")
+
+	hotelIdInt, err := strconv.ParseInt(hotelId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing hotel ID:", err)
+		return nil, utils.NewBadRequestError("invalid hotel ID")
+	}
+
+	summary, err := h.reviewRepository.GetHotelAspectSummary(hotelIdInt, sentiment.NeutralBoundary)
+	if err != nil {
+		fmt.Println("Error fetching aspect summary:", err)
+		return nil, err
+	}
+
+	aspects := []dto.AspectSummaryDTO{}
+	for _, aspect := range sentiment.Aspects {
+		aggregate, ok := summary.Aspects[aspect]
+		if !ok || aggregate.Mentions == 0 || summary.AnalyzedCount == 0 {
+			continue
+		}
+
+		rate := float64(aggregate.Mentions) / float64(summary.AnalyzedCount)
+		meanSentiment := math.Round(aggregate.SentimentSum/float64(aggregate.Mentions)*1000) / 1000
+		tone := aspectTone(aggregate)
+		aspects = append(aspects, dto.AspectSummaryDTO{
+			Aspect:        aspect,
+			Mentions:      aggregate.Mentions,
+			MentionRate:   math.Round(rate*1000) / 1000,
+			Positive:      aggregate.Positive,
+			Neutral:       aggregate.Neutral,
+			Negative:      aggregate.Negative,
+			MeanSentiment: &meanSentiment,
+			Tone:          tone,
+			Summary:       fmt.Sprintf("mentioned %s in %.0f%% of reviews, %s", aspect, rate*100, tone),
+		})
+	}
+	// Stable, so aspects mentioned equally often keep their display order
+	sort.SliceStable(aspects, func(i, j int) bool { return aspects[i].Mentions > aspects[j].Mentions })
+
+	return &dto.AspectSummaryResponseDTO{
+		HotelId:       summary.HotelId,
+		AnalyzedCount: summary.AnalyzedCount,
+		Aspects:       aspects,
+	}, nil
+}
+
+// aspectTone describes how an aspect's mentions lean, e.g. "mostly negative".
+func aspectTone(aggregate models.AspectAggregate) string {
+	mentions := float64(aggregate.Mentions)
+	switch {
+	case float64(aggregate.Positive) >= dominantShare*mentions:
+		return "mostly " + sentiment.LabelPositive
+	case float64(aggregate.Negative) >= dominantShare*mentions:
+		return "mostly " + sentiment.LabelNegative
+	case float64(aggregate.Neutral) >= dominantShare*mentions:
+		return "mostly " + sentiment.LabelNeutral
+	default:
+		return "mixed"
+	}
+}
+
+// mean returns nil for an empty set so clients can tell "no reviews" apart from a zero average.
+func mean(sum int64, count int64) *float64 {
+	if count == 0 {
+		return nil
+	}
+	m := roundRating(float64(sum) / float64(count))
+	return &m
+}
+
+func roundRating(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// moderationTransitions lists, per decision, the statuses a review may be in when the decision is made.
+var moderationTransitions = map[string][]string{
+	models.ModerationApproved: {models.ModerationPending, models.ModerationRejected, models.ModerationHidden},
+	models.ModerationRejected: {models.ModerationPending, models.ModerationHidden},
+	models.ModerationHidden:   {models.ModerationApproved},
+}
+
+type ModerationService interface {
+	ListReviews(status string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error)
+	Approve(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error)
+	Reject(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error)
+	Hide(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error)
+	GetHistory(id string) ([]*dto.ReviewRevisionDTO, error)
+	GetFraudChecks(id string) ([]*dto.FraudCheckDTO, error)
+	ListReported(status string, query *dto.ReviewListQuery) (*dto.ReportedReviewPageDTO, error)
+}
+
+type ModerationServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	reportRepository db.ReviewReportRepository
+	searchIndex      search.ReviewSearchIndex
+}
+
+func NewModerationService(_reviewRepository db.ReviewRepository, _reportRepository db.ReviewReportRepository, _searchIndex search.ReviewSearchIndex) ModerationService {
+	return &ModerationServiceImpl{
+		reviewRepository: _reviewRepository,
+		reportRepository: _reportRepository,
+		searchIndex:      _searchIndex,
+	}
+}
+
+// ListReviews returns the moderation queue for a status, oldest first unless another sort is requested.
+func (m *ModerationServiceImpl) ListReviews(status string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error) {
+	fmt.Println("Fetching moderation queue in ModerationService")
+
+	if status == "" {
+		status = models.ModerationPending
+	}
+	if err := validateModerationStatus(status); err != nil {
+		return nil, err
+	}
+
+	if query.Sort == "" {
+		query.Sort = string(models.ReviewSortOldest)
+	}
+	filter, err := newReviewFilter(query, models.ReviewSortMostReported)
+	if err != nil {
+		return nil, err
+	}
+	filter.ModerationStatus = status
+
+	return listReviewPage(m.reviewRepository, filter, query.IncludeTotal)
+}
+
+// ListReported returns the reviews with open reports, most reported first unless another sort is requested.
+// An empty status lists reviews in any status, so both reviews still published and those already hidden by reports appear.
+func (m *ModerationServiceImpl) ListReported(status string, query *dto.ReviewListQuery) (*dto.ReportedReviewPageDTO, error) {
+	fmt.Println("Fetching reported reviews in ModerationService")
+
+	if status != "" {
+		if err := validateModerationStatus(status); err != nil {
+			return nil, err
+		}
+	}
+
+	if query.Sort == "" {
+		query.Sort = string(models.ReviewSortMostReported)
+	}
+	filter, err := newReviewFilter(query, models.ReviewSortMostReported)
+	if err != nil {
+		return nil, err
+	}
+	filter.ModerationStatus = status
+	filter.Reported = true
+
+	page, err := listReviewPage(m.reviewRepository, filter, query.IncludeTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(page.Reviews))
+	for i, review := range page.Reviews {
+		ids[i] = review.Id
+	}
+	reasons, err := m.reportRepository.GetOpenReasonCounts(ids)
+	if err != nil {
+		fmt.Println("Error counting review reports:", err)
+		return nil, err
+	}
+
+	reported := &dto.ReportedReviewPageDTO{Reviews: []*dto.ReportedReviewDTO{}, NextCursor: page.NextCursor, TotalCount: page.TotalCount}
+	for _, review := range page.Reviews {
+		counts := reasons[review.Id]
+		if counts == nil {
+			counts = map[string]int64{}
+		}
+		reported.Reviews = append(reported.Reviews, &dto.ReportedReviewDTO{Review: review, Reasons: counts})
+	}
+	return reported, nil
+}
+
+func validateModerationStatus(status string) error {
+	switch status {
+	case models.ModerationPending, models.ModerationApproved, models.ModerationRejected, models.ModerationHidden:
+		return nil
+	default:
+		return utils.NewBadRequestError("status must be one of pending, approved, rejected, hidden")
+	}
+}
+
+func (m *ModerationServiceImpl) Approve(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error) {
+	return m.decide(id, moderator, models.ModerationApproved, payload.Reason)
+}
+
+func (m *ModerationServiceImpl) Reject(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error) {
+	if strings.TrimSpace(payload.Reason) == "" {
+		return nil, utils.NewBadRequestError("a reason is required to reject a review")
+	}
+	return m.decide(id, moderator, models.ModerationRejected, payload.Reason)
+}
+
+func (m *ModerationServiceImpl) Hide(id string, moderator *models.AuthUser, payload *dto.ModerateReviewRequestDTO) (*models.Review, error) {
+	if strings.TrimSpace(payload.Reason) == "" {
+		return nil, utils.NewBadRequestError("a reason is required to hide a review")
+	}
+	return m.decide(id, moderator, models.ModerationHidden, payload.Reason)
+}
+
+func (m *ModerationServiceImpl) decide(id string, moderator *models.AuthUser, status string, reason string) (*models.Review, error) {
+	fmt.Println("Moderating review in ModerationService:", status)
+
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := m.reviewRepository.GetByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+
+	allowed := false
+	for _, from := range moderationTransitions[status] {
+		allowed = allowed || review.ModerationStatus == from
+	}
+	if !allowed {
+		return nil, utils.NewConflictError(fmt.Sprintf("a %s review cannot be %s", review.ModerationStatus, status), review)
+	}
+
+	var reasonPtr *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		reasonPtr = &reason
+	}
+
+	// The transition is checked again by the update, so a decision racing another one or a delete loses with a 409
+	updated, err := m.reviewRepository.SetModerationStatus(idInt, status, moderationTransitions[status], reasonPtr, moderator.Id)
+	if errors.Is(err, db.ErrConcurrentChange) {
+		return nil, utils.NewConflictError(fmt.Sprintf("review with ID %d was changed or deleted while being moderated, reload it and try again", idInt), nil)
+	}
+	if err != nil {
+		fmt.Println("Error moderating review:", err)
+		return nil, err
+	}
+
+	if err := m.searchIndex.Index(updated); err != nil {
+		fmt.Println("Error indexing review for search:", err)
+	}
+
+	fmt.Println("Review moderated successfully:", updated.Id, status)
+	return updated, nil
+}
+
+// GetHistory returns every revision of a review, oldest first, including for reviews that were since deleted.
+func (m *ModerationServiceImpl) GetHistory(id string) ([]*dto.ReviewRevisionDTO, error) {
+	fmt.Println("Fetching review history in ModerationService")
+
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	revisions, err := m.reviewRepository.GetRevisions(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review history:", err)
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+
+	history := make([]*dto.ReviewRevisionDTO, len(revisions))
+	for i, revision := range revisions {
+		changed := []string{}
+		if i > 0 {
+			previous := revisions[i-1]
+			if revision.Comment != previous.Comment {
+				changed = append(changed, "comment")
+			}
+			if revision.Rating != previous.Rating {
+				changed = append(changed, "rating")
+			}
+			if !maps.Equal(revision.CategoryRatings, previous.CategoryRatings) {
+				changed = append(changed, "category_ratings")
+			}
+		}
+
+		history[i] = &dto.ReviewRevisionDTO{
+			Revision:        revision.Revision,
+			EditorId:        revision.EditorId,
+			Comment:         revision.Comment,
+			Rating:          revision.Rating,
+			CategoryRatings: revision.CategoryRatings,
+			Changed:         changed,
+			CreatedAt:       revision.CreatedAt,
+		}
+	}
+	return history, nil
+}
+
+// GetFraudChecks returns the fraud checks of a review. Reviews written before fraud scoring, or imported, have none.
+func (m *ModerationServiceImpl) GetFraudChecks(id string) ([]*dto.FraudCheckDTO, error) {
+	fmt.Println("Fetching fraud checks in ModerationService")
+
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	checks, err := m.reviewRepository.GetFraudChecks(idInt)
+	if err != nil {
+		fmt.Println("Error fetching fraud checks:", err)
+		return nil, err
+	}
+
+	result := make([]*dto.FraudCheckDTO, len(checks))
+	for i, check := range checks {
+		result[i] = &dto.FraudCheckDTO{
+			Score:     check.Score,
+			Decision:  check.Decision,
+			Signals:   check.Signals,
+			CreatedAt: check.CreatedAt,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/utils"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// moderatedReviews serves a single review and records moderation decisions on it. When changedTo is set the review
+// is moved to that status, or deleted for "deleted", between the service reading it and the decision being stored.
+type moderatedReviews struct {
+	photoReviews
+	changedTo string
+}
+
+func (m *moderatedReviews) SetModerationStatus(id int64, status string, fromStatuses []string, reason *string, moderatorId int64) (*models.Review, error) {
+	if m.changedTo == "deleted" || m.changedTo != "" && !slices.Contains(fromStatuses, m.changedTo) {
+		return nil, db.ErrConcurrentChange
+	}
+	updated := *m.review
+	updated.ModerationStatus, updated.ModerationReason, updated.ModeratedBy = status, reason, &moderatorId
+	m.review = &updated
+	return &updated, nil
+}
+
+func TestModerationTransitions(t *testing.T) {
+	statuses := []string{models.ModerationPending, models.ModerationApproved, models.ModerationRejected, models.ModerationHidden}
+	allowed := map[string]map[string]bool{
+		"approve": {models.ModerationPending: true, models.ModerationRejected: true, models.ModerationHidden: true},
+		"reject":  {models.ModerationPending: true, models.ModerationHidden: true},
+		"hide":    {models.ModerationApproved: true},
+	}
+	moderator := &models.AuthUser{Id: 99, Permissions: []string{ModeratorPermission}}
+	payload := &dto.ModerateReviewRequestDTO{Reason: "checked"}
+
+	for decision, from := range allowed {
+		for _, status := range statuses {
+			t.Run(decision+" "+status, func(t *testing.T) {
+				reviews := &moderatedReviews{photoReviews: photoReviews{review: &models.Review{Id: 1, UserId: 10, ModerationStatus: status}}}
+				service := NewModerationService(reviews, nil, search.NewInMemoryReviewSearchIndex())
+
+				var err error
+				switch decision {
+				case "approve":
+					_, err = service.Approve("1", moderator, payload)
+				case "reject":
+					_, err = service.Reject("1", moderator, payload)
+				case "hide":
+					_, err = service.Hide("1", moderator, payload)
+				}
+
+				if from[status] {
+					if err != nil {
+						t.Errorf("got %v, want the decision to apply", err)
+					}
+				} else if utils.StatusFromError(err, 0) != http.StatusConflict {
+					t.Errorf("got %v, want a 409", err)
+				}
+			})
+		}
+	}
+}
+
+func TestModerationLosesRaceWithConcurrentChange(t *testing.T) {
+	moderator := &models.AuthUser{Id: 99, Permissions: []string{ModeratorPermission}}
+	payload := &dto.ModerateReviewRequestDTO{Reason: "checked"}
+
+	tests := []struct {
+		name      string
+		changedTo string
+		wantErr   bool
+	}{
+		{"rejected by another moderator", models.ModerationRejected, false},
+		{"approved by another moderator", models.ModerationApproved, true},
+		{"deleted by its author", "deleted", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews := &moderatedReviews{photoReviews: photoReviews{review: &models.Review{Id: 1, UserId: 10, ModerationStatus: models.ModerationPending}}, changedTo: tt.changedTo}
+			service := NewModerationService(reviews, nil, search.NewInMemoryReviewSearchIndex())
+
+			_, err := service.Approve("1", moderator, payload)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("got %v, want the approval to apply", err)
+				}
+			} else if utils.StatusFromError(err, 0) != http.StatusConflict {
+				t.Errorf("got %v, want a 409", err)
+			}
+		})
+	}
+}
+
+func TestAwaitsTakedownReview(t *testing.T) {
+	requeued := "edited after being hidden"
+	prescreened := "held for review: contains a link"
+
+	tests := []struct {
+		name   string
+		review *models.Review
+		want   bool
+	}{
+		{"requeued after a takedown", &models.Review{ModerationStatus: models.ModerationPending, ModerationReason: &requeued, TakedownRequeued: true}, true},
+		// The flag decides, not the reason text
+		{"reason text alone", &models.Review{ModerationStatus: models.ModerationPending, ModerationReason: &requeued}, false},
+		{"held by the pre-screen", &models.Review{ModerationStatus: models.ModerationPending, ModerationReason: &prescreened}, false},
+		{"awaiting approval", &models.Review{ModerationStatus: models.ModerationPending}, false},
+		// Reports on a pending review do not keep it from being re-screened
+		{"reported", &models.Review{ModerationStatus: models.ModerationPending, ReportCount: 2}, false},
+		{"approved again", &models.Review{ModerationStatus: models.ModerationApproved, TakedownRequeued: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := awaitsTakedownReview(tt.review); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
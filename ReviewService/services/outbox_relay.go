@@ -0,0 +1,110 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/events"
+	"context"
+	"fmt"
+	"time"
+)
+
+type OutboxRelayConfig struct {
+	BatchSize      int           // events published per batch
+	PublishTimeout time.Duration // per event
+	KeepPublished  time.Duration // published events are deleted once older than this
+}
+
+// OutboxRelay publishes the events stored in the outbox to the broker, oldest first. An event is marked
+// published only after the broker accepts it, so a crash in between publishes it again; brokers drop the
+// repeat by event ID. A failed event stops the batch, so later events are not published ahead of it.
+type OutboxRelay interface {
+	// RelayBatch publishes up to one batch and returns how many events were published.
+	RelayBatch() (int, error)
+	// Run relays on every tick until ctx is cancelled.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type OutboxRelayImpl struct {
+	outboxRepository db.OutboxRepository
+	broker           events.Broker
+	config           OutboxRelayConfig
+}
+
+func NewOutboxRelay(_outboxRepository db.OutboxRepository, _broker events.Broker, _config OutboxRelayConfig) OutboxRelay {
+	if _config.BatchSize <= 0 {
+		_config.BatchSize = 100
+	}
+	if _config.PublishTimeout <= 0 {
+		_config.PublishTimeout = 5 * time.Second
+	}
+	return &OutboxRelayImpl{
+		outboxRepository: _outboxRepository,
+		broker:           _broker,
+		config:           _config,
+	}
+}
+
+func (o *OutboxRelayImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.drain(ctx)
+			if o.config.KeepPublished > 0 {
+				if _, err := o.outboxRepository.DeletePublishedBefore(time.Now().Add(-o.config.KeepPublished)); err != nil {
+					fmt.Println("Error cleaning up published outbox events:", err)
+				}
+			}
+		}
+	}
+}
+
+// drain keeps relaying while batches come back full, so a backlog is not spread over many ticks.
+func (o *OutboxRelayImpl) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := o.RelayBatch()
+		if err != nil {
+			fmt.Println("Error relaying outbox events:", err)
+		}
+		if err != nil || published < o.config.BatchSize {
+			return
+		}
+	}
+}
+
+func (o *OutboxRelayImpl) RelayBatch() (int, error) {
+	pending, err := o.outboxRepository.GetUnpublished(o.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), o.config.PublishTimeout)
+		err := o.broker.Publish(ctx, &events.Message{
+			Id:      event.EventId,
+			Type:    event.EventType,
+			Payload: event.Payload,
+		})
+		cancel()
+
+		if err != nil {
+			if markErr := o.outboxRepository.MarkFailed(event.Id, err.Error()); markErr != nil {
+				fmt.Println("Error recording outbox failure:", markErr)
+			}
+			return published, fmt.Errorf("event %s: %w", event.EventId, err)
+		}
+
+		if err := o.outboxRepository.MarkPublished(event.Id); err != nil {
+			// The event is published again on the next run and dropped by the broker as a duplicate
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"ReviewService/events"
+	"ReviewService/models"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryOutbox is an OutboxRepository on a slice. markPublishedErr fails the next MarkPublished call.
+type memoryOutbox struct {
+	mu               sync.Mutex
+	events           []*models.OutboxEvent
+	markPublishedErr error
+}
+
+func newMemoryOutbox(count int) *memoryOutbox {
+	outbox := &memoryOutbox{}
+	for i := 1; i <= count; i++ {
+		outbox.events = append(outbox.events, &models.OutboxEvent{
+			Id:        int64(i),
+			EventId:   fmt.Sprintf("event-%d", i),
+			EventType: "review.created",
+			Payload:   []byte(fmt.Sprintf(`{"reviewId":%d}`, i)),
+		})
+	}
+	return outbox
+}
+
+func (m *memoryOutbox) GetUnpublished(limit int) ([]*models.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending := []*models.OutboxEvent{}
+	for _, event := range m.events {
+		if event.PublishedAt == nil && len(pending) < limit {
+			copied := *event
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryOutbox) MarkPublished(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.markPublishedErr; err != nil {
+		m.markPublishedErr = nil
+		return err
+	}
+	now := time.Now().UTC().Format(time.DateTime)
+	m.events[id-1].PublishedAt = &now
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(id int64, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[id-1].Attempts++
+	m.events[id-1].LastError = &reason
+	return nil
+}
+
+func (m *memoryOutbox) DeletePublishedBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// flakyBroker forwards to an InMemoryBroker, failing the event IDs in failing and counting every attempt.
+type flakyBroker struct {
+	*events.InMemoryBroker
+	failing  map[string]bool
+	attempts []string
+}
+
+func (b *flakyBroker) Publish(ctx context.Context, message *events.Message) error {
+	b.attempts = append(b.attempts, message.Id)
+	if b.failing[message.Id] {
+		return errors.New("broker unavailable")
+	}
+	return b.InMemoryBroker.Publish(ctx, message)
+}
+
+func publishedIds(broker *events.InMemoryBroker) []string {
+	ids := []string{}
+	for _, message := range broker.Messages() {
+		ids = append(ids, message.Id)
+	}
+	return ids
+}
+
+func TestRelayBatchPublishesInOrder(t *testing.T) {
+	outbox := newMemoryOutbox(5)
+	broker := events.NewInMemoryBroker()
+	relay := NewOutboxRelay(outbox, broker, OutboxRelayConfig{BatchSize: 3})
+
+	if published, err := relay.RelayBatch(); err != nil || published != 3 {
+		t.Fatalf("first batch published %d, %v, want 3", published, err)
+	}
+	if published, err := relay.RelayBatch(); err != nil || published != 2 {
+		t.Fatalf("second batch published %d, %v, want 2", published, err)
+	}
+	if published, err := relay.RelayBatch(); err != nil || published != 0 {
+		t.Fatalf("empty batch published %d, %v, want 0", published, err)
+	}
+
+	want := []string{"event-1", "event-2", "event-3", "event-4", "event-5"}
+	if got := publishedIds(broker); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+	if string(broker.Messages()[0].Payload) != `{"reviewId":1}` || broker.Messages()[0].Type != "review.created" {
+		t.Errorf("got message %+v", broker.Messages()[0])
+	}
+}
+
+func TestRelayBatchStopsAtFailedEvent(t *testing.T) {
+	outbox := newMemoryOutbox(4)
+	broker := &flakyBroker{InMemoryBroker: events.NewInMemoryBroker(), failing: map[string]bool{"event-2": true}}
+	relay := NewOutboxRelay(outbox, broker, OutboxRelayConfig{BatchSize: 10})
+
+	published, err := relay.RelayBatch()
+	if err == nil || published != 1 {
+		t.Fatalf("got %d, %v, want 1 published and an error", published, err)
+	}
+	if !slices.Equal(broker.attempts, []string{"event-1", "event-2"}) {
+		t.Errorf("attempted %v, want nothing after the failed event", broker.attempts)
+	}
+	if failed := outbox.events[1]; failed.Attempts != 1 || failed.LastError == nil || failed.PublishedAt != nil {
+		t.Errorf("failed event recorded as %+v", failed)
+	}
+
+	// Once the broker recovers, the failed event goes out before the ones behind it
+	delete(broker.failing, "event-2")
+	if published, err := relay.RelayBatch(); err != nil || published != 3 {
+		t.Fatalf("retry published %d, %v, want 3", published, err)
+	}
+	want := []string{"event-1", "event-2", "event-3", "event-4"}
+	if got := publishedIds(broker.InMemoryBroker); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestRelayBatchRepublishesAfterMarkPublishedFailure(t *testing.T) {
+	outbox := newMemoryOutbox(2)
+	outbox.markPublishedErr = errors.New("connection lost")
+	broker := &flakyBroker{InMemoryBroker: events.NewInMemoryBroker()}
+	relay := NewOutboxRelay(outbox, broker, OutboxRelayConfig{BatchSize: 10})
+
+	if published, err := relay.RelayBatch(); err == nil || published != 0 {
+		t.Fatalf("got %d, %v, want 0 published and an error", published, err)
+	}
+	if published, err := relay.RelayBatch(); err != nil || published != 2 {
+		t.Fatalf("retry published %d, %v, want 2", published, err)
+	}
+
+	// The broker saw event-1 twice and dropped the repeat by its ID
+	if !slices.Equal(broker.attempts, []string{"event-1", "event-1", "event-2"}) {
+		t.Errorf("attempted %v", broker.attempts)
+	}
+	if got := publishedIds(broker.InMemoryBroker); !slices.Equal(got, []string{"event-1", "event-2"}) {
+		t.Errorf("published %v, want each event once", got)
+	}
+	for _, event := range outbox.events {
+		if event.PublishedAt == nil {
+			t.Errorf("event %s is not marked published", event.EventId)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"ReviewService/clients"
+	db "ReviewService/db/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RatingSyncConfig struct {
+	BatchSize       int           // hotels synced per batch
+	MaxAttempts     int           // pushes per hotel before it is left for a later run
+	RetryBackoff    time.Duration // wait before the second attempt, doubled for every later one
+	FailureDelay    time.Duration // a hotel whose pushes all failed is skipped this long, doubled for every run that fails again
+	MaxFailureDelay time.Duration
+}
+
+// RatingSyncService keeps HotelService's rating and rating_count in step with the reviews stored here.
+// Creating, updating or deleting a review clears its is_synced flag; the sync recomputes every affected
+// hotel from its active reviews and sets the flag again only once HotelService has accepted the result.
+type RatingSyncService interface {
+	// SyncBatch syncs up to one batch of hotels and returns how many were synced.
+	SyncBatch() (int, error)
+	// Run syncs on every tick until ctx is cancelled.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type RatingSyncServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	hotelClient      clients.HotelClient
+	config           RatingSyncConfig
+}
+
+func NewRatingSyncService(_reviewRepository db.ReviewRepository, _hotelClient clients.HotelClient, _config RatingSyncConfig) RatingSyncService {
+	if _config.BatchSize <= 0 {
+		_config.BatchSize = 50
+	}
+	if _config.MaxAttempts <= 0 {
+		_config.MaxAttempts = 3
+	}
+	if _config.FailureDelay <= 0 {
+		_config.FailureDelay = time.Minute
+	}
+	if _config.MaxFailureDelay <= 0 {
+		_config.MaxFailureDelay = time.Hour
+	}
+	return &RatingSyncServiceImpl{
+		reviewRepository: _reviewRepository,
+		hotelClient:      _hotelClient,
+		config:           _config,
+	}
+}
+
+func (s *RatingSyncServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.drain(ctx)
+		}
+	}
+}
+
+// drain keeps syncing batches while they come back full, so a backlog is not spread over many ticks.
+func (s *RatingSyncServiceImpl) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		synced, err := s.SyncBatch()
+		if err != nil {
+			fmt.Println("Error syncing hotel ratings:", err)
+		}
+		if err != nil || synced < s.config.BatchSize {
+			return
+		}
+	}
+}
+
+func (s *RatingSyncServiceImpl) SyncBatch() (int, error) {
+	hotelIds, err := s.reviewRepository.GetUnsyncedHotelIds(s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	var errs []error
+	for _, hotelId := range hotelIds {
+		if err := s.syncHotel(hotelId); err != nil {
+			errs = append(errs, fmt.Errorf("hotel %d: %w", hotelId, err))
+			continue
+		}
+		synced++
+	}
+
+	if synced > 0 {
+		fmt.Println("Synced ratings for hotels:", synced)
+	}
+	return synced, errors.Join(errs...)
+}
+
+func (s *RatingSyncServiceImpl) syncHotel(hotelId int64) error {
+	rating, err := s.reviewRepository.GetHotelRating(hotelId)
+	if err != nil {
+		return err
+	}
+
+	average := roundRating(rating.Average)
+	categories := map[string]float64{}
+	for category, aggregate := range rating.Categories {
+		if aggregate.Count > 0 {
+			categories[category] = roundRating(float64(aggregate.Sum) / float64(aggregate.Count))
+		}
+	}
+
+	err = s.pushWithRetry(hotelId, average, rating.Count, categories)
+	if errors.Is(err, clients.ErrHotelNotFound) {
+		// Retrying cannot help a hotel HotelService does not know, so its reviews are marked synced anyway
+		fmt.Println("Hotel not found in HotelService, skipping rating sync:", hotelId)
+	} else if err != nil {
+		if recordErr := s.reviewRepository.RecordHotelSyncFailure(hotelId, err, s.config.FailureDelay, s.config.MaxFailureDelay); recordErr != nil {
+			return errors.Join(err, recordErr)
+		}
+		return err
+	}
+
+	_, err = s.reviewRepository.MarkHotelSynced(hotelId, rating.Unsynced)
+	return err
+}
+
+func (s *RatingSyncServiceImpl) pushWithRetry(hotelId int64, rating float64, ratingCount int64, categoryRatings map[string]float64) error {
+	backoff := s.config.RetryBackoff
+
+	var err error
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		err = s.hotelClient.UpdateRating(hotelId, rating, ratingCount, categoryRatings)
+		if err == nil || errors.Is(err, clients.ErrHotelNotFound) {
+			return err
+		}
+
+		if attempt < s.config.MaxAttempts {
+			fmt.Println("Error pushing hotel rating, retrying:", hotelId, err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"ReviewService/clients"
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// syncReviews serves fixed hotel aggregates and records which review versions were marked synced
+// and which hotels were held back after a failed push.
+type syncReviews struct {
+	db.ReviewRepository
+	ratings  map[int64]*models.HotelRating
+	marked   map[int64][]models.ReviewVersion
+	failures map[int64]int
+}
+
+func newSyncReviews(ratings ...*models.HotelRating) *syncReviews {
+	s := &syncReviews{ratings: map[int64]*models.HotelRating{}, marked: map[int64][]models.ReviewVersion{}, failures: map[int64]int{}}
+	for _, rating := range ratings {
+		s.ratings[rating.HotelId] = rating
+	}
+	return s
+}
+
+func (s *syncReviews) GetUnsyncedHotelIds(limit int) ([]int64, error) {
+	var ids []int64
+	for id := range s.ratings {
+		if _, done := s.marked[id]; !done && s.failures[id] == 0 && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *syncReviews) GetHotelRating(hotelId int64) (*models.HotelRating, error) {
+	return s.ratings[hotelId], nil
+}
+
+func (s *syncReviews) MarkHotelSynced(hotelId int64, reviews []models.ReviewVersion) (int64, error) {
+	s.marked[hotelId] = reviews
+	delete(s.failures, hotelId)
+	return int64(len(reviews)), nil
+}
+
+func (s *syncReviews) RecordHotelSyncFailure(hotelId int64, syncErr error, retryDelay time.Duration, maxRetryDelay time.Duration) error {
+	s.failures[hotelId]++
+	return nil
+}
+
+// failingHotelClient fails every push for the hotels in fail and passes the others on.
+type failingHotelClient struct {
+	clients.HotelClient
+	fail   map[int64]bool
+	pushes int
+}
+
+func (f *failingHotelClient) UpdateRating(hotelId int64, rating float64, ratingCount int64, categoryRatings map[string]float64) error {
+	f.pushes++
+	if f.fail[hotelId] {
+		return errors.New("connection refused")
+	}
+	return f.HotelClient.UpdateRating(hotelId, rating, ratingCount, categoryRatings)
+}
+
+func hotelRating(hotelId int64, average float64, count int64, unsynced ...int64) *models.HotelRating {
+	rating := &models.HotelRating{HotelId: hotelId, Average: average, Count: count, Categories: map[string]models.RatingAggregate{}}
+	for _, id := range unsynced {
+		rating.Unsynced = append(rating.Unsynced, models.ReviewVersion{Id: id, UpdatedAt: "2025-09-01 10:00:00"})
+	}
+	return rating
+}
+
+func TestRatingSyncPushesAggregateAndMarksReadVersions(t *testing.T) {
+	rating := hotelRating(1, 4.2567, 3, 10, 11)
+	rating.Categories["cleanliness"] = models.RatingAggregate{Count: 2, Sum: 9}
+	reviews := newSyncReviews(rating)
+	hotels := clients.NewInMemoryHotelClient(1)
+
+	synced, err := NewRatingSyncService(reviews, hotels, RatingSyncConfig{}).SyncBatch()
+	if err != nil || synced != 1 {
+		t.Fatalf("got %d synced, error %v", synced, err)
+	}
+
+	pushed := hotels.GetRating(1)
+	want := &clients.HotelRating{Rating: 4.26, RatingCount: 3, CategoryRatings: map[string]float64{"cleanliness": 4.5}}
+	if !reflect.DeepEqual(pushed, want) {
+		t.Errorf("pushed %+v, want %+v", pushed, want)
+	}
+	if !reflect.DeepEqual(reviews.marked[1], rating.Unsynced) {
+		t.Errorf("marked %v, want exactly the versions read with the aggregate %v", reviews.marked[1], rating.Unsynced)
+	}
+}
+
+func TestRatingSyncMarksUnknownHotelSynced(t *testing.T) {
+	reviews := newSyncReviews(hotelRating(2, 5, 1, 20))
+	hotels := clients.NewInMemoryHotelClient()
+
+	if _, err := NewRatingSyncService(reviews, hotels, RatingSyncConfig{}).SyncBatch(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews.marked[2]) != 1 || reviews.failures[2] != 0 {
+		t.Errorf("marked %v with %d failures, want the reviews marked synced", reviews.marked[2], reviews.failures[2])
+	}
+}
+
+func TestRatingSyncHoldsBackFailingHotel(t *testing.T) {
+	reviews := newSyncReviews(hotelRating(1, 4, 2, 10), hotelRating(2, 3, 1, 20))
+	hotels := &failingHotelClient{HotelClient: clients.NewInMemoryHotelClient(1, 2), fail: map[int64]bool{1: true}}
+	service := NewRatingSyncService(reviews, hotels, RatingSyncConfig{MaxAttempts: 2, RetryBackoff: time.Millisecond})
+
+	synced, err := service.SyncBatch()
+	if err == nil || synced != 1 {
+		t.Fatalf("got %d synced, error %v, want hotel 2 synced and an error for hotel 1", synced, err)
+	}
+	if _, ok := reviews.marked[1]; ok {
+		t.Error("reviews of the failing hotel were marked synced")
+	}
+	if reviews.failures[1] != 1 || len(reviews.marked[2]) != 1 {
+		t.Errorf("failures %v marked %v, want hotel 1 held back and hotel 2 synced", reviews.failures, reviews.marked)
+	}
+	if hotels.pushes != 3 {
+		t.Errorf("%d pushes, want two attempts for hotel 1 and one for hotel 2", hotels.pushes)
+	}
+
+	// While held back the failing hotel is not picked up again
+	if synced, err := service.SyncBatch(); synced != 0 || err != nil || hotels.pushes != 3 {
+		t.Errorf("second batch synced %d with error %v after %d pushes, want nothing to do", synced, err, hotels.pushes)
+	}
+}
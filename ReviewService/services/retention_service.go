@@ -0,0 +1,135 @@
+package services
+
+import (
+	"ReviewService/blobstore"
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RetentionConfig struct {
+	RetentionDays     int           // soft-deleted reviews older than this are purged
+	BatchSize         int           // reviews, or idempotency keys, purged per transaction
+	DryRun            bool          // scheduled runs only log what they would purge
+	IdempotencyKeyTTL time.Duration // idempotency keys unused this long are deleted; zero keeps them
+}
+
+// RetentionService permanently removes reviews that have stayed soft-deleted past the retention period,
+// together with their photos, votes, responses, category ratings and revisions. Scheduled runs also delete
+// expired idempotency keys.
+type RetentionService interface {
+	// PurgeExpired purges every expired review, or on a dry run reports what it would purge.
+	PurgeExpired(dryRun bool) (*models.PurgeReport, error)
+	// Run purges on every tick until ctx is cancelled.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type RetentionServiceImpl struct {
+	reviewRepository         db.ReviewRepository
+	idempotencyKeyRepository db.IdempotencyKeyRepository
+	blobStore                blobstore.BlobStore
+	config                   RetentionConfig
+}
+
+func NewRetentionService(_reviewRepository db.ReviewRepository, _idempotencyKeyRepository db.IdempotencyKeyRepository, _blobStore blobstore.BlobStore, _config RetentionConfig) RetentionService {
+	if _config.BatchSize <= 0 {
+		_config.BatchSize = 100
+	}
+	return &RetentionServiceImpl{
+		reviewRepository:         _reviewRepository,
+		idempotencyKeyRepository: _idempotencyKeyRepository,
+		blobStore:                _blobStore,
+		config:                   _config,
+	}
+}
+
+func (s *RetentionServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.PurgeExpired(s.config.DryRun)
+			if err != nil {
+				fmt.Println("Error purging expired reviews:", err)
+			}
+			if report != nil && report.Reviews > 0 {
+				fmt.Printf("Retention purge (dry run: %t): %d reviews, %d photos, %d votes, %d reports, %d responses, %d category ratings, %d aspects, %d revisions\n",
+					report.DryRun, report.Reviews, report.Photos, report.Votes, report.Reports, report.Responses, report.CategoryRatings, report.Aspects, report.Revisions)
+			}
+
+			if s.config.DryRun || s.config.IdempotencyKeyTTL <= 0 {
+				continue
+			}
+			deleted, err := s.purgeExpiredIdempotencyKeys()
+			if err != nil {
+				fmt.Println("Error deleting expired idempotency keys:", err)
+			}
+			if deleted > 0 {
+				fmt.Println("Retention purge: deleted", deleted, "expired idempotency keys")
+			}
+		}
+	}
+}
+
+// purgeExpiredIdempotencyKeys deletes idempotency keys past their TTL, a batch at a time, and returns how many it deleted.
+func (s *RetentionServiceImpl) purgeExpiredIdempotencyKeys() (int64, error) {
+	var total int64
+	for {
+		deleted, err := s.idempotencyKeyRepository.DeleteExpired(s.config.IdempotencyKeyTTL, s.config.BatchSize)
+		total += deleted
+		if err != nil || deleted < int64(s.config.BatchSize) {
+			return total, err
+		}
+	}
+}
+
+func (s *RetentionServiceImpl) PurgeExpired(dryRun bool) (*models.PurgeReport, error) {
+	report := &models.PurgeReport{DryRun: dryRun, ReviewIds: []int64{}, BlobKeys: []string{}}
+
+	// Page by id so a dry run, which leaves the rows in place, still moves through the whole backlog
+	var afterId int64
+	for {
+		ids, err := s.reviewRepository.GetPurgeableIds(s.config.RetentionDays, afterId, s.config.BatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(ids) == 0 {
+			return report, nil
+		}
+
+		batch, err := purgeReviews(s.reviewRepository, s.blobStore, ids, dryRun)
+		if err != nil {
+			return report, err
+		}
+		report.Add(batch)
+
+		afterId = ids[len(ids)-1]
+		if len(ids) < s.config.BatchSize {
+			return report, nil
+		}
+	}
+}
+
+// purgeReviews deletes the reviews' rows and then their photo blobs. A blob that cannot be deleted is
+// only logged, since nothing references it any more.
+func purgeReviews(reviewRepository db.ReviewRepository, blobStore blobstore.BlobStore, ids []int64, dryRun bool) (*models.PurgeReport, error) {
+	report, err := reviewRepository.Purge(ids, dryRun)
+	if err != nil || dryRun {
+		return report, err
+	}
+
+	ctx := context.Background()
+	for _, key := range report.BlobKeys {
+		if err := blobStore.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrBlobNotFound) {
+			fmt.Println("Error deleting purged blob:", key, err)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"ReviewService/blobstore"
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// expiredReviews holds soft-deleted reviews past retention, each with one photo, and purges them like the repository:
+// a dry run reports without removing.
+type expiredReviews struct {
+	db.ReviewRepository
+	ids     []int64
+	batches [][]int64
+}
+
+func (e *expiredReviews) GetPurgeableIds(olderThanDays int, afterId int64, limit int) ([]int64, error) {
+	var ids []int64
+	for _, id := range e.ids {
+		if id > afterId && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (e *expiredReviews) Purge(ids []int64, dryRun bool) (*models.PurgeReport, error) {
+	e.batches = append(e.batches, ids)
+	report := &models.PurgeReport{DryRun: dryRun, ReviewIds: ids, Reviews: int64(len(ids)), Photos: int64(len(ids))}
+	for _, id := range ids {
+		report.BlobKeys = append(report.BlobKeys, fmt.Sprintf("reviews/%d/photo.jpg", id))
+	}
+	if !dryRun {
+		e.ids = slices.DeleteFunc(e.ids, func(id int64) bool { return slices.Contains(ids, id) })
+	}
+	return report, nil
+}
+
+// expiringKeys holds expired idempotency keys and records the limits DeleteExpired was called with.
+type expiringKeys struct {
+	db.IdempotencyKeyRepository
+	expired int64
+	limits  []int
+}
+
+func (e *expiringKeys) DeleteExpired(ttl time.Duration, limit int) (int64, error) {
+	e.limits = append(e.limits, limit)
+	deleted := min(e.expired, int64(limit))
+	e.expired -= deleted
+	return deleted, nil
+}
+
+func newTestRetentionService(t *testing.T, reviews *expiredReviews, keys *expiringKeys) (*RetentionServiceImpl, blobstore.BlobStore) {
+	t.Helper()
+	store, err := blobstore.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range reviews.ids {
+		if err := store.Put(context.Background(), fmt.Sprintf("reviews/%d/photo.jpg", id), "image/jpeg", []byte("jpeg")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := RetentionConfig{RetentionDays: 90, BatchSize: 2, IdempotencyKeyTTL: 24 * time.Hour}
+	return NewRetentionService(reviews, keys, store, config).(*RetentionServiceImpl), store
+}
+
+func blobExists(store blobstore.BlobStore, id int64) bool {
+	blob, _, err := store.Get(context.Background(), fmt.Sprintf("reviews/%d/photo.jpg", id))
+	if err != nil {
+		return false
+	}
+	blob.Close()
+	return true
+}
+
+func TestPurgeExpiredPurgesInBatches(t *testing.T) {
+	reviews := &expiredReviews{ids: []int64{1, 2, 3, 4, 5}}
+	service, store := newTestRetentionService(t, reviews, &expiringKeys{})
+
+	report, err := service.PurgeExpired(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(reviews.batches, [][]int64{{1, 2}, {3, 4}, {5}}) {
+		t.Errorf("purged batches %v, want batches of 2", reviews.batches)
+	}
+	if report.Reviews != 5 || report.Photos != 5 || len(report.ReviewIds) != 5 || len(reviews.ids) != 0 {
+		t.Errorf("report %+v with %v left, want all 5 reviews purged", report, reviews.ids)
+	}
+	for id := int64(1); id <= 5; id++ {
+		if blobExists(store, id) {
+			t.Errorf("photo of purged review %d was kept", id)
+		}
+	}
+}
+
+func TestPurgeExpiredDryRunKeepsEverything(t *testing.T) {
+	reviews := &expiredReviews{ids: []int64{1, 2, 3}}
+	service, store := newTestRetentionService(t, reviews, &expiringKeys{})
+
+	report, err := service.PurgeExpired(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run leaves the rows in place, so it has to page past them to see the whole backlog
+	if !reflect.DeepEqual(reviews.batches, [][]int64{{1, 2}, {3}}) {
+		t.Errorf("dry run batches %v, want every review once", reviews.batches)
+	}
+	if !report.DryRun || report.Reviews != 3 || len(reviews.ids) != 3 {
+		t.Errorf("report %+v with %v left, want 3 reviews reported and kept", report, reviews.ids)
+	}
+	for id := int64(1); id <= 3; id++ {
+		if !blobExists(store, id) {
+			t.Errorf("dry run deleted the photo of review %d", id)
+		}
+	}
+}
+
+func TestPurgeExpiredIdempotencyKeysDeletesInBatches(t *testing.T) {
+	keys := &expiringKeys{expired: 5}
+	service, _ := newTestRetentionService(t, &expiredReviews{}, keys)
+
+	deleted, err := service.purgeExpiredIdempotencyKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 5 || keys.expired != 0 {
+		t.Errorf("deleted %d with %d left, want all 5 expired keys deleted", deleted, keys.expired)
+	}
+	if !reflect.DeepEqual(keys.limits, []int{2, 2, 2}) {
+		t.Errorf("DeleteExpired limits %v, want batches of 2 until a short batch", keys.limits)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"ReviewService/blobstore"
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// AdminPermission lets a caller restore and permanently delete reviews.
+const AdminPermission = "review:admin"
+
+type ReviewAdminService interface {
+	RestoreReview(id string) (*models.Review, error)
+	PurgeReview(id string, dryRun bool) (*models.PurgeReport, error)
+	PurgeExpired(dryRun bool) (*models.PurgeReport, error)
+}
+
+type ReviewAdminServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	blobStore        blobstore.BlobStore
+	searchIndex      search.ReviewSearchIndex
+	retentionService RetentionService
+}
+
+func NewReviewAdminService(_reviewRepository db.ReviewRepository, _blobStore blobstore.BlobStore, _searchIndex search.ReviewSearchIndex, _retentionService RetentionService) ReviewAdminService {
+	return &ReviewAdminServiceImpl{
+		reviewRepository: _reviewRepository,
+		blobStore:        _blobStore,
+		searchIndex:      _searchIndex,
+		retentionService: _retentionService,
+	}
+}
+
+func (a *ReviewAdminServiceImpl) RestoreReview(id string) (*models.Review, error) {
+	fmt.Println("Restoring review in ReviewAdminService")
+
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := a.reviewRepository.Restore(idInt)
+	if errors.Is(err, db.ErrDuplicateEntry) {
+		return nil, utils.NewConflictError("the booking has been reviewed again since this review was deleted", nil)
+	}
+	if err != nil {
+		fmt.Println("Error restoring review:", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("no deleted review with ID %d", idInt))
+	}
+
+	if err := a.searchIndex.Index(review); err != nil {
+		fmt.Println("Error indexing review for search:", err)
+	}
+
+	fmt.Println("Review restored successfully:", review.Id)
+	return review, nil
+}
+
+// PurgeReview permanently deletes one review. The review must be soft-deleted first, and its deletion must have
+// reached HotelService, since once the row is gone nothing would trigger the hotel's rating sync.
+func (a *ReviewAdminServiceImpl) PurgeReview(id string, dryRun bool) (*models.PurgeReport, error) {
+	fmt.Println("Purging review in ReviewAdminService")
+
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := a.reviewRepository.GetDeletedByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching deleted review:", err)
+		return nil, err
+	}
+	if review == nil {
+		active, err := a.reviewRepository.GetByID(idInt)
+		if err != nil {
+			return nil, err
+		}
+		if active != nil {
+			return nil, utils.NewConflictError("delete the review before purging it", nil)
+		}
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+	if !review.IsSynced {
+		return nil, utils.NewConflictError("the review's deletion has not been synced to the hotel yet, try again shortly", nil)
+	}
+
+	report, err := purgeReviews(a.reviewRepository, a.blobStore, []int64{idInt}, dryRun)
+	if err != nil {
+		fmt.Println("Error purging review:", err)
+		return nil, err
+	}
+
+	fmt.Println("Review purged successfully:", idInt, "dry run:", dryRun)
+	return report, nil
+}
+
+func (a *ReviewAdminServiceImpl) PurgeExpired(dryRun bool) (*models.PurgeReport, error) {
+	fmt.Println("Purging expired reviews in ReviewAdminService")
+	return a.retentionService.PurgeExpired(dryRun)
+}
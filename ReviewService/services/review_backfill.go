@@ -0,0 +1,61 @@
+package services
+
+import (
+	"ReviewService/models"
+	"fmt"
+)
+
+// BackfillReport counts what a backfill did.
+type BackfillReport struct {
+	Updated int64
+	Skipped int64 // reviews updated by an edit while the backfill was running
+}
+
+// BackfillService fills in a derived column for the reviews stored before it existed, or computed by an older
+// version of its code. Reviews written while it runs get the column on write and are left alone.
+type BackfillService interface {
+	Backfill(batchSize int) (*BackfillReport, error)
+}
+
+// reviewBackfill describes one backfill: fetch returns up to limit reviews still to do after afterId in id order,
+// and apply computes the column on a review and stores it, returning false when an edit stored it first.
+type reviewBackfill struct {
+	name  string // "Sentiment", for the progress lines
+	done  string // what an updated review was, "analyzed"
+	fetch func(afterId int64, limit int) ([]*models.Review, error)
+	apply func(review *models.Review) (bool, error)
+}
+
+func (b *reviewBackfill) run(batchSize int) (*BackfillReport, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	report := &BackfillReport{}
+	afterId := int64(0)
+	for {
+		reviews, err := b.fetch(afterId, batchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, review := range reviews {
+			stored, err := b.apply(review)
+			if err != nil {
+				return report, fmt.Errorf("review %d: %w", review.Id, err)
+			}
+			if stored {
+				report.Updated++
+			} else {
+				report.Skipped++
+			}
+			afterId = review.Id
+		}
+
+		if len(reviews) < batchSize {
+			fmt.Printf("%s backfill finished: %d reviews %s, %d skipped\n", b.name, report.Updated, b.done, report.Skipped)
+			return report, nil
+		}
+		fmt.Printf("%s backfill: %d %s, %d skipped, up to review %d\n", b.name, report.Updated, b.done, report.Skipped, afterId)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"ReviewService/models"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testBackfill pages through reviews 1 to count, reporting the ids in edited as already stored and failing on
+// failAt.
+func testBackfill(count int64, edited map[int64]bool, failAt int64) (*reviewBackfill, *[]int64) {
+	var afterIds []int64
+	return &reviewBackfill{
+		name: "Test",
+		done: "updated",
+		fetch: func(afterId int64, limit int) ([]*models.Review, error) {
+			afterIds = append(afterIds, afterId)
+			var reviews []*models.Review
+			for id := afterId + 1; id <= count && len(reviews) < limit; id++ {
+				reviews = append(reviews, &models.Review{Id: id})
+			}
+			return reviews, nil
+		},
+		apply: func(review *models.Review) (bool, error) {
+			if review.Id == failAt {
+				return false, errors.New("connection reset")
+			}
+			return !edited[review.Id], nil
+		},
+	}, &afterIds
+}
+
+func TestBackfillPagesAndCounts(t *testing.T) {
+	backfill, afterIds := testBackfill(5, map[int64]bool{2: true}, 0)
+
+	report, err := backfill.run(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *report != (BackfillReport{Updated: 4, Skipped: 1}) {
+		t.Errorf("report %+v, want 4 updated and the edited review skipped", report)
+	}
+	if !reflect.DeepEqual(*afterIds, []int64{0, 2, 4}) {
+		t.Errorf("fetched after %v, want batches of 2 until a short batch", *afterIds)
+	}
+}
+
+func TestBackfillFullLastBatchFetchesAgain(t *testing.T) {
+	backfill, afterIds := testBackfill(4, nil, 0)
+
+	if _, err := backfill.run(2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*afterIds, []int64{0, 2, 4}) {
+		t.Errorf("fetched after %v, want an empty batch to end the run", *afterIds)
+	}
+}
+
+func TestBackfillStopsOnError(t *testing.T) {
+	backfill, _ := testBackfill(5, nil, 3)
+
+	report, err := backfill.run(0)
+	if err == nil || err.Error() != "review 3: connection reset" {
+		t.Fatalf("got %v, want the failing review named", err)
+	}
+	if report.Updated != 2 {
+		t.Errorf("report %+v, want the 2 reviews before the failure counted", report)
+	}
+}
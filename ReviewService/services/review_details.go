@@ -0,0 +1,86 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"fmt"
+)
+
+// ReviewDetailsLoader attaches the data that lives outside the reviews table to reviews shown publicly.
+type ReviewDetailsLoader struct {
+	reviewRepository   db.ReviewRepository
+	responseRepository db.ReviewResponseRepository
+	photoRepository    db.ReviewPhotoRepository
+}
+
+func NewReviewDetailsLoader(_reviewRepository db.ReviewRepository, _responseRepository db.ReviewResponseRepository, _photoRepository db.ReviewPhotoRepository) *ReviewDetailsLoader {
+	return &ReviewDetailsLoader{
+		reviewRepository:   _reviewRepository,
+		responseRepository: _responseRepository,
+		photoRepository:    _photoRepository,
+	}
+}
+
+// Attach embeds each review's category ratings, aspects, host response and photos.
+func (l *ReviewDetailsLoader) Attach(reviews []*models.Review) error {
+	if err := l.attachCategoryRatings(reviews); err != nil {
+		return err
+	}
+	if err := l.attachAspects(reviews); err != nil {
+		return err
+	}
+	if err := attachResponses(l.responseRepository, reviews); err != nil {
+		return err
+	}
+	return attachPhotos(l.photoRepository, reviews)
+}
+
+func (l *ReviewDetailsLoader) attachCategoryRatings(reviews []*models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.Id
+	}
+
+	ratings, err := l.reviewRepository.GetCategoryRatings(ids)
+	if err != nil {
+		fmt.Println("Error fetching category ratings:", err)
+		return err
+	}
+
+	for _, review := range reviews {
+		review.CategoryRatings = ratings[review.Id]
+		if review.CategoryRatings == nil {
+			review.CategoryRatings = map[string]int{}
+		}
+	}
+	return nil
+}
+
+func (l *ReviewDetailsLoader) attachAspects(reviews []*models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.Id
+	}
+
+	aspects, err := l.reviewRepository.GetAspects(ids)
+	if err != nil {
+		fmt.Println("Error fetching review aspects:", err)
+		return err
+	}
+
+	for _, review := range reviews {
+		review.Aspects = aspects[review.Id]
+		if review.Aspects == nil {
+			review.Aspects = map[string]float64{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+type ReviewExportService interface {
+	// PrepareExport validates the query before anything is written, so a bad query still gets an error response.
+	PrepareExport(query *dto.ReviewExportQuery) (*ReviewExport, error)
+	// WriteExport streams the matching reviews to w chunk by chunk, calling flush after each chunk.
+	// It returns the number of rows written, even when it fails part way.
+	WriteExport(export *ReviewExport, w io.Writer, flush func() error) (int, error)
+}
+
+// ReviewExport is a validated export request.
+type ReviewExport struct {
+	Format string
+	filter *models.ReviewFilter
+}
+
+type ReviewExportServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	batchSize        int
+}
+
+func NewReviewExportService(_reviewRepository db.ReviewRepository, _batchSize int) ReviewExportService {
+	if _batchSize <= 0 {
+		_batchSize = 1000
+	}
+	return &ReviewExportServiceImpl{
+		reviewRepository: _reviewRepository,
+		batchSize:        _batchSize,
+	}
+}
+
+func (s *ReviewExportServiceImpl) PrepareExport(query *dto.ReviewExportQuery) (*ReviewExport, error) {
+	format := query.Format
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		return nil, utils.NewBadRequestError("format must be csv or ndjson")
+	}
+
+	// An export walks the reviews in ID order, so the listing's paging parameters do not apply
+	list := dto.ReviewListQuery{}
+	if query.List != nil {
+		list = *query.List
+	}
+	list.Sort, list.Cursor, list.Limit = "", "", 0
+	filter, err := newReviewFilter(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.HotelId != "" {
+		hotelId, err := strconv.ParseInt(query.HotelId, 10, 64)
+		if err != nil {
+			return nil, utils.NewBadRequestError("invalid hotel ID")
+		}
+		filter.HotelId = &hotelId
+	}
+	if query.UserId != "" {
+		userId, err := strconv.ParseInt(query.UserId, 10, 64)
+		if err != nil {
+			return nil, utils.NewBadRequestError("invalid user ID")
+		}
+		filter.UserId = &userId
+	}
+	if query.Status != "" {
+		if err := validateModerationStatus(query.Status); err != nil {
+			return nil, err
+		}
+		filter.ModerationStatus = query.Status
+	}
+
+	return &ReviewExport{Format: format, filter: filter}, nil
+}
+
+func (s *ReviewExportServiceImpl) WriteExport(export *ReviewExport, w io.Writer, flush func() error) (int, error) {
+	fmt.Println("Exporting reviews in ReviewExportService, format:", export.Format)
+
+	buffered := bufio.NewWriter(w)
+	var writeRow func(row *dto.ReviewExportRowDTO) error
+	var flushRows func() error
+
+	if export.Format == ExportFormatCSV {
+		csvWriter := csv.NewWriter(buffered)
+		if err := csvWriter.Write(exportCSVHeader); err != nil {
+			return 0, err
+		}
+		writeRow = func(row *dto.ReviewExportRowDTO) error {
+			return csvWriter.Write(exportCSVRecord(row))
+		}
+		flushRows = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	} else {
+		encoder := json.NewEncoder(buffered)
+		encoder.SetEscapeHTML(false)
+		writeRow = func(row *dto.ReviewExportRowDTO) error {
+			return encoder.Encode(row)
+		}
+		flushRows = func() error { return nil }
+	}
+
+	rows := 0
+	var afterId int64
+	for {
+		reviews, err := s.reviewRepository.ListForExport(export.filter, afterId, s.batchSize)
+		if err != nil {
+			return rows, err
+		}
+
+		for _, review := range reviews {
+			if err := writeRow(toExportRow(review)); err != nil {
+				return rows, err
+			}
+			rows++
+		}
+
+		if err := flushRows(); err != nil {
+			return rows, err
+		}
+		if err := buffered.Flush(); err != nil {
+			return rows, err
+		}
+		if err := flush(); err != nil {
+			return rows, err
+		}
+
+		if len(reviews) < s.batchSize {
+			break
+		}
+		afterId = reviews[len(reviews)-1].Id
+	}
+
+	fmt.Println("Export finished, rows:", rows)
+	return rows, nil
+}
+
+// exportCSVHeader lists the CSV columns. The import reads the same names, so an export can be imported elsewhere.
+var exportCSVHeader = func() []string {
+	header := []string{"id", "user_id", "booking_id", "hotel_id", "rating", "comment"}
+	header = append(header, models.ReviewCategories...)
+	return append(header, "moderation_status", "verified_stay", "sentiment_score", "language", "helpful_count", "not_helpful_count", "created_at", "updated_at", "edited_at")
+}()
+
+func toExportRow(review *models.Review) *dto.ReviewExportRowDTO {
+	return &dto.ReviewExportRowDTO{
+		Id:               review.Id,
+		UserId:           review.UserId,
+		BookingId:        review.BookingId,
+		HotelId:          review.HotelId,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		CategoryRatings:  review.CategoryRatings,
+		ModerationStatus: review.ModerationStatus,
+		VerifiedStay:     review.IsVerifiedStay,
+		SentimentScore:   review.SentimentScore,
+		Language:         review.Language,
+		HelpfulCount:     review.HelpfulCount,
+		NotHelpfulCount:  review.NotHelpfulCount,
+		CreatedAt:        review.CreatedAt,
+		UpdatedAt:        review.UpdatedAt,
+		EditedAt:         review.EditedAt,
+	}
+}
+
+// exportCSVRecord flattens a row in the order of exportCSVHeader. Missing values are left empty, and cells a
+// spreadsheet would run as formulas are escaped.
+func exportCSVRecord(row *dto.ReviewExportRowDTO) []string {
+	record := []string{
+		strconv.FormatInt(row.Id, 10),
+		strconv.FormatInt(row.UserId, 10),
+		strconv.FormatInt(row.BookingId, 10),
+		strconv.FormatInt(row.HotelId, 10),
+		strconv.Itoa(row.Rating),
+		row.Comment,
+	}
+	for _, category := range models.ReviewCategories {
+		value := ""
+		if rating, ok := row.CategoryRatings[category]; ok {
+			value = strconv.Itoa(rating)
+		}
+		record = append(record, value)
+	}
+
+	sentimentScore := ""
+	if row.SentimentScore != nil {
+		sentimentScore = strconv.FormatFloat(*row.SentimentScore, 'f', 3, 64)
+	}
+	language := ""
+	if row.Language != nil {
+		language = *row.Language
+	}
+	editedAt := ""
+	if row.EditedAt != nil {
+		editedAt = *row.EditedAt
+	}
+	record = append(record,
+		row.ModerationStatus,
+		strconv.FormatBool(row.VerifiedStay),
+		sentimentScore,
+		language,
+		strconv.Itoa(row.HelpfulCount),
+		strconv.Itoa(row.NotHelpfulCount),
+		row.CreatedAt,
+		row.UpdatedAt,
+		editedAt,
+	)
+	for i, cell := range record {
+		record[i] = escapeCSVFormula(cell)
+	}
+	return record
+}
+
+// csvFormulaStarts are the characters that make a spreadsheet read a cell as a formula.
+const csvFormulaStarts = "=+-@\t\r"
+
+// escapeCSVFormula prefixes a cell a spreadsheet would run as a formula with ', so a comment such as
+// =HYPERLINK(...) opens as text. Plain numbers like a negative sentiment score are left alone. A cell that already
+// starts with ' before a formula character gets another one, so unescapeCSVFormula restores any cell exactly.
+func escapeCSVFormula(cell string) string {
+	if !csvFormulaLike(cell) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil && strings.Trim(cell, "+-.0123456789") == "" {
+		return cell
+	}
+	return "'" + cell
+}
+
+// unescapeCSVFormula removes the ' escapeCSVFormula added to a cell.
+func unescapeCSVFormula(cell string) string {
+	if strings.HasPrefix(cell, "'") && csvFormulaLike(cell[1:]) {
+		return cell[1:]
+	}
+	return cell
+}
+
+// csvFormulaLike reports whether the cell starts with a formula character once any leading 's are skipped.
+func csvFormulaLike(cell string) bool {
+	trimmed := strings.TrimLeft(cell, "'")
+	return trimmed != "" && strings.ContainsRune(csvFormulaStarts, rune(trimmed[0]))
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"ReviewService/dto"
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+)
+
+func TestEscapeCSVFormula(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"=HYPERLINK(\"http://evil.example\",\"click\")", "'=HYPERLINK(\"http://evil.example\",\"click\")"},
+		{"+1+cmd|' /C calc'!A0", "'+1+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A9)", "'@SUM(A1:A9)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"Great stay = great value", "Great stay = great value"},
+		{"", ""},
+		// Numbers stay numbers
+		{"-0.412", "-0.412"},
+		{"+5", "+5"},
+		{"-Inf", "'-Inf"},
+		// Already escaped-looking text gets another quote, so the import restores it exactly
+		{"'=1", "''=1"},
+		{"'quoted", "'quoted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			got := escapeCSVFormula(tt.cell)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if back := unescapeCSVFormula(got); back != tt.cell {
+				t.Errorf("unescaped back to %q, want %q", back, tt.cell)
+			}
+		})
+	}
+}
+
+func TestExportCSVRecordEscapesFormulas(t *testing.T) {
+	score := -0.5
+	row := &dto.ReviewExportRowDTO{Id: 1, UserId: 10, BookingId: 20, HotelId: 100, Rating: 2, Comment: "=1+1", SentimentScore: &score}
+
+	record := exportCSVRecord(row)
+	comment, sentimentScore := record[slices.Index(exportCSVHeader, "comment")], record[slices.Index(exportCSVHeader, "sentiment_score")]
+	if comment != "'=1+1" || sentimentScore != "-0.500" {
+		t.Errorf("comment %q and sentiment score %q, want the comment escaped and the score kept", comment, sentimentScore)
+	}
+}
+
+func TestExportedCommentImportsUnchanged(t *testing.T) {
+	comments := []string{"=1+1", "'=1+1", "-5", "@home", "Lovely stay"}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write(exportCSVHeader)
+	for i, comment := range comments {
+		writer.Write(exportCSVRecord(&dto.ReviewExportRowDTO{Id: int64(i + 1), UserId: 10, BookingId: int64(i + 1), HotelId: 100, Rating: 4, Comment: comment}))
+	}
+	writer.Flush()
+
+	reader, err := newCSVImportReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range comments {
+		_, row, err := reader.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row.Comment != want {
+			t.Errorf("imported comment %q, want %q", row.Comment, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"ReviewService/clients"
+	db "ReviewService/db/repositories"
+	"ReviewService/fraud"
+	"ReviewService/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fraudMatchLimit caps the near-duplicate candidates compared with a new review.
+const fraudMatchLimit = 50
+
+type FraudConfig struct {
+	HoldThreshold     float64            // reviews scoring at least this go to moderation instead of being published
+	Weights           map[string]float64 // the most each signal can add to the score, by signal name
+	BurstWindow       time.Duration
+	BurstCount        int           // earlier reviews by the user within BurstWindow at which the burst signal is 1
+	DuplicateLow      float64       // similarity to another review at which the duplicate signal starts
+	DuplicateHigh     float64       // similarity at which it is 1
+	OutlierMinHistory int           // reviews a user needs before a rating can be an outlier
+	QuickReviewWindow time.Duration // reviews written sooner than this after the booking was made are suspicious
+}
+
+// FraudScreener scores new reviews for signs of fraud and holds suspicious ones for a moderator.
+type FraudScreener struct {
+	reviewRepository db.ReviewRepository
+	config           FraudConfig
+}
+
+func NewFraudScreener(_reviewRepository db.ReviewRepository, _config FraudConfig) *FraudScreener {
+	return &FraudScreener{
+		reviewRepository: _reviewRepository,
+		config:           _config,
+	}
+}
+
+// applyFingerprint computes the near-duplicate fingerprint of the review's comment.
+func applyFingerprint(review *models.Review) {
+	fingerprint := &models.TextFingerprint{}
+	if signature, ok := fraud.Signature(review.Comment); ok {
+		fingerprint.Signature = fraud.Encode(signature)
+		fingerprint.Bands = fraud.Bands(signature)
+	}
+	review.Fingerprint = fingerprint
+}
+
+// Screen scores a new review and records the check on it. A review that reaches the hold threshold is set to pending
+// with the signals that flagged it added to its moderation reason. The review's fingerprint must be applied first.
+func (f *FraudScreener) Screen(review *models.Review, booking *clients.Booking) error {
+	signals := map[string]*models.FraudSignal{}
+	for _, signal := range fraud.Signals {
+		signals[signal] = &models.FraudSignal{Weight: f.config.Weights[signal]}
+	}
+
+	if err := f.scoreBurst(review, signals[fraud.SignalBurst]); err != nil {
+		return err
+	}
+	if err := f.scoreDuplicate(review, signals[fraud.SignalDuplicateText]); err != nil {
+		return err
+	}
+	if err := f.scoreRatingOutlier(review, signals[fraud.SignalRatingOutlier]); err != nil {
+		return err
+	}
+	f.scoreQuickReview(booking, signals[fraud.SignalQuickReview])
+
+	scores := map[string]float64{}
+	for signal, s := range signals {
+		s.Score = math.Round(s.Score*1000) / 1000
+		scores[signal] = s.Score
+	}
+
+	check := &models.FraudCheck{
+		Score:    fraud.Combine(scores, f.config.Weights),
+		Decision: models.FraudDecisionPass,
+		Signals:  signals,
+	}
+	if check.Score >= f.config.HoldThreshold {
+		check.Decision = models.FraudDecisionHold
+		review.ModerationStatus = models.ModerationPending
+		review.ModerationReason = fraudHoldReason(review.ModerationReason, signals)
+		fmt.Printf("Review by user %d held as suspected fraud, score %.3f\n", review.UserId, check.Score)
+	}
+	review.FraudCheck = check
+	return nil
+}
+
+// IsHeld reports whether a review is still waiting for a moderator because its fraud check held it.
+func (f *FraudScreener) IsHeld(review *models.Review) (bool, error) {
+	if review.ModerationStatus != models.ModerationPending {
+		return false, nil
+	}
+	checks, err := f.reviewRepository.GetFraudChecks(review.Id)
+	if err != nil {
+		return false, err
+	}
+	return len(checks) > 0 && checks[len(checks)-1].Decision == models.FraudDecisionHold, nil
+}
+
+// scoreBurst counts the user's earlier reviews within the burst window. One of them is normal;
+// the signal rises to 1 as the count reaches BurstCount.
+func (f *FraudScreener) scoreBurst(review *models.Review, signal *models.FraudSignal) error {
+	count, err := f.reviewRepository.CountRecentByUser(review.UserId, f.config.BurstWindow)
+	if err != nil {
+		return err
+	}
+	signal.Score = fraud.Ramp(float64(count), 1, float64(f.config.BurstCount))
+	signal.Detail = fmt.Sprintf("%d earlier reviews in the last %s", count, f.config.BurstWindow)
+	return nil
+}
+
+// scoreDuplicate compares the comment with the reviews sharing a fingerprint band and scores the closest one.
+func (f *FraudScreener) scoreDuplicate(review *models.Review, signal *models.FraudSignal) error {
+	signature := fraud.Decode(review.Fingerprint.Signature)
+	if signature == nil {
+		signal.Detail = "comment too short to compare"
+		return nil
+	}
+
+	matches, err := f.reviewRepository.FindFingerprintMatches(review.Fingerprint.Bands, fraudMatchLimit)
+	if err != nil {
+		return err
+	}
+
+	var best *models.FingerprintMatch
+	bestSimilarity := 0.0
+	for _, match := range matches {
+		if similarity := fraud.Similarity(signature, fraud.Decode(match.Signature)); similarity > bestSimilarity {
+			best, bestSimilarity = match, similarity
+		}
+	}
+	if best == nil {
+		signal.Detail = "no similar reviews"
+		return nil
+	}
+
+	signal.Score = fraud.Ramp(bestSimilarity, f.config.DuplicateLow, f.config.DuplicateHigh)
+	author := fmt.Sprintf("user %d", best.UserId)
+	if best.UserId == review.UserId {
+		author = "the same user"
+	}
+	signal.Detail = fmt.Sprintf("%.0f%% similar to review %d by %s", bestSimilarity*100, best.ReviewId, author)
+	return nil
+}
+
+// scoreRatingOutlier measures how far the rating is from the user's usual ratings, in standard deviations.
+// The deviation is floored at half a star, so a user who always gives the same rating is not flagged for
+// a one-star difference.
+func (f *FraudScreener) scoreRatingOutlier(review *models.Review, signal *models.FraudSignal) error {
+	stats, err := f.reviewRepository.GetUserRatingStats(review.UserId)
+	if err != nil {
+		return err
+	}
+	if stats.Count < f.config.OutlierMinHistory {
+		signal.Detail = fmt.Sprintf("history of %d reviews is too short", stats.Count)
+		return nil
+	}
+
+	deviations := math.Abs(float64(review.Rating)-stats.Mean) / math.Max(stats.StdDev, 0.5)
+	signal.Score = fraud.Ramp(deviations, 1.5, 3)
+	signal.Detail = fmt.Sprintf("rating %d against a mean of %.2f (sd %.2f) over %d reviews", review.Rating, stats.Mean, stats.StdDev, stats.Count)
+	return nil
+}
+
+// scoreQuickReview scores reviews written soon after the booking was made, 1 right away and 0 once the window has passed.
+func (f *FraudScreener) scoreQuickReview(booking *clients.Booking, signal *models.FraudSignal) {
+	if booking == nil || booking.CreatedAt.IsZero() {
+		signal.Detail = "booking creation time unknown"
+		return
+	}
+	age := time.Since(booking.CreatedAt)
+	signal.Score = 1 - fraud.Ramp(age.Seconds(), 0, f.config.QuickReviewWindow.Seconds())
+	signal.Detail = fmt.Sprintf("written %s after the booking was made", age.Round(time.Second))
+}
+
+// fraudHoldReason adds the signals that contributed to a hold to the review's moderation reason, strongest first.
+func fraudHoldReason(reason *string, signals map[string]*models.FraudSignal) *string {
+	var flagged []string
+	for _, signal := range fraud.Signals {
+		if signals[signal].Score > 0 && signals[signal].Weight > 0 {
+			flagged = append(flagged, signal)
+		}
+	}
+	sort.SliceStable(flagged, func(i, j int) bool {
+		a, b := signals[flagged[i]], signals[flagged[j]]
+		return a.Score*a.Weight > b.Score*b.Weight
+	})
+	for i, signal := range flagged {
+		flagged[i] = strings.ReplaceAll(signal, "_", " ")
+	}
+
+	held := "suspected fraud (" + strings.Join(flagged, ", ") + ")"
+	if reason != nil {
+		held = *reason + ", " + held
+	} else {
+		held = "held for review: " + held
+	}
+	return &held
+}
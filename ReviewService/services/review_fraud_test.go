@@ -0,0 +1,162 @@
+package services
+
+import (
+	"ReviewService/clients"
+	db "ReviewService/db/repositories"
+	"ReviewService/fraud"
+	"ReviewService/models"
+	"testing"
+	"time"
+)
+
+// screenedReviews answers the lookups of a fraud check from a user's history and the stored fingerprints.
+type screenedReviews struct {
+	db.ReviewRepository
+	recent  int
+	stats   models.UserRatingStats
+	matches []*models.FingerprintMatch
+	checks  []*models.FraudCheck
+}
+
+func (s *screenedReviews) CountRecentByUser(userId int64, window time.Duration) (int, error) {
+	return s.recent, nil
+}
+
+func (s *screenedReviews) GetUserRatingStats(userId int64) (*models.UserRatingStats, error) {
+	return &s.stats, nil
+}
+
+func (s *screenedReviews) FindFingerprintMatches(bands []int64, limit int) ([]*models.FingerprintMatch, error) {
+	return s.matches, nil
+}
+
+func (s *screenedReviews) GetFraudChecks(reviewId int64) ([]*models.FraudCheck, error) {
+	return s.checks, nil
+}
+
+// testFraudConfig is the default configuration of the service.
+var testFraudConfig = FraudConfig{
+	HoldThreshold: 0.5,
+	Weights: map[string]float64{
+		fraud.SignalBurst:         0.6,
+		fraud.SignalDuplicateText: 0.8,
+		fraud.SignalRatingOutlier: 0.35,
+		fraud.SignalQuickReview:   0.35,
+	},
+	BurstWindow:       time.Hour,
+	BurstCount:        3,
+	DuplicateLow:      0.5,
+	DuplicateHigh:     0.9,
+	OutlierMinHistory: 3,
+	QuickReviewWindow: time.Hour,
+}
+
+const screenedComment = "The hotel was spotless and the staff at the front desk went out of their way to help us with our late check-in"
+
+func fingerprintMatch(reviewId int64, userId int64, comment string) *models.FingerprintMatch {
+	review := &models.Review{Comment: comment}
+	applyFingerprint(review)
+	return &models.FingerprintMatch{ReviewId: reviewId, UserId: userId, Signature: review.Fingerprint.Signature}
+}
+
+func TestFraudScreenerScreen(t *testing.T) {
+	booked := &clients.Booking{Id: 1, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	settled := models.UserRatingStats{Count: 10, Mean: 4.2, StdDev: 0.6}
+
+	tests := []struct {
+		name       string
+		rating     int
+		reviews    *screenedReviews
+		booking    *clients.Booking
+		wantHold   bool
+		wantReason string
+	}{
+		{"ordinary review", 4, &screenedReviews{recent: 1, stats: settled}, booked, false, ""},
+		{"copied text", 4, &screenedReviews{stats: settled, matches: []*models.FingerprintMatch{fingerprintMatch(7, 99, screenedComment)}}, booked,
+			true, "held for review: suspected fraud (duplicate text)"},
+		{"burst of reviews", 4, &screenedReviews{recent: 3, stats: settled}, booked,
+			true, "held for review: suspected fraud (burst)"},
+		// Weak signals only hold a review together
+		{"outlier rating alone", 1, &screenedReviews{stats: settled}, booked, false, ""},
+		{"outlier rating written right after booking", 1, &screenedReviews{stats: settled}, &clients.Booking{Id: 1, CreatedAt: time.Now()},
+			true, "held for review: suspected fraud (rating outlier, quick review)"},
+		{"short history is no outlier", 1, &screenedReviews{stats: models.UserRatingStats{Count: 2, Mean: 5}}, booked, false, ""},
+		{"unrelated match", 4, &screenedReviews{stats: settled, matches: []*models.FingerprintMatch{
+			fingerprintMatch(7, 99, "Breakfast was cold every morning and the pool closed early without any notice to guests staying there"),
+		}}, booked, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := &models.Review{UserId: 10, Rating: tt.rating, Comment: screenedComment, ModerationStatus: models.ModerationApproved}
+			applyFingerprint(review)
+
+			if err := NewFraudScreener(tt.reviews, testFraudConfig).Screen(review, tt.booking); err != nil {
+				t.Fatal(err)
+			}
+
+			check := review.FraudCheck
+			if check == nil || len(check.Signals) != len(fraud.Signals) {
+				t.Fatalf("check %+v, want every signal recorded", check)
+			}
+			if held := check.Decision == models.FraudDecisionHold; held != tt.wantHold {
+				t.Fatalf("decision %s at score %v, want hold %v", check.Decision, check.Score, tt.wantHold)
+			}
+			if !tt.wantHold {
+				if review.ModerationStatus != models.ModerationApproved || review.ModerationReason != nil {
+					t.Errorf("passed review is %s, want it left as it was", review.ModerationStatus)
+				}
+				return
+			}
+			if review.ModerationStatus != models.ModerationPending || review.ModerationReason == nil || *review.ModerationReason != tt.wantReason {
+				t.Errorf("held review is %s with reason %v, want pending with %q", review.ModerationStatus, review.ModerationReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestFraudScreenerHoldAddsToPrescreenReason(t *testing.T) {
+	reason := "held for review: contains a link"
+	review := &models.Review{UserId: 10, Rating: 4, Comment: screenedComment, ModerationStatus: models.ModerationPending, ModerationReason: &reason}
+	applyFingerprint(review)
+
+	reviews := &screenedReviews{recent: 5, stats: models.UserRatingStats{Count: 10, Mean: 4, StdDev: 1}}
+	if err := NewFraudScreener(reviews, testFraudConfig).Screen(review, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "held for review: contains a link, suspected fraud (burst)"; *review.ModerationReason != want {
+		t.Errorf("reason %q, want %q", *review.ModerationReason, want)
+	}
+	if detail := review.FraudCheck.Signals[fraud.SignalQuickReview].Detail; detail != "booking creation time unknown" {
+		t.Errorf("quick review detail %q, want the missing booking noted", detail)
+	}
+}
+
+func TestFraudScreenerIsHeld(t *testing.T) {
+	hold := &models.FraudCheck{Decision: models.FraudDecisionHold}
+	pass := &models.FraudCheck{Decision: models.FraudDecisionPass}
+
+	tests := []struct {
+		name   string
+		status string
+		checks []*models.FraudCheck
+		want   bool
+	}{
+		{"held and pending", models.ModerationPending, []*models.FraudCheck{hold}, true},
+		{"approved by a moderator", models.ModerationApproved, []*models.FraudCheck{hold}, false},
+		{"pending for another reason", models.ModerationPending, []*models.FraudCheck{pass}, false},
+		{"latest check decides", models.ModerationPending, []*models.FraudCheck{hold, pass}, false},
+		{"never checked", models.ModerationPending, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screener := NewFraudScreener(&screenedReviews{checks: tt.checks}, testFraudConfig)
+			held, err := screener.IsHeld(&models.Review{Id: 1, ModerationStatus: tt.status})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if held != tt.want {
+				t.Errorf("got %v, want %v", held, tt.want)
+			}
+		})
+	}
+}
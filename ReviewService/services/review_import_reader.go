@@ -0,0 +1,196 @@
+package services
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+)
+
+// maxImportLineBytes bounds one JSONL line, well above a review with a 1000-character comment.
+const maxImportLineBytes = 1 << 20
+
+// importRowError is a problem with a single row; the import reports it and carries on with the next row.
+type importRowError struct {
+	field   string
+	message string
+}
+
+func (e *importRowError) Error() string {
+	return e.message
+}
+
+// importRowReader reads an import file one row at a time. Next returns io.EOF after the last row,
+// an *importRowError for a row that cannot be parsed, and any other error when the file cannot be read further.
+type importRowReader interface {
+	Next() (line int, row *dto.ImportReviewRowDTO, err error)
+}
+
+func newImportRowReader(format string, body io.Reader) (importRowReader, error) {
+	switch format {
+	case ImportFormatCSV:
+		return newCSVImportReader(body)
+	case ImportFormatJSONL:
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), maxImportLineBytes)
+		return &jsonlImportReader{scanner: scanner}, nil
+	default:
+		return nil, utils.NewBadRequestError("format must be csv or jsonl")
+	}
+}
+
+type jsonlImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlImportReader) Next() (int, *dto.ImportReviewRowDTO, error) {
+	for j.scanner.Scan() {
+		j.line++
+		text := strings.TrimSpace(j.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := &dto.ImportReviewRowDTO{}
+		if err := json.Unmarshal([]byte(text), row); err != nil {
+			return j.line, nil, &importRowError{message: "invalid JSON: " + err.Error()}
+		}
+		return j.line, row, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return j.line + 1, nil, fmt.Errorf("line longer than %d bytes", maxImportLineBytes)
+		}
+		return j.line, nil, err
+	}
+	return j.line, nil, io.EOF
+}
+
+// csvRequiredColumns must be in the header. Category columns and the others below are optional,
+// and unknown columns are ignored so an export can be imported again.
+var csvRequiredColumns = []string{"user_id", "booking_id", "hotel_id", "rating", "comment"}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	line    int // line of the last row read
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, utils.NewBadRequestError("the file is empty")
+	}
+	if err != nil {
+		return nil, utils.NewBadRequestError("invalid CSV header: " + err.Error())
+	}
+
+	// Spreadsheet exports often start the header with a byte order mark
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range csvRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, utils.NewBadRequestError(fmt.Sprintf("the CSV header has no %s column", name))
+		}
+	}
+
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvImportReader) Next() (int, *dto.ImportReviewRowDTO, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, nil, &importRowError{message: "invalid CSV: " + parseErr.Err.Error()}
+		}
+		return c.line, nil, err
+	}
+	line, _ := c.reader.FieldPos(0)
+	c.line = line
+
+	value := func(name string) string {
+		if i, ok := c.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	parseInt := func(name string) (int64, error) {
+		raw := value(name)
+		if raw == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return 0, &importRowError{field: name, message: "must be an integer"}
+		}
+		return n, nil
+	}
+
+	row := &dto.ImportReviewRowDTO{}
+	row.Comment = unescapeCSVFormula(value("comment"))
+	row.CreatedAt = value("created_at")
+
+	for name, target := range map[string]*int64{"user_id": &row.UserId, "booking_id": &row.BookingId, "hotel_id": &row.HotelId} {
+		if *target, err = parseInt(name); err != nil {
+			return line, nil, err
+		}
+	}
+	rating, err := parseInt("rating")
+	if err != nil {
+		return line, nil, err
+	}
+	row.Rating = int(rating)
+
+	if raw := value("verified_stay"); raw != "" {
+		if row.VerifiedStay, err = strconv.ParseBool(raw); err != nil {
+			return line, nil, &importRowError{field: "verified_stay", message: "must be true or false"}
+		}
+	}
+
+	categories := &dto.CategoryRatingsDTO{}
+	targets := map[string]**int{
+		models.CategoryCleanliness:   &categories.Cleanliness,
+		models.CategoryAccuracy:      &categories.Accuracy,
+		models.CategoryCheckIn:       &categories.CheckIn,
+		models.CategoryCommunication: &categories.Communication,
+		models.CategoryLocation:      &categories.Location,
+		models.CategoryValue:         &categories.Value,
+	}
+	for _, category := range models.ReviewCategories {
+		if value(category) == "" {
+			continue
+		}
+		n, err := parseInt(category)
+		if err != nil {
+			return line, nil, err
+		}
+		rating := int(n)
+		*targets[category] = &rating
+		row.CategoryRatings = categories
+	}
+
+	return line, row, nil
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/langid"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/sentiment"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ImportConfig struct {
+	BatchSize int   // rows inserted per transaction
+	MaxErrors int   // row errors listed in the report; the rest are only counted
+	MaxBytes  int64 // request body limit, enforced by the controller
+}
+
+type ReviewImportService interface {
+	// Import reads reviews from a CSV or JSONL file and stores the valid rows in batches. Rows that fail validation
+	// are reported and skipped. On a dry run every check runs, including the inserts, but nothing is kept.
+	Import(format string, body io.Reader, dryRun bool) (*dto.ReviewImportReportDTO, error)
+}
+
+type ReviewImportServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	searchIndex      search.ReviewSearchIndex
+	prescreener      *ReviewPrescreener
+	analyzer         *sentiment.Analyzer
+	identifier       *langid.Identifier
+	validate         *validator.Validate
+	config           ImportConfig
+}
+
+func NewReviewImportService(_reviewRepository db.ReviewRepository, _searchIndex search.ReviewSearchIndex, _prescreener *ReviewPrescreener, _analyzer *sentiment.Analyzer, _identifier *langid.Identifier, _config ImportConfig) ReviewImportService {
+	if _config.BatchSize <= 0 {
+		_config.BatchSize = 500
+	}
+	if _config.MaxErrors <= 0 {
+		_config.MaxErrors = 1000
+	}
+
+	// Errors name fields as they appear in the file
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+
+	return &ReviewImportServiceImpl{
+		reviewRepository: _reviewRepository,
+		searchIndex:      _searchIndex,
+		prescreener:      _prescreener,
+		analyzer:         _analyzer,
+		identifier:       _identifier,
+		validate:         validate,
+		config:           _config,
+	}
+}
+
+// importRow is a row that passed validation, waiting for its batch.
+type importRow struct {
+	line   int
+	review *models.Review
+}
+
+func (s *ReviewImportServiceImpl) Import(format string, body io.Reader, dryRun bool) (*dto.ReviewImportReportDTO, error) {
+	fmt.Println("Importing reviews in ReviewImportService, dry run:", dryRun)
+
+	reader, err := newImportRowReader(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ReviewImportReportDTO{DryRun: dryRun, Errors: []*dto.ImportRowErrorDTO{}}
+	seen := map[models.BookingUser]int{} // line of the first row for each booking and user
+	var batch []*importRow
+
+	for {
+		line, row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *importRowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			s.fail(report, line, rowErr.field, rowErr.message)
+			continue
+		}
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, utils.NewPayloadTooLargeError(fmt.Sprintf("the file is larger than %d bytes; rows up to line %d were processed", tooLarge.Limit, line), report)
+			}
+			fmt.Println("Error reading import file:", err)
+			return nil, err
+		}
+
+		report.Rows++
+		review, fieldErrs := s.toReview(row)
+		if len(fieldErrs) > 0 {
+			for _, fieldErr := range fieldErrs {
+				s.failField(report, line, fieldErr)
+			}
+			report.Failed++
+			continue
+		}
+
+		key := models.BookingUser{BookingId: review.BookingId, UserId: review.UserId}
+		if first, ok := seen[key]; ok {
+			s.fail(report, line, "booking_id", fmt.Sprintf("booking %d is already reviewed by user %d on line %d", key.BookingId, key.UserId, first))
+			continue
+		}
+		seen[key] = line
+
+		batch = append(batch, &importRow{line: line, review: review})
+		if len(batch) >= s.config.BatchSize {
+			if err := s.importBatch(report, batch, dryRun); err != nil {
+				return nil, err
+			}
+			batch = nil
+		}
+	}
+
+	if err := s.importBatch(report, batch, dryRun); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Import finished (dry run: %t): %d rows, %d imported, %d failed\n", dryRun, report.Rows, report.Imported, report.Failed)
+	return report, nil
+}
+
+// toReview validates a row and builds the review it describes, screened and analyzed like a new review.
+func (s *ReviewImportServiceImpl) toReview(row *dto.ImportReviewRowDTO) (*models.Review, []*importRowError) {
+	var errs []*importRowError
+	if err := s.validate.Struct(row); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return nil, []*importRowError{{message: err.Error()}}
+		}
+		for _, fieldErr := range validationErrs {
+			errs = append(errs, &importRowError{field: importFieldName(fieldErr), message: validationMessage(fieldErr)})
+		}
+	}
+
+	createdAt := ""
+	if row.CreatedAt != "" {
+		t, err := parseImportTimestamp(row.CreatedAt)
+		switch {
+		case err != nil:
+			errs = append(errs, &importRowError{field: "created_at", message: "must be RFC 3339, YYYY-MM-DD HH:MM:SS or YYYY-MM-DD"})
+		case t.After(time.Now()):
+			errs = append(errs, &importRowError{field: "created_at", message: "cannot be in the future"})
+		default:
+			createdAt = t.Format(mysqlTimestampLayout)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	status, reason := s.prescreener.Screen(row.Comment)
+	review := &models.Review{
+		UserId:           row.UserId,
+		BookingId:        row.BookingId,
+		HotelId:          row.HotelId,
+		Comment:          row.Comment,
+		Rating:           row.Rating,
+		CategoryRatings:  row.CategoryRatings.ToMap(),
+		CreatedAt:        createdAt,
+		IsVerifiedStay:   row.VerifiedStay,
+		ModerationStatus: status,
+		ModerationReason: reason,
+	}
+	applySentiment(s.analyzer, review)
+	applyFingerprint(review)
+	applyLanguage(s.identifier, review)
+	return review, nil
+}
+
+// importBatch skips rows whose booking the user has already reviewed, then inserts the rest in one transaction.
+// If the insert fails, the whole batch is reported as failed and the import goes on with the next batch.
+func (s *ReviewImportServiceImpl) importBatch(report *dto.ReviewImportReportDTO, batch []*importRow, dryRun bool) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	keys := make([]models.BookingUser, len(batch))
+	for i, row := range batch {
+		keys[i] = models.BookingUser{BookingId: row.review.BookingId, UserId: row.review.UserId}
+	}
+	existing, err := s.reviewRepository.GetActiveReviewIds(keys)
+	if err != nil {
+		fmt.Println("Error checking for existing reviews:", err)
+		return err
+	}
+
+	var rows []*importRow
+	var reviews []*models.Review
+	for i, row := range batch {
+		if id, ok := existing[keys[i]]; ok {
+			s.fail(report, row.line, "booking_id", fmt.Sprintf("booking %d is already reviewed by user %d (review %d)", keys[i].BookingId, keys[i].UserId, id))
+			continue
+		}
+		rows = append(rows, row)
+		reviews = append(reviews, row.review)
+	}
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	if err := s.reviewRepository.ImportBatch(reviews, dryRun); err != nil {
+		fmt.Println("Error importing batch:", err)
+		message := "the batch could not be stored: " + err.Error()
+		if errors.Is(err, db.ErrDuplicateEntry) {
+			message = "the batch could not be stored: a booking in it was reviewed while the import ran"
+		}
+		for _, row := range rows {
+			s.fail(report, row.line, "", message)
+		}
+		return nil
+	}
+
+	report.Imported += len(reviews)
+	if !dryRun {
+		for _, review := range reviews {
+			if err := s.searchIndex.Index(review); err != nil {
+				fmt.Println("Error indexing review for search:", err)
+			}
+		}
+	}
+	return nil
+}
+
+// fail records a failed row with a single error.
+func (s *ReviewImportServiceImpl) fail(report *dto.ReviewImportReportDTO, line int, field string, message string) {
+	report.Failed++
+	s.failField(report, line, &importRowError{field: field, message: message})
+}
+
+// failField lists an error in the report, or only marks the list truncated once it is full.
+func (s *ReviewImportServiceImpl) failField(report *dto.ReviewImportReportDTO, line int, err *importRowError) {
+	if len(report.Errors) >= s.config.MaxErrors {
+		report.ErrorsTruncated = true
+		return
+	}
+	report.Errors = append(report.Errors, &dto.ImportRowErrorDTO{Line: line, Field: err.field, Message: err.message})
+}
+
+// importFieldName turns a validator namespace such as ImportReviewRowDTO.CreateReviewRequestDTO.category_ratings.value
+// into the field's path in the file, category_ratings.value.
+func importFieldName(fieldErr validator.FieldError) string {
+	parts := strings.Split(fieldErr.Namespace(), ".")[1:]
+	path := parts[:0]
+	for _, part := range parts {
+		if part != "CreateReviewRequestDTO" {
+			path = append(path, part)
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	unit := ""
+	if fieldErr.Kind() == reflect.String {
+		unit = " characters"
+	}
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fieldErr.Param() + unit
+	case "max":
+		return "must be at most " + fieldErr.Param() + unit
+	default:
+		return "failed the " + fieldErr.Tag() + " check"
+	}
+}
+
+// parseImportTimestamp accepts RFC 3339, a MySQL timestamp in UTC (as exports write them) or a plain date.
+func parseImportTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(mysqlTimestampLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
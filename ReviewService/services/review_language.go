@@ -0,0 +1,40 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/langid"
+	"ReviewService/models"
+)
+
+// applyLanguage identifies the language of the review's comment and stores it on the review.
+func applyLanguage(identifier *langid.Identifier, review *models.Review) {
+	language := identifier.Identify(review.Comment)
+	review.Language = &language
+}
+
+// LanguageBackfillServiceImpl identifies the language of reviews stored before language detection existed, so
+// the lang filter and the preferred-language ordering see them.
+type LanguageBackfillServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	identifier       *langid.Identifier
+}
+
+func NewLanguageBackfillService(_reviewRepository db.ReviewRepository, _identifier *langid.Identifier) BackfillService {
+	return &LanguageBackfillServiceImpl{
+		reviewRepository: _reviewRepository,
+		identifier:       _identifier,
+	}
+}
+
+func (s *LanguageBackfillServiceImpl) Backfill(batchSize int) (*BackfillReport, error) {
+	backfill := &reviewBackfill{
+		name:  "Language",
+		done:  "identified",
+		fetch: s.reviewRepository.GetUnidentified,
+		apply: func(review *models.Review) (bool, error) {
+			applyLanguage(s.identifier, review)
+			return s.reviewRepository.SetLanguage(review.Id, *review.Language)
+		},
+	}
+	return backfill.run(batchSize)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/langid"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultReviewPageSize = 20
+	MaxReviewPageSize     = 100
+)
+
+const mysqlTimestampLayout = "2006-01-02 15:04:05"
+
+// newReviewFilter validates the listing parameters and turns them into a repository filter.
+// extraSorts are accepted besides the public ones.
+func newReviewFilter(query *dto.ReviewListQuery, extraSorts ...models.ReviewSort) (*models.ReviewFilter, error) {
+	filter := &models.ReviewFilter{
+		Sort:       models.ReviewSort(query.Sort),
+		Limit:      query.Limit,
+		MinRating:  query.MinRating,
+		MaxRating:  query.MaxRating,
+		HasComment: query.HasComment,
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = models.ReviewSortNewest
+	}
+	sorts := append([]models.ReviewSort{models.ReviewSortNewest, models.ReviewSortOldest, models.ReviewSortHighest, models.ReviewSortLowest, models.ReviewSortRelevant}, extraSorts...)
+	if !slices.Contains(sorts, filter.Sort) {
+		names := make([]string, len(sorts))
+		for i, sort := range sorts {
+			names[i] = string(sort)
+		}
+		return nil, utils.NewBadRequestError("sort must be one of " + strings.Join(names, ", "))
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultReviewPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > MaxReviewPageSize {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", MaxReviewPageSize))
+	}
+
+	if filter.MinRating < 0 || filter.MinRating > 5 || filter.MaxRating < 0 || filter.MaxRating > 5 {
+		return nil, utils.NewBadRequestError("min_rating and max_rating must be between 1 and 5")
+	}
+	if filter.MinRating > 0 && filter.MaxRating > 0 && filter.MinRating > filter.MaxRating {
+		return nil, utils.NewBadRequestError("min_rating cannot be greater than max_rating")
+	}
+
+	if query.From != "" {
+		from, _, err := parseListingDate(query.From)
+		if err != nil {
+			return nil, utils.NewBadRequestError("from must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		}
+		filter.CreatedFrom = from.Format(mysqlTimestampLayout)
+	}
+	if query.To != "" {
+		to, dateOnly, err := parseListingDate(query.To)
+		if err != nil {
+			return nil, utils.NewBadRequestError("to must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		}
+		if dateOnly {
+			// A plain date includes the whole day
+			to = to.AddDate(0, 0, 1)
+		} else {
+			to = to.Add(time.Second)
+		}
+		filter.CreatedBefore = to.Format(mysqlTimestampLayout)
+	}
+
+	if query.Lang != "" {
+		if !langid.Supported(query.Lang) {
+			return nil, utils.NewBadRequestError("lang must be one of " + strings.Join(langid.Languages, ", ") + " or und")
+		}
+		filter.Language = query.Lang
+	}
+
+	if filter.Sort == models.ReviewSortRelevant {
+		filter.RelevanceAsOf = time.Now().UTC().Format(mysqlTimestampLayout)
+	}
+
+	if query.Cursor != "" {
+		cursor := &models.ReviewCursor{}
+		if err := utils.DecodeCursor(query.Cursor, cursor); err != nil {
+			return nil, err
+		}
+		if cursor.Sort != filter.Sort {
+			return nil, utils.NewBadRequestError("cursor was issued for a different sort")
+		}
+		if filter.Sort == models.ReviewSortRelevant {
+			// Later pages rank as of the first page, so reviews do not move between pages as they age
+			if _, err := time.Parse(mysqlTimestampLayout, cursor.AsOf); err != nil {
+				return nil, utils.NewBadRequestError("invalid cursor")
+			}
+			if _, err := strconv.ParseFloat(cursor.Score, 64); err != nil {
+				return nil, utils.NewBadRequestError("invalid cursor")
+			}
+			filter.RelevanceAsOf = cursor.AsOf
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// applyLanguagePreference puts reviews in the languages of the Accept-Language header first. A cursor keeps the
+// order of the page that issued it. Filtering on one language makes the preference moot.
+func applyLanguagePreference(filter *models.ReviewFilter, query *dto.ReviewListQuery) {
+	if filter.Language != "" {
+		return
+	}
+	if filter.After == nil {
+		filter.PreferredLanguages = langid.ParseAcceptLanguage(query.AcceptLanguage)
+		return
+	}
+	// Cursors come back from clients, so only known languages are taken from them
+	for _, language := range filter.After.Preferred {
+		if langid.Supported(language) && len(filter.PreferredLanguages) < len(langid.Languages) {
+			filter.PreferredLanguages = append(filter.PreferredLanguages, language)
+		}
+	}
+}
+
+// parseListingDate accepts YYYY-MM-DD or RFC 3339 and returns the time in UTC, reporting whether it was a plain date.
+func parseListingDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.UTC(), false, nil
+}
+
+// listReviews fetches a page of public reviews with their host responses.
+func (r *ReviewServiceImpl) listReviews(filter *models.ReviewFilter, includeTotal bool) (*dto.ReviewPageDTO, error) {
+	page, err := listReviewPage(r.reviewRepository, filter, includeTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.details.Attach(page.Reviews); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// listReviewPage fetches one page plus one extra row, which tells whether a next page exists.
+func listReviewPage(reviewRepository db.ReviewRepository, filter *models.ReviewFilter, includeTotal bool) (*dto.ReviewPageDTO, error) {
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	reviews, err := reviewRepository.List(filter)
+	if err != nil {
+		fmt.Println("Error listing reviews:", err)
+		return nil, err
+	}
+
+	page := &dto.ReviewPageDTO{Reviews: []*models.Review{}}
+	if len(reviews) > pageSize {
+		last := reviews[pageSize-1]
+		next := &models.ReviewCursor{Sort: filter.Sort, Preferred: filter.PreferredLanguages}
+		if len(filter.PreferredLanguages) > 0 {
+			next.LanguageRank = filter.LanguageRank(last)
+		}
+		switch filter.Sort {
+		case models.ReviewSortRelevant:
+			next.AsOf, next.Score, next.Id = filter.RelevanceAsOf, last.RelevanceScore, last.Id
+		case models.ReviewSortMostReported:
+			next.ReportCount, next.ReportWeight, next.Id = last.ReportCount, last.ReportWeight, last.Id
+		default:
+			next.Rating, next.CreatedAt, next.Id = last.Rating, last.CreatedAt, last.Id
+		}
+		cursor, err := utils.EncodeCursor(next)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = &cursor
+		reviews = reviews[:pageSize]
+	}
+	page.Reviews = append(page.Reviews, reviews...)
+
+	if includeTotal {
+		total, err := reviewRepository.Count(filter)
+		if err != nil {
+			fmt.Println("Error counting reviews:", err)
+			return nil, err
+		}
+		page.TotalCount = &total
+	}
+
+	return page, nil
+}
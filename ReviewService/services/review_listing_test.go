@@ -0,0 +1,120 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pagedReviews returns the first filter.Limit of its reviews, whatever the cursor.
+type pagedReviews struct {
+	db.ReviewRepository
+	reviews []*models.Review
+}
+
+func (p *pagedReviews) List(filter *models.ReviewFilter) ([]*models.Review, error) {
+	return p.reviews[:min(filter.Limit, len(p.reviews))], nil
+}
+
+func encodeTestCursor(t *testing.T, cursor any) string {
+	t.Helper()
+	encoded, err := utils.EncodeCursor(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestRelevantFilterComputesAsOfFirstPage(t *testing.T) {
+	filter, err := newReviewFilter(&dto.ReviewListQuery{Sort: "relevant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asOf, err := time.Parse(mysqlTimestampLayout, filter.RelevanceAsOf)
+	if err != nil || time.Since(asOf) > time.Minute {
+		t.Errorf("first page ranked as of %q, want now", filter.RelevanceAsOf)
+	}
+
+	cursor := encodeTestCursor(t, &models.ReviewCursor{Sort: models.ReviewSortRelevant, AsOf: "2025-09-01 10:00:00", Score: "0.4123456789", Id: 7})
+	filter, err = newReviewFilter(&dto.ReviewListQuery{Sort: "relevant", Cursor: cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.RelevanceAsOf != "2025-09-01 10:00:00" || filter.After.Score != "0.4123456789" || filter.After.Id != 7 {
+		t.Errorf("filter ranked as of %q after %+v, want the cursor's time and position", filter.RelevanceAsOf, filter.After)
+	}
+}
+
+func TestReviewFilterRejectsInvalidCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   string
+		cursor string
+	}{
+		{"not base64", "newest", "%%%"},
+		{"other sort", "newest", encodeTestCursor(t, &models.ReviewCursor{Sort: models.ReviewSortOldest, CreatedAt: "2025-09-01 10:00:00", Id: 7})},
+		{"relevant offset cursor", "relevant", encodeTestCursor(t, map[string]any{"s": "relevant", "o": -20})},
+		{"relevant without time", "relevant", encodeTestCursor(t, &models.ReviewCursor{Sort: models.ReviewSortRelevant, Score: "0.5", Id: 7})},
+		{"relevant with malformed time", "relevant", encodeTestCursor(t, &models.ReviewCursor{Sort: models.ReviewSortRelevant, AsOf: "yesterday", Score: "0.5", Id: 7})},
+		{"relevant with malformed score", "relevant", encodeTestCursor(t, &models.ReviewCursor{Sort: models.ReviewSortRelevant, AsOf: "2025-09-01 10:00:00", Score: "1 OR 1=1", Id: 7})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newReviewFilter(&dto.ReviewListQuery{Sort: tt.sort, Cursor: tt.cursor})
+			if utils.StatusFromError(err, 0) != http.StatusBadRequest {
+				t.Errorf("got %v, want a 400", err)
+			}
+		})
+	}
+}
+
+func TestListReviewPageCursorPositions(t *testing.T) {
+	reviews := []*models.Review{
+		{Id: 9, Rating: 5, CreatedAt: "2025-09-02 10:00:00", RelevanceScore: "0.8100000000", ReportCount: 4, ReportWeight: 5.5},
+		{Id: 4, Rating: 4, CreatedAt: "2025-09-01 10:00:00", RelevanceScore: "0.6200000000", ReportCount: 2, ReportWeight: 1.5},
+		{Id: 6, Rating: 3, CreatedAt: "2025-08-30 10:00:00", RelevanceScore: "0.6200000000", ReportCount: 2, ReportWeight: 1},
+	}
+	tests := []struct {
+		sort models.ReviewSort
+		want models.ReviewCursor
+	}{
+		{models.ReviewSortNewest, models.ReviewCursor{Sort: models.ReviewSortNewest, Rating: 4, CreatedAt: "2025-09-01 10:00:00", Id: 4}},
+		{models.ReviewSortRelevant, models.ReviewCursor{Sort: models.ReviewSortRelevant, AsOf: "2025-09-03 08:00:00", Score: "0.6200000000", Id: 4}},
+		{models.ReviewSortMostReported, models.ReviewCursor{Sort: models.ReviewSortMostReported, ReportCount: 2, ReportWeight: 1.5, Id: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			repository := &pagedReviews{reviews: reviews}
+			filter := &models.ReviewFilter{Sort: tt.sort, Limit: 2}
+			if tt.sort == models.ReviewSortRelevant {
+				filter.RelevanceAsOf = "2025-09-03 08:00:00"
+			}
+
+			page, err := listReviewPage(repository, filter, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Reviews) != 2 || page.NextCursor == nil {
+				t.Fatalf("got %d reviews and cursor %v, want a full page with a next cursor", len(page.Reviews), page.NextCursor)
+			}
+
+			var next models.ReviewCursor
+			if err := utils.DecodeCursor(*page.NextCursor, &next); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(next, tt.want) {
+				t.Errorf("next cursor %+v, want %+v", next, tt.want)
+			}
+		})
+	}
+
+	page, err := listReviewPage(&pagedReviews{reviews: reviews}, &models.ReviewFilter{Sort: models.ReviewSortRelevant, Limit: 3}, false)
+	if err != nil || page.NextCursor != nil {
+		t.Errorf("last page got cursor %v, error %v, want none", page.NextCursor, err)
+	}
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"ReviewService/blobstore"
+	db "ReviewService/db/repositories"
+	"ReviewService/imaging"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+type PhotoConfig struct {
+	MaxBytes      int64 // per uploaded file
+	MaxPerReview  int
+	MaxPixels     int // width * height, checked before decoding
+	ThumbnailSize int // longest side of a thumbnail in pixels
+}
+
+// MaxRequestBytes bounds a whole upload request: a full set of photos plus room for the multipart framing.
+func (c PhotoConfig) MaxRequestBytes() int64 {
+	return c.MaxBytes*int64(c.MaxPerReview) + 1<<20
+}
+
+type ReviewPhotoService interface {
+	UploadPhotos(reviewId string, uploader *models.AuthUser, files []*multipart.FileHeader) ([]*models.ReviewPhoto, error)
+	GetPhotos(reviewId string) ([]*models.ReviewPhoto, error)
+	OpenPhoto(reviewId string, photoId string, thumbnail bool) (io.ReadCloser, string, error)
+	DeletePhoto(reviewId string, photoId string, caller *models.AuthUser) error
+}
+
+type ReviewPhotoServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	photoRepository  db.ReviewPhotoRepository
+	blobStore        blobstore.BlobStore
+	config           PhotoConfig
+}
+
+func NewReviewPhotoService(_reviewRepository db.ReviewRepository, _photoRepository db.ReviewPhotoRepository, _blobStore blobstore.BlobStore, _config PhotoConfig) ReviewPhotoService {
+	return &ReviewPhotoServiceImpl{
+		reviewRepository: _reviewRepository,
+		photoRepository:  _photoRepository,
+		blobStore:        _blobStore,
+		config:           _config,
+	}
+}
+
+func (s *ReviewPhotoServiceImpl) UploadPhotos(reviewId string, uploader *models.AuthUser, files []*multipart.FileHeader) ([]*models.ReviewPhoto, error) {
+	fmt.Println("Uploading review photos in ReviewPhotoService")
+
+	review, err := s.getReview(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserId != uploader.Id {
+		return nil, utils.NewForbiddenError("only the author can add photos to this review")
+	}
+
+	if len(files) == 0 {
+		return nil, utils.NewBadRequestError("at least one photo is required in the \"photos\" field")
+	}
+	// Fail fast before decoding anything; CreateAll enforces the limit again under a lock
+	existing, err := s.photoRepository.CountByReviewId(review.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing+len(files) > s.config.MaxPerReview {
+		return nil, s.photoLimitError(existing)
+	}
+
+	// Validate and process every file before storing any, so a bad file rejects the whole upload
+	processed := make([]*imaging.Processed, len(files))
+	for i, file := range files {
+		processed[i], err = s.process(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pending := []*models.ReviewPhoto{}
+	for _, img := range processed {
+		photo, err := s.storeBlobs(review, img)
+		if err != nil {
+			// Undo the blobs already stored so a failed upload leaves nothing behind
+			for _, stored := range pending {
+				s.removeBlobs(stored)
+			}
+			return nil, err
+		}
+		pending = append(pending, photo)
+	}
+
+	photos, err := s.photoRepository.CreateAll(review.Id, pending, s.config.MaxPerReview)
+	if err != nil {
+		for _, stored := range pending {
+			s.removeBlobs(stored)
+		}
+		var limitErr *db.PhotoLimitError
+		if errors.As(err, &limitErr) {
+			return nil, s.photoLimitError(limitErr.Existing)
+		}
+		return nil, err
+	}
+	for _, photo := range photos {
+		setPhotoUrls(photo)
+	}
+
+	fmt.Println("Review photos uploaded successfully:", len(photos))
+	return photos, nil
+}
+
+func (s *ReviewPhotoServiceImpl) photoLimitError(existing int) error {
+	return &utils.AppError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    fmt.Sprintf("a review can have at most %d photos", s.config.MaxPerReview),
+		Data:       map[string]int{"limit": s.config.MaxPerReview, "existing": existing},
+	}
+}
+
+func (s *ReviewPhotoServiceImpl) process(file *multipart.FileHeader) (*imaging.Processed, error) {
+	tooLarge := utils.NewPayloadTooLargeError(fmt.Sprintf("%s is larger than %d bytes", file.Filename, s.config.MaxBytes), map[string]int64{"maxBytes": s.config.MaxBytes})
+	if file.Size > s.config.MaxBytes {
+		return nil, tooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		fmt.Println("Error opening uploaded photo:", err)
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, s.config.MaxBytes+1))
+	if err != nil {
+		fmt.Println("Error reading uploaded photo:", err)
+		return nil, err
+	}
+	if int64(len(data)) > s.config.MaxBytes {
+		return nil, tooLarge
+	}
+
+	img, err := imaging.Process(data, s.config.MaxPixels, s.config.ThumbnailSize)
+	if errors.Is(err, imaging.ErrUnsupportedType) || errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, utils.NewUnprocessableError(fmt.Sprintf("%s: %s", file.Filename, err.Error()))
+	}
+	if err != nil {
+		return nil, utils.NewUnprocessableError(fmt.Sprintf("%s is not a valid image", file.Filename))
+	}
+	return img, nil
+}
+
+// storeBlobs writes an image and its thumbnail to the blob store and returns the photo row to insert for them.
+func (s *ReviewPhotoServiceImpl) storeBlobs(review *models.Review, img *imaging.Processed) (*models.ReviewPhoto, error) {
+	name, err := randomBlobName()
+	if err != nil {
+		return nil, err
+	}
+	extension := ".jpg"
+	if img.ContentType == imaging.ContentTypePNG {
+		extension = ".png"
+	}
+	blobKey := fmt.Sprintf("reviews/%d/%s%s", review.Id, name, extension)
+	thumbnailKey := fmt.Sprintf("reviews/%d/%s_thumb%s", review.Id, name, extension)
+
+	ctx := context.Background()
+	if err := s.blobStore.Put(ctx, blobKey, img.ContentType, img.Data); err != nil {
+		fmt.Println("Error storing review photo:", err)
+		return nil, err
+	}
+	if err := s.blobStore.Put(ctx, thumbnailKey, img.ContentType, img.Thumbnail); err != nil {
+		fmt.Println("Error storing review photo thumbnail:", err)
+		s.blobStore.Delete(ctx, blobKey)
+		return nil, err
+	}
+
+	return &models.ReviewPhoto{
+		ReviewId:     review.Id,
+		UserId:       review.UserId,
+		BlobKey:      blobKey,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  img.ContentType,
+		SizeBytes:    len(img.Data),
+		Width:        img.Width,
+		Height:       img.Height,
+	}, nil
+}
+
+// remove deletes a photo's row and blobs. Blob failures are only logged; the row no longer points at them.
+func (s *ReviewPhotoServiceImpl) remove(photo *models.ReviewPhoto) error {
+	if err := s.photoRepository.Delete(photo.Id); err != nil {
+		fmt.Println("Error deleting review photo:", err)
+		return err
+	}
+
+	s.removeBlobs(photo)
+	return nil
+}
+
+func (s *ReviewPhotoServiceImpl) removeBlobs(photo *models.ReviewPhoto) {
+	ctx := context.Background()
+	for _, key := range []string{photo.BlobKey, photo.ThumbnailKey} {
+		if err := s.blobStore.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrBlobNotFound) {
+			fmt.Println("Error deleting review photo blob:", key, err)
+		}
+	}
+}
+
+func (s *ReviewPhotoServiceImpl) GetPhotos(reviewId string) ([]*models.ReviewPhoto, error) {
+	fmt.Println("Fetching review photos in ReviewPhotoService")
+
+	review, err := s.getPublicReview(reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := s.photoRepository.GetByReviewId(review.Id)
+	if err != nil {
+		return nil, err
+	}
+	if photos == nil {
+		photos = []*models.ReviewPhoto{}
+	}
+	for _, photo := range photos {
+		setPhotoUrls(photo)
+	}
+	return photos, nil
+}
+
+func (s *ReviewPhotoServiceImpl) OpenPhoto(reviewId string, photoId string, thumbnail bool) (io.ReadCloser, string, error) {
+	review, err := s.getPublicReview(reviewId)
+	if err != nil {
+		return nil, "", err
+	}
+	photo, err := s.getPhoto(review, photoId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := photo.BlobKey
+	if thumbnail {
+		key = photo.ThumbnailKey
+	}
+
+	body, contentType, err := s.blobStore.Get(context.Background(), key)
+	if errors.Is(err, blobstore.ErrBlobNotFound) {
+		return nil, "", utils.NewNotFoundError(fmt.Sprintf("photo %d is missing from storage", photo.Id))
+	}
+	if err != nil {
+		fmt.Println("Error reading review photo:", err)
+		return nil, "", err
+	}
+	return body, contentType, nil
+}
+
+func (s *ReviewPhotoServiceImpl) DeletePhoto(reviewId string, photoId string, caller *models.AuthUser) error {
+	fmt.Println("Deleting review photo in ReviewPhotoService")
+
+	review, err := s.getReview(reviewId)
+	if err != nil {
+		return err
+	}
+	if review.UserId != caller.Id && !caller.HasPermission(ModeratorPermission) {
+		return utils.NewForbiddenError("you can only delete photos from your own reviews")
+	}
+
+	photo, err := s.getPhoto(review, photoId)
+	if err != nil {
+		return err
+	}
+	if err := s.remove(photo); err != nil {
+		return err
+	}
+
+	fmt.Println("Review photo deleted successfully:", photo.Id)
+	return nil
+}
+
+func (s *ReviewPhotoServiceImpl) getReview(reviewId string) (*models.Review, error) {
+	idInt, err := strconv.ParseInt(reviewId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := s.reviewRepository.GetByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+	return review, nil
+}
+
+// getPublicReview is getReview for unauthenticated reads, which only see approved reviews.
+func (s *ReviewPhotoServiceImpl) getPublicReview(reviewId string) (*models.Review, error) {
+	review, err := s.getReview(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if review.ModerationStatus != models.ModerationApproved {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", review.Id))
+	}
+	return review, nil
+}
+
+func (s *ReviewPhotoServiceImpl) getPhoto(review *models.Review, photoId string) (*models.ReviewPhoto, error) {
+	idInt, err := strconv.ParseInt(photoId, 10, 64)
+	if err != nil {
+		return nil, utils.NewBadRequestError("invalid photo ID")
+	}
+
+	photo, err := s.photoRepository.GetById(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review photo:", err)
+		return nil, err
+	}
+	if photo == nil || photo.ReviewId != review.Id {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("photo %d not found on review %d", idInt, review.Id))
+	}
+	return photo, nil
+}
+
+func randomBlobName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func setPhotoUrls(photo *models.ReviewPhoto) {
+	photo.Url = fmt.Sprintf("/reviews/%d/photos/%d", photo.ReviewId, photo.Id)
+	photo.ThumbnailUrl = photo.Url + "/thumbnail"
+}
+
+// attachPhotos embeds the live photos into each review.
+func attachPhotos(photoRepository db.ReviewPhotoRepository, reviews []*models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.Id
+	}
+
+	photos, err := photoRepository.GetByReviewIds(ids)
+	if err != nil {
+		fmt.Println("Error fetching review photos:", err)
+		return err
+	}
+
+	for _, review := range reviews {
+		review.Photos = photos[review.Id]
+		for _, photo := range review.Photos {
+			setPhotoUrls(photo)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"ReviewService/blobstore"
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// photoReviews serves a single review; any other ReviewRepository call panics.
+type photoReviews struct {
+	db.ReviewRepository
+	review *models.Review
+}
+
+func (r *photoReviews) GetByID(id int64) (*models.Review, error) {
+	if id != r.review.Id {
+		return nil, nil
+	}
+	return r.review, nil
+}
+
+// memoryPhotos is a ReviewPhotoRepository on a slice. staleCount makes CountByReviewId report that many photos,
+// like a count read before a concurrent upload committed.
+type memoryPhotos struct {
+	db.ReviewPhotoRepository
+	mu         sync.Mutex
+	photos     []*models.ReviewPhoto
+	staleCount *int
+}
+
+func (m *memoryPhotos) CountByReviewId(reviewId int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.staleCount != nil {
+		return *m.staleCount, nil
+	}
+	return len(m.photos), nil
+}
+
+func (m *memoryPhotos) CreateAll(reviewId int64, photos []*models.ReviewPhoto, limit int) ([]*models.ReviewPhoto, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.photos)+len(photos) > limit {
+		return nil, &db.PhotoLimitError{Limit: limit, Existing: len(m.photos)}
+	}
+	for _, photo := range photos {
+		photo.Id = int64(len(m.photos) + 1)
+		m.photos = append(m.photos, photo)
+	}
+	return photos, nil
+}
+
+func pngBytes(t *testing.T, width int, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encoding PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// photoUpload builds the multipart file headers of an upload with one "photos" part per file.
+func photoUpload(t *testing.T, files ...[]byte) []*multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, data := range files {
+		part, err := writer.CreateFormFile("photos", fmt.Sprintf("photo%d.png", i))
+		if err != nil {
+			t.Fatalf("creating form file: %v", err)
+		}
+		part.Write(data)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/reviews/1/photos", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatalf("parsing upload: %v", err)
+	}
+	return req.MultipartForm.File["photos"]
+}
+
+func newTestPhotoService(t *testing.T, photos *memoryPhotos) (ReviewPhotoService, string) {
+	t.Helper()
+	root := t.TempDir()
+	store, err := blobstore.NewLocalBlobStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore: %v", err)
+	}
+	reviews := &photoReviews{review: &models.Review{Id: 1, UserId: 10, ModerationStatus: models.ModerationApproved}}
+	config := PhotoConfig{MaxBytes: 4096, MaxPerReview: 3, MaxPixels: 10_000, ThumbnailSize: 8}
+	return NewReviewPhotoService(reviews, photos, store, config), root
+}
+
+// storedBlobs counts the blobs the local blob store holds, without their content type sidecars.
+func storedBlobs(t *testing.T, root string) int {
+	t.Helper()
+	count := 0
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && filepath.Ext(path) != ".type" {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestUploadPhotos(t *testing.T) {
+	photos := &memoryPhotos{}
+	service, root := newTestPhotoService(t, photos)
+
+	uploaded, err := service.UploadPhotos("1", &models.AuthUser{Id: 10}, photoUpload(t, pngBytes(t, 16, 8), pngBytes(t, 4, 4)))
+	if err != nil {
+		t.Fatalf("UploadPhotos: %v", err)
+	}
+	if len(uploaded) != 2 || len(photos.photos) != 2 {
+		t.Fatalf("uploaded %d photos and stored %d, want 2", len(uploaded), len(photos.photos))
+	}
+	if uploaded[0].Width != 16 || uploaded[0].Height != 8 || uploaded[0].Url != "/reviews/1/photos/1" {
+		t.Errorf("got photo %+v", uploaded[0])
+	}
+	if got := storedBlobs(t, root); got != 4 {
+		t.Errorf("stored %d blobs, want a photo and a thumbnail each", got)
+	}
+}
+
+func TestUploadPhotosRejections(t *testing.T) {
+	stale := 0
+	tests := []struct {
+		name     string
+		uploader int64
+		existing int
+		stale    *int
+		files    func(t *testing.T) [][]byte
+		status   int
+	}{
+		{"not the author", 11, 0, nil, func(t *testing.T) [][]byte { return [][]byte{pngBytes(t, 4, 4)} }, http.StatusForbidden},
+		{"no files", 10, 0, nil, func(t *testing.T) [][]byte { return nil }, http.StatusBadRequest},
+		{"file too large", 10, 0, nil, func(t *testing.T) [][]byte { return [][]byte{pngBytes(t, 4, 4), make([]byte, 4097)} }, http.StatusRequestEntityTooLarge},
+		{"not an image", 10, 0, nil, func(t *testing.T) [][]byte { return [][]byte{[]byte("GIF89a not a photo")} }, http.StatusUnprocessableEntity},
+		{"too many pixels", 10, 0, nil, func(t *testing.T) [][]byte { return [][]byte{pngBytes(t, 200, 51)} }, http.StatusUnprocessableEntity},
+		{"over the count limit", 10, 2, nil, func(t *testing.T) [][]byte { return [][]byte{pngBytes(t, 4, 4), pngBytes(t, 4, 4)} }, http.StatusUnprocessableEntity},
+		// Another upload committed after the first count, so only the locked count inside CreateAll catches it
+		{"over the limit after a concurrent upload", 10, 2, &stale, func(t *testing.T) [][]byte { return [][]byte{pngBytes(t, 4, 4), pngBytes(t, 4, 4)} }, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			photos := &memoryPhotos{staleCount: tt.stale}
+			for i := 0; i < tt.existing; i++ {
+				photos.photos = append(photos.photos, &models.ReviewPhoto{Id: int64(i + 1), ReviewId: 1})
+			}
+			service, root := newTestPhotoService(t, photos)
+
+			var files []*multipart.FileHeader
+			if data := tt.files(t); len(data) > 0 {
+				files = photoUpload(t, data...)
+			}
+			_, err := service.UploadPhotos("1", &models.AuthUser{Id: tt.uploader}, files)
+			if got := utils.StatusFromError(err, 0); got != tt.status {
+				t.Errorf("got status %d (%v), want %d", got, err, tt.status)
+			}
+			if len(photos.photos) != tt.existing || storedBlobs(t, root) != 0 {
+				t.Errorf("a rejected upload left %d photos and %d blobs", len(photos.photos)-tt.existing, storedBlobs(t, root))
+			}
+		})
+	}
+}
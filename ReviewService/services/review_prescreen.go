@@ -0,0 +1,62 @@
+package services
+
+import (
+	"ReviewService/models"
+	"regexp"
+	"strings"
+)
+
+type PrescreenConfig struct {
+	BannedTerms []string // matched case-insensitively as whole words or phrases
+	AutoApprove bool     // publish reviews that pass the pre-screen; when false every review waits for a moderator
+}
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|io|in|co|info|biz|xyz)\b`)
+	phonePattern = regexp.MustCompile(`(?:\+?\d[\s\-.()]*){9,}\d`)
+)
+
+// ReviewPrescreener decides the initial moderation status of a new or edited review.
+type ReviewPrescreener struct {
+	config PrescreenConfig
+	banned *regexp.Regexp
+}
+
+func NewReviewPrescreener(_config PrescreenConfig) *ReviewPrescreener {
+	p := &ReviewPrescreener{config: _config}
+
+	terms := make([]string, 0, len(_config.BannedTerms))
+	for _, term := range _config.BannedTerms {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, regexp.QuoteMeta(term))
+		}
+	}
+	if len(terms) > 0 {
+		p.banned = regexp.MustCompile(`(?i)\b(?:` + strings.Join(terms, "|") + `)\b`)
+	}
+
+	return p
+}
+
+// Screen returns the status a review should start in and, when it is held back, why.
+func (p *ReviewPrescreener) Screen(comment string) (string, *string) {
+	var reasons []string
+	if p.banned != nil && p.banned.MatchString(comment) {
+		reasons = append(reasons, "contains banned terms")
+	}
+	if linkPattern.MatchString(comment) {
+		reasons = append(reasons, "contains a link")
+	}
+	if phonePattern.MatchString(comment) {
+		reasons = append(reasons, "contains a phone number")
+	}
+
+	if len(reasons) > 0 {
+		reason := "held for review: " + strings.Join(reasons, ", ")
+		return models.ModerationPending, &reason
+	}
+	if !p.config.AutoApprove {
+		return models.ModerationPending, nil
+	}
+	return models.ModerationApproved, nil
+}
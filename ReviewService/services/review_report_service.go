@@ -0,0 +1,109 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReportConfig sets how much reports count. A report weighs the weight of its reason, times HostWeight when the
+// reporter can act for the reviewed hotel, since hosts have a stake in the reviews of their own hotel.
+type ReportConfig struct {
+	HideThreshold float64 // open report weight at which a published review is hidden until a moderator decides
+	ReasonWeights map[string]float64
+	HostWeight    float64
+}
+
+type ReviewReportService interface {
+	Report(reviewId string, reporter *models.AuthUser, payload *dto.ReviewReportRequestDTO) (*models.ReviewReport, error)
+}
+
+type ReviewReportServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	reportRepository db.ReviewReportRepository
+	hotelAuthorizer  HotelAuthorizer
+	searchIndex      search.ReviewSearchIndex
+	config           ReportConfig
+}
+
+func NewReviewReportService(_reviewRepository db.ReviewRepository, _reportRepository db.ReviewReportRepository, _hotelAuthorizer HotelAuthorizer, _searchIndex search.ReviewSearchIndex, _config ReportConfig) ReviewReportService {
+	return &ReviewReportServiceImpl{
+		reviewRepository: _reviewRepository,
+		reportRepository: _reportRepository,
+		hotelAuthorizer:  _hotelAuthorizer,
+		searchIndex:      _searchIndex,
+		config:           _config,
+	}
+}
+
+// Report records the caller's report of a published review. Each user can report a review once.
+func (s *ReviewReportServiceImpl) Report(reviewId string, reporter *models.AuthUser, payload *dto.ReviewReportRequestDTO) (*models.ReviewReport, error) {
+	fmt.Println("Reporting review in ReviewReportService")
+
+	idInt, err := strconv.ParseInt(reviewId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := s.reviewRepository.GetByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil || review.ModerationStatus != models.ModerationApproved {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+	if review.UserId == reporter.Id {
+		return nil, utils.NewForbiddenError("you cannot report your own review")
+	}
+
+	report := &models.ReviewReport{
+		ReviewId:   idInt,
+		ReporterId: reporter.Id,
+		Reason:     payload.Reason,
+		Weight:     s.reportWeight(payload.Reason, reporter, review.HotelId),
+	}
+	if details := strings.TrimSpace(payload.Details); details != "" {
+		report.Details = &details
+	}
+
+	hideReason := fmt.Sprintf("hidden after reports reached weight %g", s.config.HideThreshold)
+	created, hidden, err := s.reportRepository.Create(report, s.config.HideThreshold, hideReason)
+	if errors.Is(err, db.ErrDuplicateEntry) {
+		existing, _ := s.reportRepository.GetReport(idInt, reporter.Id)
+		return nil, utils.NewConflictError("you have already reported this review", existing)
+	}
+	if err != nil {
+		fmt.Println("Error recording report:", err)
+		return nil, err
+	}
+
+	if hidden {
+		fmt.Println("Review hidden after reports:", idInt)
+		if updated, err := s.reviewRepository.GetByID(idInt); err == nil && updated != nil {
+			if err := s.searchIndex.Index(updated); err != nil {
+				fmt.Println("Error indexing review for search:", err)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+func (s *ReviewReportServiceImpl) reportWeight(reason string, reporter *models.AuthUser, hotelId int64) float64 {
+	weight, ok := s.config.ReasonWeights[reason]
+	if !ok {
+		weight = 1
+	}
+	if s.hotelAuthorizer.CanRespond(reporter, hotelId) {
+		weight *= s.config.HostWeight
+	}
+	return weight
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/notifications"
+	"ReviewService/utils"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type ReviewResponseService interface {
+	CreateResponse(reviewId string, responder *models.AuthUser, payload *dto.ReviewResponseRequestDTO) (*models.ReviewResponse, error)
+	UpdateResponse(reviewId string, responder *models.AuthUser, payload *dto.ReviewResponseRequestDTO) (*models.ReviewResponse, error)
+	DeleteResponse(reviewId string, responder *models.AuthUser) error
+}
+
+type ReviewResponseServiceImpl struct {
+	reviewRepository   db.ReviewRepository
+	responseRepository db.ReviewResponseRepository
+	hotelAuthorizer    HotelAuthorizer
+	notifier           notifications.Notifier
+}
+
+func NewReviewResponseService(_reviewRepository db.ReviewRepository, _responseRepository db.ReviewResponseRepository, _hotelAuthorizer HotelAuthorizer, _notifier notifications.Notifier) ReviewResponseService {
+	return &ReviewResponseServiceImpl{
+		reviewRepository:   _reviewRepository,
+		responseRepository: _responseRepository,
+		hotelAuthorizer:    _hotelAuthorizer,
+		notifier:           _notifier,
+	}
+}
+
+func (s *ReviewResponseServiceImpl) CreateResponse(reviewId string, responder *models.AuthUser, payload *dto.ReviewResponseRequestDTO) (*models.ReviewResponse, error) {
+	fmt.Println("Creating review response in ReviewResponseService")
+
+	review, err := s.authorizeResponder(reviewId, responder)
+	if err != nil {
+		return nil, err
+	}
+	if review.ModerationStatus != models.ModerationApproved {
+		return nil, utils.NewUnprocessableError("only published reviews can be responded to")
+	}
+
+	response, err := s.responseRepository.Create(&models.ReviewResponse{
+		ReviewId:    review.Id,
+		ResponderId: responder.Id,
+		Body:        payload.Body,
+	})
+	if errors.Is(err, db.ErrDuplicateEntry) {
+		existing, _ := s.responseRepository.GetByReviewId(review.Id)
+		return nil, utils.NewConflictError("this review already has a response", existing)
+	}
+	if err != nil {
+		fmt.Println("Error creating review response:", err)
+		return nil, err
+	}
+
+	// The response is already public, so a failed notification is only logged
+	err = s.notifier.Notify(&notifications.Notification{
+		Key:        fmt.Sprintf("review-response-%d", response.Id),
+		UserId:     review.UserId,
+		Subject:    "The hotel responded to your review",
+		TemplateId: notifications.TemplateReviewResponse,
+		Params: map[string]any{
+			"reviewId": review.Id,
+			"hotelId":  review.HotelId,
+			"response": response.Body,
+		},
+	})
+	if err != nil {
+		fmt.Println("Error notifying guest of review response:", err)
+	}
+
+	fmt.Println("Review response created successfully:", response.Id)
+	return response, nil
+}
+
+func (s *ReviewResponseServiceImpl) UpdateResponse(reviewId string, responder *models.AuthUser, payload *dto.ReviewResponseRequestDTO) (*models.ReviewResponse, error) {
+	fmt.Println("Updating review response in ReviewResponseService")
+
+	existing, err := s.getResponse(reviewId, responder)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.responseRepository.Update(existing.Id, payload.Body)
+	if err != nil {
+		fmt.Println("Error updating review response:", err)
+		return nil, err
+	}
+
+	fmt.Println("Review response updated successfully:", response.Id)
+	return response, nil
+}
+
+func (s *ReviewResponseServiceImpl) DeleteResponse(reviewId string, responder *models.AuthUser) error {
+	fmt.Println("Deleting review response in ReviewResponseService")
+
+	existing, err := s.getResponse(reviewId, responder)
+	if err != nil {
+		return err
+	}
+
+	if err := s.responseRepository.Delete(existing.Id); err != nil {
+		fmt.Println("Error deleting review response:", err)
+		return err
+	}
+
+	fmt.Println("Review response deleted successfully:", existing.Id)
+	return nil
+}
+
+func (s *ReviewResponseServiceImpl) getResponse(reviewId string, responder *models.AuthUser) (*models.ReviewResponse, error) {
+	review, err := s.authorizeResponder(reviewId, responder)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.responseRepository.GetByReviewId(review.Id)
+	if err != nil {
+		fmt.Println("Error fetching review response:", err)
+		return nil, err
+	}
+	if response == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review %d has no response", review.Id))
+	}
+	return response, nil
+}
+
+// authorizeResponder loads the review and checks that the caller may respond for its hotel. Moderators may always.
+func (s *ReviewResponseServiceImpl) authorizeResponder(reviewId string, responder *models.AuthUser) (*models.Review, error) {
+	idInt, err := strconv.ParseInt(reviewId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := s.reviewRepository.GetByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+
+	if !s.hotelAuthorizer.CanRespond(responder, review.HotelId) && !responder.HasPermission(ModeratorPermission) {
+		return nil, utils.NewForbiddenError("only the hotel's hosts can respond to this review")
+	}
+
+	return review, nil
+}
+
+// attachResponses embeds the live host response into each review.
+func attachResponses(responseRepository db.ReviewResponseRepository, reviews []*models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.Id
+	}
+
+	responses, err := responseRepository.GetByReviewIds(ids)
+	if err != nil {
+		fmt.Println("Error fetching review responses:", err)
+		return err
+	}
+
+	for _, review := range reviews {
+		review.Response = responses[review.Id]
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/notifications"
+	"ReviewService/utils"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// memoryResponses is a ReviewResponseRepository on a map that, like the unique index on live responses,
+// refuses a second live response to a review.
+type memoryResponses struct {
+	db.ReviewResponseRepository
+	byReview map[int64]*models.ReviewResponse
+	nextId   int64
+}
+
+func newMemoryResponses() *memoryResponses {
+	return &memoryResponses{byReview: map[int64]*models.ReviewResponse{}}
+}
+
+func (m *memoryResponses) Create(response *models.ReviewResponse) (*models.ReviewResponse, error) {
+	if _, ok := m.byReview[response.ReviewId]; ok {
+		return nil, db.ErrDuplicateEntry
+	}
+	m.nextId++
+	created := *response
+	created.Id = m.nextId
+	m.byReview[response.ReviewId] = &created
+	return &created, nil
+}
+
+func (m *memoryResponses) GetByReviewId(reviewId int64) (*models.ReviewResponse, error) {
+	return m.byReview[reviewId], nil
+}
+
+func (m *memoryResponses) Update(id int64, body string) (*models.ReviewResponse, error) {
+	for _, response := range m.byReview {
+		if response.Id == id {
+			response.Body = body
+			return response, nil
+		}
+	}
+	return nil, errors.New("response not found")
+}
+
+func (m *memoryResponses) Delete(id int64) error {
+	for reviewId, response := range m.byReview {
+		if response.Id == id {
+			delete(m.byReview, reviewId)
+			return nil
+		}
+	}
+	return errors.New("response not found")
+}
+
+// failingNotifier fails every notification.
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(notification *notifications.Notification) error {
+	return errors.New("redis unavailable")
+}
+
+var (
+	hotelHost         = &models.AuthUser{Id: 20, Permissions: []string{"hotel:100:respond"}}
+	otherHost         = &models.AuthUser{Id: 21, Permissions: []string{"hotel:200:respond"}}
+	responseModerator = &models.AuthUser{Id: 30, Permissions: []string{ModeratorPermission}}
+	guest             = &models.AuthUser{Id: 10}
+)
+
+func newTestResponseService(status string, notifier notifications.Notifier) (ReviewResponseService, *memoryResponses) {
+	reviews := &photoReviews{review: &models.Review{Id: 1, UserId: 10, HotelId: 100, ModerationStatus: status}}
+	responses := newMemoryResponses()
+	return NewReviewResponseService(reviews, responses, NewClaimsHotelAuthorizer(), notifier), responses
+}
+
+func TestCreateResponseAuthorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		reviewId string
+		caller   *models.AuthUser
+		status   string
+		want     int
+	}{
+		{"host of the hotel", "1", hotelHost, models.ModerationApproved, http.StatusOK},
+		{"moderator", "1", responseModerator, models.ModerationApproved, http.StatusOK},
+		{"host of another hotel", "1", otherHost, models.ModerationApproved, http.StatusForbidden},
+		{"review author", "1", guest, models.ModerationApproved, http.StatusForbidden},
+		{"unpublished review", "1", hotelHost, models.ModerationPending, http.StatusUnprocessableEntity},
+		{"unknown review", "2", hotelHost, models.ModerationApproved, http.StatusNotFound},
+		{"invalid review id", "abc", hotelHost, models.ModerationApproved, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestResponseService(tt.status, notifications.NewInMemoryNotifier())
+
+			_, err := service.CreateResponse(tt.reviewId, tt.caller, &dto.ReviewResponseRequestDTO{Body: "Thank you!"})
+			if got := utils.StatusFromError(err, http.StatusOK); got != tt.want {
+				t.Errorf("got status %d (%v), want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateResponseOncePerReview(t *testing.T) {
+	service, responses := newTestResponseService(models.ModerationApproved, notifications.NewInMemoryNotifier())
+
+	first, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Thank you!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = service.CreateResponse("1", responseModerator, &dto.ReviewResponseRequestDTO{Body: "Thanks again"})
+	if utils.StatusFromError(err, 0) != http.StatusConflict {
+		t.Fatalf("second response got %v, want a 409", err)
+	}
+	if responses.byReview[1].Body != first.Body {
+		t.Errorf("stored response %q, want the first one kept", responses.byReview[1].Body)
+	}
+
+	// Once deleted, the review can be responded to again
+	if err := service.DeleteResponse("1", hotelHost); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Updated thanks"}); err != nil {
+		t.Errorf("response after delete got %v", err)
+	}
+}
+
+func TestUpdateAndDeleteResponseAuthorization(t *testing.T) {
+	service, _ := newTestResponseService(models.ModerationApproved, notifications.NewInMemoryNotifier())
+
+	if _, err := service.UpdateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Edited"}); utils.StatusFromError(err, 0) != http.StatusNotFound {
+		t.Errorf("update without a response got %v, want a 404", err)
+	}
+	if _, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Thank you!"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.UpdateResponse("1", otherHost, &dto.ReviewResponseRequestDTO{Body: "Edited"}); utils.StatusFromError(err, 0) != http.StatusForbidden {
+		t.Errorf("update by another hotel's host got %v, want a 403", err)
+	}
+	if err := service.DeleteResponse("1", guest); utils.StatusFromError(err, 0) != http.StatusForbidden {
+		t.Errorf("delete by the review author got %v, want a 403", err)
+	}
+	if updated, err := service.UpdateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Edited"}); err != nil || updated.Body != "Edited" {
+		t.Errorf("update by the host got %v, %v", updated, err)
+	}
+}
+
+func TestCreateResponseNotifiesGuest(t *testing.T) {
+	notifier := notifications.NewInMemoryNotifier()
+	service, _ := newTestResponseService(models.ModerationApproved, notifier)
+
+	response, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Thank you!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := notifier.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("%d notifications sent, want 1", len(sent))
+	}
+	notification := sent[0]
+	if notification.UserId != 10 || notification.TemplateId != notifications.TemplateReviewResponse || notification.Key != "review-response-1" {
+		t.Errorf("notification %+v, want the review_response template for the guest", notification)
+	}
+	if notification.Params["reviewId"] != int64(1) || notification.Params["response"] != response.Body {
+		t.Errorf("template params %v", notification.Params)
+	}
+
+	// A rejected response creates no notification
+	if _, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Again"}); err == nil || len(notifier.Sent()) != 1 {
+		t.Errorf("duplicate response got %v with %d notifications", err, len(notifier.Sent()))
+	}
+}
+
+func TestCreateResponseSurvivesNotificationFailure(t *testing.T) {
+	service, responses := newTestResponseService(models.ModerationApproved, failingNotifier{})
+
+	if _, err := service.CreateResponse("1", hotelHost, &dto.ReviewResponseRequestDTO{Body: "Thank you!"}); err != nil {
+		t.Fatalf("got %v, want the response kept despite the failed notification", err)
+	}
+	if responses.byReview[1] == nil {
+		t.Error("response was not stored")
+	}
+}
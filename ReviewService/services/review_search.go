@@ -0,0 +1,73 @@
+package services
+
+import (
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/utils"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const MaxSearchLimit = 50
+
+func (r *ReviewServiceImpl) SearchReviews(query *dto.ReviewSearchQuery) ([]*dto.ReviewSearchHitDTO, error) {
+	fmt.Println("Searching reviews in ReviewService")
+
+	text := strings.TrimSpace(query.Q)
+	if text == "" {
+		return nil, utils.NewBadRequestError("search query is required")
+	}
+
+	searchQuery := search.SearchQuery{Text: text, Limit: query.Limit}
+	if searchQuery.Limit == 0 {
+		searchQuery.Limit = search.DefaultSearchLimit
+	}
+	if searchQuery.Limit < 0 || searchQuery.Limit > MaxSearchLimit {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit))
+	}
+
+	if query.HotelId != "" {
+		hotelId, err := strconv.ParseInt(query.HotelId, 10, 64)
+		if err != nil {
+			return nil, utils.NewBadRequestError("invalid hotel ID")
+		}
+		searchQuery.HotelId = &hotelId
+	}
+
+	hits, err := r.searchIndex.Search(searchQuery)
+	if err != nil {
+		fmt.Println("Error searching reviews:", err)
+		return nil, err
+	}
+
+	reviews := make([]*models.Review, len(hits))
+	for i, hit := range hits {
+		reviews[i] = hit.Review
+	}
+	if err := r.details.Attach(reviews); err != nil {
+		return nil, err
+	}
+
+	results := make([]*dto.ReviewSearchHitDTO, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, &dto.ReviewSearchHitDTO{
+			Review:  hit.Review,
+			Score:   hit.Score,
+			Snippet: search.Snippet(hit.Review.Comment, text),
+		})
+	}
+	return results, nil
+}
+
+// indexReview keeps the search index current after a write. A failure is logged, not returned,
+// since the review itself has already been stored.
+func (r *ReviewServiceImpl) indexReview(review *models.Review) {
+	if review == nil {
+		return
+	}
+	if err := r.searchIndex.Index(review); err != nil {
+		fmt.Println("Error indexing review for search:", err)
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/models"
+	"ReviewService/sentiment"
+)
+
+// applySentiment analyzes the review's comment and stores the result on the review.
+func applySentiment(analyzer *sentiment.Analyzer, review *models.Review) {
+	analysis := analyzer.Analyze(review.Comment)
+	version := sentiment.Version
+	review.SentimentScore = &analysis.Score
+	review.SentimentVersion = &version
+	review.Aspects = analysis.Aspects
+}
+
+// SentimentBackfillServiceImpl analyzes reviews stored before sentiment analysis existed, or analyzed with an older
+// version of it.
+type SentimentBackfillServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	analyzer         *sentiment.Analyzer
+}
+
+func NewSentimentBackfillService(_reviewRepository db.ReviewRepository, _analyzer *sentiment.Analyzer) BackfillService {
+	return &SentimentBackfillServiceImpl{
+		reviewRepository: _reviewRepository,
+		analyzer:         _analyzer,
+	}
+}
+
+func (s *SentimentBackfillServiceImpl) Backfill(batchSize int) (*BackfillReport, error) {
+	backfill := &reviewBackfill{
+		name: "Sentiment",
+		done: "analyzed",
+		fetch: func(afterId int64, limit int) ([]*models.Review, error) {
+			return s.reviewRepository.GetUnanalyzed(sentiment.Version, afterId, limit)
+		},
+		apply: func(review *models.Review) (bool, error) {
+			applySentiment(s.analyzer, review)
+			return s.reviewRepository.SetSentiment(review.Id, *review.SentimentScore, *review.SentimentVersion, review.Aspects)
+		},
+	}
+	return backfill.run(batchSize)
+}
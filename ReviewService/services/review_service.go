@@ -1,31 +1,57 @@
 package services
 
 import (
+	"ReviewService/clients"
 	db "ReviewService/db/repositories"
 	"ReviewService/dto"
+	"ReviewService/langid"
 	"ReviewService/models"
+	"ReviewService/search"
+	"ReviewService/sentiment"
+	"ReviewService/utils"
+	"errors"
 	"fmt"
+	"maps"
 	"strconv"
+	"strings"
 )
 
+// ModeratorPermission lets a caller edit or delete reviews written by other users.
+const ModeratorPermission = "review:moderate"
+
 type ReviewService interface {
 	GetReviewById(id string) (*models.Review, error)
-	CreateReview(payload *dto.CreateReviewRequestDTO) (*models.Review, error)
-	UpdateReview(id string, payload *dto.UpdateReviewRequestDTO) (*models.Review, error)
-	DeleteReview(id string) error
-	GetAllReviews() ([]*models.Review, error)
-	GetReviewsByUserId(userId string) ([]*models.Review, error)
-	GetReviewsByHotelId(hotelId string) ([]*models.Review, error)
+	CreateReview(author *models.AuthUser, payload *dto.CreateReviewRequestDTO) (*models.Review, error)
+	UpdateReview(id string, caller *models.AuthUser, payload *dto.UpdateReviewRequestDTO) (*models.Review, error)
+	DeleteReview(id string, caller *models.AuthUser) error
+	GetAllReviews(query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error)
+	GetReviewsByUserId(userId string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error)
+	GetReviewsByHotelId(hotelId string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error)
 	GetReviewsByBookingId(bookingId string) ([]*models.Review, error)
+	SearchReviews(query *dto.ReviewSearchQuery) ([]*dto.ReviewSearchHitDTO, error)
 }
 
 type ReviewServiceImpl struct {
 	reviewRepository db.ReviewRepository
+	details          *ReviewDetailsLoader
+	bookingClient    clients.BookingClient
+	searchIndex      search.ReviewSearchIndex
+	prescreener      *ReviewPrescreener
+	analyzer         *sentiment.Analyzer
+	fraudScreener    *FraudScreener
+	identifier       *langid.Identifier
 }
 
-func NewReviewService(_reviewRepository db.ReviewRepository) ReviewService {
+func NewReviewService(_reviewRepository db.ReviewRepository, _details *ReviewDetailsLoader, _bookingClient clients.BookingClient, _searchIndex search.ReviewSearchIndex, _prescreener *ReviewPrescreener, _analyzer *sentiment.Analyzer, _fraudScreener *FraudScreener, _identifier *langid.Identifier) ReviewService {
 	return &ReviewServiceImpl{
 		reviewRepository: _reviewRepository,
+		details:          _details,
+		bookingClient:    _bookingClient,
+		searchIndex:      _searchIndex,
+		prescreener:      _prescreener,
+		analyzer:         _analyzer,
+		fraudScreener:    _fraudScreener,
+		identifier:       _identifier,
 	}
 }
 
@@ -43,60 +69,179 @@ func (r *ReviewServiceImpl) GetReviewById(id string) (*models.Review, error) {
 		fmt.Println("Error fetching review:", err)
 		return nil, err
 	}
+	if review == nil || review.ModerationStatus != models.ModerationApproved {
+		// Reviews that are not approved are not public
+		return nil, nil
+	}
+
+	if err := r.details.Attach([]*models.Review{review}); err != nil {
+		return nil, err
+	}
 	return review, nil
 }
 
-func (r *ReviewServiceImpl) CreateReview(payload *dto.CreateReviewRequestDTO) (*models.Review, error) {
+func (r *ReviewServiceImpl) CreateReview(author *models.AuthUser, payload *dto.CreateReviewRequestDTO) (*models.Review, error) {
 	fmt.Println("Creating review in ReviewService")
 
 	// Validate rating range
 	if payload.Rating < 1 || payload.Rating > 5 {
-		return nil, fmt.Errorf("rating must be between 1 and 5")
+		return nil, utils.NewBadRequestError("rating must be between 1 and 5")
+	}
+	for category, rating := range payload.CategoryRatings.ToMap() {
+		if rating < 1 || rating > 5 {
+			return nil, utils.NewBadRequestError(fmt.Sprintf("%s rating must be between 1 and 5", category))
+		}
+	}
+
+	// A booking can be reviewed only once by its guest
+	existing, err := r.reviewRepository.GetActiveByBookingAndUser(payload.BookingId, author.Id)
+	if err != nil {
+		fmt.Println("Error checking for an existing review:", err)
+		return nil, err
+	}
+	if existing != nil {
+		return nil, utils.NewConflictError("this booking has already been reviewed", existing)
+	}
+
+	// Only guests with a confirmed stay at this hotel may review it
+	booking, err := r.checkStayEligibility(author.Id, payload.BookingId, payload.HotelId)
+	if err != nil {
+		return nil, err
+	}
+
+	status, reason := r.prescreener.Screen(payload.Comment)
+
+	newReview := &models.Review{
+		UserId:           author.Id,
+		BookingId:        payload.BookingId,
+		HotelId:          payload.HotelId,
+		Comment:          payload.Comment,
+		Rating:           payload.Rating,
+		CategoryRatings:  payload.CategoryRatings.ToMap(),
+		IsVerifiedStay:   true,
+		ModerationStatus: status,
+		ModerationReason: reason,
+	}
+	applySentiment(r.analyzer, newReview)
+	applyFingerprint(newReview)
+	applyLanguage(r.identifier, newReview)
+
+	// Suspected fraud goes to moderation instead of being published
+	if err := r.fraudScreener.Screen(newReview, booking); err != nil {
+		fmt.Println("Error scoring review for fraud:", err)
+		return nil, err
 	}
 
 	// Call the repository to create the review
-	review, err := r.reviewRepository.Create(payload.UserId, payload.BookingId, payload.HotelId, payload.Comment, payload.Rating)
+	review, err := r.reviewRepository.Create(newReview)
+	if errors.Is(err, db.ErrDuplicateEntry) {
+		// A concurrent request created the review between the check above and the insert
+		existing, _ := r.reviewRepository.GetActiveByBookingAndUser(payload.BookingId, author.Id)
+		return nil, utils.NewConflictError("this booking has already been reviewed", existing)
+	}
 	if err != nil {
 		fmt.Println("Error creating review:", err)
 		return nil, err
 	}
 
+	r.indexReview(review)
+
+	if err := r.details.Attach([]*models.Review{review}); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Review created successfully:", review)
 	return review, nil
 }
 
-func (r *ReviewServiceImpl) UpdateReview(id string, payload *dto.UpdateReviewRequestDTO) (*models.Review, error) {
+func (r *ReviewServiceImpl) UpdateReview(id string, caller *models.AuthUser, payload *dto.UpdateReviewRequestDTO) (*models.Review, error) {
 	fmt.Println("Updating review in ReviewService")
 
 	idInt, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
 		fmt.Println("Error parsing review ID:", err)
-		return nil, fmt.Errorf("invalid review ID")
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	existing, err := r.authorizeChange(idInt, caller)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate rating range
 	if payload.Rating < 1 || payload.Rating > 5 {
-		return nil, fmt.Errorf("rating must be between 1 and 5")
+		return nil, utils.NewBadRequestError("rating must be between 1 and 5")
+	}
+	for category, rating := range payload.CategoryRatings.ToMap() {
+		if rating < 1 || rating > 5 {
+			return nil, utils.NewBadRequestError(fmt.Sprintf("%s rating must be between 1 and 5", category))
+		}
+	}
+
+	// Resubmitting the same content is not an edit, so it neither adds a revision nor marks the review edited
+	unchanged, err := r.contentUnchanged(existing, payload)
+	if err != nil {
+		return nil, err
+	}
+	if unchanged {
+		if err := r.details.Attach([]*models.Review{existing}); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	updated := *existing
+	updated.Comment = payload.Comment
+	updated.Rating = payload.Rating
+	updated.CategoryRatings = payload.CategoryRatings.ToMap()
+	applySentiment(r.analyzer, &updated)
+	applyFingerprint(&updated)
+	applyLanguage(r.identifier, &updated)
+	updated.ModeratedBy = nil
+	updated.ModeratedAt = nil
+	if existing.ModerationStatus == models.ModerationRejected || existing.ModerationStatus == models.ModerationHidden {
+		// Editing must not let an author republish a review a moderator or reports took down
+		reason := fmt.Sprintf("edited after being %s", existing.ModerationStatus)
+		updated.ModerationStatus, updated.ModerationReason = models.ModerationPending, &reason
+		updated.TakedownRequeued = true
+	} else if held, err := r.fraudScreener.IsHeld(existing); err != nil {
+		fmt.Println("Error checking fraud hold:", err)
+		return nil, err
+	} else if held || awaitsTakedownReview(existing) {
+		// A review held as suspected fraud or requeued after a takedown waits for a moderator, edited or not
+		updated.ModerationStatus, updated.ModerationReason = existing.ModerationStatus, existing.ModerationReason
+	} else {
+		updated.ModerationStatus, updated.ModerationReason = r.prescreener.Screen(payload.Comment)
 	}
 
 	// Call the repository to update the review
-	review, err := r.reviewRepository.Update(idInt, payload.Comment, payload.Rating)
+	review, err := r.reviewRepository.Update(&updated, caller.Id)
 	if err != nil {
 		fmt.Println("Error updating review:", err)
 		return nil, err
 	}
 
+	r.indexReview(review)
+
+	if err := r.details.Attach([]*models.Review{review}); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Review updated successfully:", review)
 	return review, nil
 }
 
-func (r *ReviewServiceImpl) DeleteReview(id string) error {
+func (r *ReviewServiceImpl) DeleteReview(id string, caller *models.AuthUser) error {
 	fmt.Println("Deleting review in ReviewService")
 
 	idInt, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
 		fmt.Println("Error parsing review ID:", err)
-		return fmt.Errorf("invalid review ID")
+		return utils.NewBadRequestError("invalid review ID")
+	}
+
+	if _, err := r.authorizeChange(idInt, caller); err != nil {
+		return err
 	}
 
 	// Call the repository to delete the review
@@ -106,53 +251,133 @@ func (r *ReviewServiceImpl) DeleteReview(id string) error {
 		return err
 	}
 
+	if err := r.searchIndex.Remove(idInt); err != nil {
+		fmt.Println("Error removing review from search index:", err)
+	}
+
 	fmt.Println("Review deleted successfully")
 	return nil
 }
 
-func (r *ReviewServiceImpl) GetAllReviews() ([]*models.Review, error) {
+// checkStayEligibility confirms with BookingService that the booking exists, belongs to the user,
+// is for the reviewed hotel and is confirmed, and returns it.
+func (r *ReviewServiceImpl) checkStayEligibility(userId int64, bookingId int64, hotelId int64) (*clients.Booking, error) {
+	booking, err := r.bookingClient.GetBooking(bookingId)
+	if err != nil {
+		fmt.Println("Error fetching booking:", err)
+		return nil, utils.NewServiceUnavailableError("could not verify booking, please try again later")
+	}
+
+	if booking == nil {
+		return nil, utils.NewUnprocessableError(fmt.Sprintf("booking %d does not exist", bookingId))
+	}
+	if booking.UserId != userId {
+		return nil, utils.NewForbiddenError("booking belongs to another user")
+	}
+	if booking.HotelId != hotelId {
+		return nil, utils.NewUnprocessableError(fmt.Sprintf("booking %d is not for hotel %d", bookingId, hotelId))
+	}
+	if booking.Status != clients.BookingStatusConfirmed {
+		return nil, utils.NewUnprocessableError(fmt.Sprintf("booking %d is %s, only confirmed bookings can be reviewed", bookingId, strings.ToLower(booking.Status)))
+	}
+
+	return booking, nil
+}
+
+func (r *ReviewServiceImpl) contentUnchanged(existing *models.Review, payload *dto.UpdateReviewRequestDTO) (bool, error) {
+	if existing.Comment != payload.Comment || existing.Rating != payload.Rating {
+		return false, nil
+	}
+	if payload.CategoryRatings == nil {
+		return true, nil
+	}
+
+	stored, err := r.reviewRepository.GetCategoryRatings([]int64{existing.Id})
+	if err != nil {
+		fmt.Println("Error fetching category ratings:", err)
+		return false, err
+	}
+	return maps.Equal(stored[existing.Id], payload.CategoryRatings.ToMap()), nil
+}
+
+// awaitsTakedownReview reports whether a pending review was requeued by editing it after a takedown. Such a review
+// stays pending through further edits until a moderator decides.
+func awaitsTakedownReview(review *models.Review) bool {
+	return review.ModerationStatus == models.ModerationPending && review.TakedownRequeued
+}
+
+// authorizeChange loads a review and checks that the caller is its author or a moderator.
+func (r *ReviewServiceImpl) authorizeChange(id int64, caller *models.AuthUser) (*models.Review, error) {
+	review, err := r.reviewRepository.GetByID(id)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", id))
+	}
+
+	if review.UserId != caller.Id && !caller.HasPermission(ModeratorPermission) {
+		return nil, utils.NewForbiddenError("only the author or a moderator can change this review")
+	}
+
+	return review, nil
+}
+
+func (r *ReviewServiceImpl) GetAllReviews(query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error) {
 	fmt.Println("Fetching all reviews in ReviewService")
 
-	reviews, err := r.reviewRepository.GetAll()
+	filter, err := newReviewFilter(query)
 	if err != nil {
-		fmt.Println("Error fetching reviews:", err)
 		return nil, err
 	}
-	return reviews, nil
+
+	filter.ModerationStatus = models.ModerationApproved
+	applyLanguagePreference(filter, query)
+
+	return r.listReviews(filter, query.IncludeTotal)
 }
 
-func (r *ReviewServiceImpl) GetReviewsByUserId(userId string) ([]*models.Review, error) {
+func (r *ReviewServiceImpl) GetReviewsByUserId(userId string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error) {
 	fmt.Println("Fetching reviews by user ID in ReviewService")
 
 	userIdInt, err := strconv.ParseInt(userId, 10, 64)
 	if err != nil {
 		fmt.Println("Error parsing user ID:", err)
-		return nil, fmt.Errorf("invalid user ID")
+		return nil, utils.NewBadRequestError("invalid user ID")
 	}
 
-	reviews, err := r.reviewRepository.GetByUserId(userIdInt)
+	filter, err := newReviewFilter(query)
 	if err != nil {
-		fmt.Println("Error fetching reviews by user ID:", err)
 		return nil, err
 	}
-	return reviews, nil
+	filter.UserId = &userIdInt
+
+	filter.ModerationStatus = models.ModerationApproved
+	applyLanguagePreference(filter, query)
+
+	return r.listReviews(filter, query.IncludeTotal)
 }
 
-func (r *ReviewServiceImpl) GetReviewsByHotelId(hotelId string) ([]*models.Review, error) {
+func (r *ReviewServiceImpl) GetReviewsByHotelId(hotelId string, query *dto.ReviewListQuery) (*dto.ReviewPageDTO, error) {
 	fmt.Println("Fetching reviews by hotel ID in ReviewService")
 
 	hotelIdInt, err := strconv.ParseInt(hotelId, 10, 64)
 	if err != nil {
 		fmt.Println("Error parsing hotel ID:", err)
-		return nil, fmt.Errorf("invalid hotel ID")
+		return nil, utils.NewBadRequestError("invalid hotel ID")
 	}
 
-	reviews, err := r.reviewRepository.GetByHotelId(hotelIdInt)
+	filter, err := newReviewFilter(query)
 	if err != nil {
-		fmt.Println("Error fetching reviews by hotel ID:", err)
 		return nil, err
 	}
-	return reviews, nil
+	filter.HotelId = &hotelIdInt
+
+	filter.ModerationStatus = models.ModerationApproved
+	applyLanguagePreference(filter, query)
+
+	return r.listReviews(filter, query.IncludeTotal)
 }
 
 func (r *ReviewServiceImpl) GetReviewsByBookingId(bookingId string) ([]*models.Review, error) {
@@ -169,5 +394,16 @@ func (r *ReviewServiceImpl) GetReviewsByBookingId(bookingId string) ([]*models.R
 		fmt.Println("Error fetching reviews by booking ID:", err)
 		return nil, err
 	}
-	return reviews, nil
+
+	approved := []*models.Review{}
+	for _, review := range reviews {
+		if review.ModerationStatus == models.ModerationApproved {
+			approved = append(approved, review)
+		}
+	}
+
+	if err := r.details.Attach(approved); err != nil {
+		return nil, err
+	}
+	return approved, nil
 }
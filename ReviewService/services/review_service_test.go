@@ -0,0 +1,120 @@
+package services
+
+import (
+	"ReviewService/clients"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckStayEligibility(t *testing.T) {
+	bookings := clients.NewInMemoryBookingClient(
+		&clients.Booking{Id: 1, UserId: 10, HotelId: 100, Status: clients.BookingStatusConfirmed},
+		&clients.Booking{Id: 2, UserId: 10, HotelId: 100, Status: clients.BookingStatusPending},
+		&clients.Booking{Id: 3, UserId: 10, HotelId: 100, Status: clients.BookingStatusCancelled},
+	)
+	service := &ReviewServiceImpl{bookingClient: bookings}
+
+	tests := []struct {
+		name      string
+		userId    int64
+		bookingId int64
+		hotelId   int64
+		status    int // 0 when the stay is eligible
+	}{
+		{"confirmed stay", 10, 1, 100, 0},
+		{"wrong user", 11, 1, 100, http.StatusForbidden},
+		{"wrong hotel", 10, 1, 101, http.StatusUnprocessableEntity},
+		{"pending booking", 10, 2, 100, http.StatusUnprocessableEntity},
+		{"cancelled booking", 10, 3, 100, http.StatusUnprocessableEntity},
+		{"missing booking", 10, 4, 100, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking, err := service.checkStayEligibility(tt.userId, tt.bookingId, tt.hotelId)
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("checkStayEligibility: %v", err)
+				}
+				if booking == nil || booking.Id != tt.bookingId {
+					t.Errorf("got booking %+v, want booking %d", booking, tt.bookingId)
+				}
+				return
+			}
+			if got := utils.StatusFromError(err, 0); got != tt.status {
+				t.Errorf("got status %d (%v), want %d", got, err, tt.status)
+			}
+		})
+	}
+}
+
+func TestCheckStayEligibilityWithBookingService(t *testing.T) {
+	const token = "internal-test-token"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(clients.InternalTokenHeader) != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/bookings/1":
+			w.Write([]byte(`{"id":1,"userId":10,"hotelId":100,"status":"CONFIRMED","createdAt":"2025-06-01T10:00:00.000Z"}`))
+		case "/api/v1/bookings/2":
+			// Slower than the client timeout
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"id":2,"userId":10,"hotelId":100,"status":"CONFIRMED"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := &ReviewServiceImpl{bookingClient: clients.NewHttpBookingClient(server.URL, token, 50*time.Millisecond)}
+
+	if _, err := service.checkStayEligibility(10, 1, 100); err != nil {
+		t.Errorf("confirmed stay: %v", err)
+	}
+	if _, err := service.checkStayEligibility(10, 404, 100); utils.StatusFromError(err, 0) != http.StatusUnprocessableEntity {
+		t.Errorf("booking unknown to BookingService: got %v, want 422", err)
+	}
+	if _, err := service.checkStayEligibility(10, 2, 100); utils.StatusFromError(err, 0) != http.StatusServiceUnavailable {
+		t.Errorf("BookingService timeout: got %v, want 503", err)
+	}
+
+	// Without the shared token BookingService refuses, which is not mistaken for a missing booking
+	unauthenticated := &ReviewServiceImpl{bookingClient: clients.NewHttpBookingClient(server.URL, "wrong-token", time.Second)}
+	if _, err := unauthenticated.checkStayEligibility(10, 1, 100); utils.StatusFromError(err, 0) != http.StatusServiceUnavailable {
+		t.Errorf("rejected internal token: got %v, want 503", err)
+	}
+}
+
+func TestRatingRangeErrorsAreBadRequests(t *testing.T) {
+	outOfRange := 6
+	service := &ReviewServiceImpl{reviewRepository: &photoReviews{review: &models.Review{Id: 1, UserId: 10}}}
+	author := &models.AuthUser{Id: 10}
+
+	creates := []*dto.CreateReviewRequestDTO{
+		{BookingId: 1, HotelId: 100, Comment: "Great stay", Rating: 0},
+		{BookingId: 1, HotelId: 100, Comment: "Great stay", Rating: 5, CategoryRatings: &dto.CategoryRatingsDTO{Cleanliness: &outOfRange}},
+	}
+	for _, payload := range creates {
+		if _, err := service.CreateReview(author, payload); utils.StatusFromError(err, 0) != http.StatusBadRequest {
+			t.Errorf("CreateReview got %v, want a 400", err)
+		}
+	}
+
+	updates := []*dto.UpdateReviewRequestDTO{
+		{Comment: "Great stay", Rating: 6},
+		{Comment: "Great stay", Rating: 5, CategoryRatings: &dto.CategoryRatingsDTO{Value: &outOfRange}},
+	}
+	for _, payload := range updates {
+		if _, err := service.UpdateReview("1", author, payload); utils.StatusFromError(err, 0) != http.StatusBadRequest {
+			t.Errorf("UpdateReview got %v, want a 400", err)
+		}
+	}
+}
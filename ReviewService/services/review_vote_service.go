@@ -0,0 +1,71 @@
+package services
+
+import (
+	db "ReviewService/db/repositories"
+	"ReviewService/dto"
+	"ReviewService/models"
+	"ReviewService/utils"
+	"fmt"
+	"strconv"
+)
+
+type ReviewVoteService interface {
+	Vote(reviewId string, voter *models.AuthUser, payload *dto.ReviewVoteRequestDTO) (*dto.ReviewVoteResponseDTO, error)
+}
+
+type ReviewVoteServiceImpl struct {
+	reviewRepository db.ReviewRepository
+	voteRepository   db.ReviewVoteRepository
+}
+
+func NewReviewVoteService(_reviewRepository db.ReviewRepository, _voteRepository db.ReviewVoteRepository) ReviewVoteService {
+	return &ReviewVoteServiceImpl{
+		reviewRepository: _reviewRepository,
+		voteRepository:   _voteRepository,
+	}
+}
+
+// Vote toggles the caller's helpful / not helpful vote on a published review.
+func (s *ReviewVoteServiceImpl) Vote(reviewId string, voter *models.AuthUser, payload *dto.ReviewVoteRequestDTO) (*dto.ReviewVoteResponseDTO, error) {
+	fmt.Println("Voting on review in ReviewVoteService")
+
+	idInt, err := strconv.ParseInt(reviewId, 10, 64)
+	if err != nil {
+		fmt.Println("Error parsing review ID:", err)
+		return nil, utils.NewBadRequestError("invalid review ID")
+	}
+
+	review, err := s.reviewRepository.GetByID(idInt)
+	if err != nil {
+		fmt.Println("Error fetching review:", err)
+		return nil, err
+	}
+	if review == nil || review.ModerationStatus != models.ModerationApproved {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("review with ID %d not found", idInt))
+	}
+	if review.UserId == voter.Id {
+		return nil, utils.NewForbiddenError("you cannot vote on your own review")
+	}
+
+	vote, err := s.voteRepository.ToggleVote(idInt, voter.Id, *payload.Helpful)
+	if err != nil {
+		fmt.Println("Error recording vote:", err)
+		return nil, err
+	}
+
+	updated, err := s.reviewRepository.GetByID(idInt)
+	if err != nil || updated == nil {
+		fmt.Println("Error fetching review after vote:", err)
+		return nil, fmt.Errorf("could not load vote totals")
+	}
+
+	result := &dto.ReviewVoteResponseDTO{
+		ReviewId:        idInt,
+		HelpfulCount:    updated.HelpfulCount,
+		NotHelpfulCount: updated.NotHelpfulCount,
+	}
+	if vote != nil {
+		result.Vote = &vote.Helpful
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor turns a position into an opaque, URL-safe cursor.
+func EncodeCursor(position any) (string, error) {
+	raw, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor reads a cursor produced by EncodeCursor into position.
+func DecodeCursor(cursor string, position any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return NewBadRequestError("invalid cursor")
+	}
+	if err := json.Unmarshal(raw, position); err != nil {
+		return NewBadRequestError("invalid cursor")
+	}
+	return nil
+}
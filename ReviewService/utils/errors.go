@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+)
+
+// AppError carries the HTTP status a service error should be reported with.
+type AppError struct {
+	StatusCode int
+	Message    string
+	Data       any // optional payload returned alongside the error, e.g. the conflicting resource
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+func NewBadRequestError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusBadRequest, Message: message}
+}
+
+func NewUnauthorizedError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusUnauthorized, Message: message}
+}
+
+func NewForbiddenError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusForbidden, Message: message}
+}
+
+func NewNotFoundError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusNotFound, Message: message}
+}
+
+func NewConflictError(message string, data any) *AppError {
+	return &AppError{StatusCode: http.StatusConflict, Message: message, Data: data}
+}
+
+func NewPayloadTooLargeError(message string, data any) *AppError {
+	return &AppError{StatusCode: http.StatusRequestEntityTooLarge, Message: message, Data: data}
+}
+
+func NewUnprocessableError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusUnprocessableEntity, Message: message}
+}
+
+func NewServiceUnavailableError(message string) *AppError {
+	return &AppError{StatusCode: http.StatusServiceUnavailable, Message: message}
+}
+
+// StatusFromError returns the status of an AppError, or fallback for any other error.
+func StatusFromError(err error, fallback int) int {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.StatusCode
+	}
+	return fallback
+}
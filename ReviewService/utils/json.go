@@ -2,6 +2,7 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -12,9 +13,10 @@ type SuccessResponse struct {
 }
 
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Error   string `json:"error"`
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Error   string      `json:"error"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 func WriteJsonSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
@@ -35,14 +37,21 @@ func WriteJsonErrorResponse(w http.ResponseWriter, statusCode int, message strin
 	w.WriteHeader(statusCode)
 
 	errorMessage := ""
+	var data interface{}
 	if err != nil {
 		errorMessage = err.Error()
+
+		var appErr *AppError
+		if errors.As(err, &appErr) {
+			data = appErr.Data
+		}
 	}
 
 	response := ErrorResponse{
 		Success: false,
 		Message: message,
 		Error:   errorMessage,
+		Data:    data,
 	}
 
 	json.NewEncoder(w).Encode(response)
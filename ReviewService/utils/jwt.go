@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"ReviewService/models"
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenVerifier validates an AuthInGo-issued token and returns the caller it was issued to.
+type TokenVerifier interface {
+	Verify(token string) (*models.AuthUser, error)
+}
+
+// JWTVerifier verifies tokens signed either with the shared HMAC secret (AuthInGo's default)
+// or with an RSA key pair. Tests can build one from a locally generated secret or key.
+type JWTVerifier struct {
+	hmacSecret []byte
+	publicKey  *rsa.PublicKey
+}
+
+func NewHMACVerifier(secret []byte) *JWTVerifier {
+	return &JWTVerifier{hmacSecret: secret}
+}
+
+func NewRSAVerifier(publicKey *rsa.PublicKey) *JWTVerifier {
+	return &JWTVerifier{publicKey: publicKey}
+}
+
+// NewTokenVerifier prefers an RSA public key file when a path is given and falls back to the HMAC secret.
+func NewTokenVerifier(publicKeyPath string, secret string) (TokenVerifier, error) {
+	if publicKeyPath != "" {
+		pem, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing JWT public key: %w", err)
+		}
+		return NewRSAVerifier(publicKey), nil
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("either a JWT public key or a JWT secret is required")
+	}
+	return NewHMACVerifier([]byte(secret)), nil
+}
+
+// Verify checks the signature and the claims. Tokens without an exp claim are rejected, so none stays valid forever.
+func (v *JWTVerifier) Verify(tokenString string) (*models.AuthUser, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if v.hmacSecret == nil {
+				return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+			}
+			return v.hmacSecret, nil
+		case *jwt.SigningMethodRSA:
+			if v.publicKey == nil {
+				return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+			}
+			return v.publicKey, nil
+		default:
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	userId, okId := claims["id"].(float64)
+	email, okEmail := claims["email"].(string)
+	if !okId || !okEmail {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return &models.AuthUser{
+		Id:          int64(userId),
+		Email:       email,
+		Roles:       stringClaims(claims["roles"]),
+		Permissions: stringClaims(claims["permissions"]),
+	}, nil
+}
+
+func stringClaims(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
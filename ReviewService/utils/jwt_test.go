@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("review-service-test-secret")
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	return key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+// userClaims are the claims AuthInGo puts into a login token.
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"id":          42,
+		"email":       "guest@example.com",
+		"roles":       []string{"user"},
+		"permissions": []string{"review:moderate"},
+		"iat":         time.Now().Unix(),
+		"exp":         time.Now().Add(15 * time.Minute).Unix(),
+	}
+}
+
+func TestVerifyValidHMACToken(t *testing.T) {
+	token := signToken(t, jwt.SigningMethodHS256, testSecret, userClaims())
+
+	user, err := NewHMACVerifier(testSecret).Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if user.Id != 42 || user.Email != "guest@example.com" {
+		t.Errorf("got user %d %q, want 42 guest@example.com", user.Id, user.Email)
+	}
+	if !slices.Equal(user.Roles, []string{"user"}) || !slices.Equal(user.Permissions, []string{"review:moderate"}) {
+		t.Errorf("got roles %v permissions %v", user.Roles, user.Permissions)
+	}
+}
+
+func TestVerifyValidRSAToken(t *testing.T) {
+	key := generateRSAKey(t)
+	token := signToken(t, jwt.SigningMethodRS256, key, userClaims())
+
+	user, err := NewRSAVerifier(&key.PublicKey).Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if user.Id != 42 {
+		t.Errorf("got user %d, want 42", user.Id)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	key := generateRSAKey(t)
+	otherKey := generateRSAKey(t)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPublicKey(t, &key.PublicKey)})
+
+	expired := userClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	noExpiry := userClaims()
+	delete(noExpiry, "exp")
+	notYetValid := userClaims()
+	notYetValid["nbf"] = time.Now().Add(time.Hour).Unix()
+	noId := userClaims()
+	delete(noId, "id")
+	noEmail := userClaims()
+	delete(noEmail, "email")
+	stringId := userClaims()
+	stringId["id"] = "42"
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, userClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("signing unsigned token: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+	}{
+		{"expired HMAC", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, expired)},
+		{"expired RSA", NewRSAVerifier(&key.PublicKey), signToken(t, jwt.SigningMethodRS256, key, expired)},
+		{"no expiry", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, noExpiry)},
+		{"not yet valid", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, notYetValid)},
+		{"wrong secret", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, []byte("other-secret"), userClaims())},
+		{"wrong RSA key", NewRSAVerifier(&key.PublicKey), signToken(t, jwt.SigningMethodRS256, otherKey, userClaims())},
+		{"RSA token for HMAC verifier", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodRS256, key, userClaims())},
+		{"HMAC token for RSA verifier", NewRSAVerifier(&key.PublicKey), signToken(t, jwt.SigningMethodHS256, testSecret, userClaims())},
+		// An attacker who knows the public key must not be able to use it as an HMAC secret
+		{"HMAC signed with public key", NewRSAVerifier(&key.PublicKey), signToken(t, jwt.SigningMethodHS256, publicPEM, userClaims())},
+		{"alg none", NewHMACVerifier(testSecret), unsigned},
+		{"ECDSA algorithm", NewHMACVerifier(testSecret), "eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9.eyJpZCI6NDJ9.c2ln"},
+		{"missing id", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, noId)},
+		{"missing email", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, noEmail)},
+		{"non-numeric id", NewHMACVerifier(testSecret), signToken(t, jwt.SigningMethodHS256, testSecret, stringId)},
+		{"malformed", NewHMACVerifier(testSecret), "not-a-jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if user, err := tt.verifier.Verify(tt.token); err == nil {
+				t.Errorf("Verify accepted the token for user %d", user.Id)
+			}
+		})
+	}
+}
+
+func TestNewTokenVerifier(t *testing.T) {
+	key := generateRSAKey(t)
+	path := filepath.Join(t.TempDir(), "jwt.pub")
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPublicKey(t, &key.PublicKey)})
+	if err := os.WriteFile(path, publicPEM, 0o600); err != nil {
+		t.Fatalf("writing public key: %v", err)
+	}
+
+	// The key file wins over the secret
+	verifier, err := NewTokenVerifier(path, string(testSecret))
+	if err != nil {
+		t.Fatalf("NewTokenVerifier: %v", err)
+	}
+	if _, err := verifier.Verify(signToken(t, jwt.SigningMethodRS256, key, userClaims())); err != nil {
+		t.Errorf("RSA token rejected: %v", err)
+	}
+	if _, err := verifier.Verify(signToken(t, jwt.SigningMethodHS256, testSecret, userClaims())); err == nil {
+		t.Error("HMAC token accepted by an RSA verifier")
+	}
+
+	if _, err := NewTokenVerifier("", ""); err == nil {
+		t.Error("NewTokenVerifier accepted neither a key nor a secret")
+	}
+	if _, err := NewTokenVerifier(filepath.Join(t.TempDir(), "missing.pub"), ""); err == nil {
+		t.Error("NewTokenVerifier accepted a missing key file")
+	}
+}
+
+func mustMarshalPublicKey(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshaling public key: %v", err)
+	}
+	return der
+}